@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	am "github.com/multiversx/mx-chain-vm-v1_4-go/scenarioexec"
@@ -24,18 +25,81 @@ func resolveArgument(exeDir string, arg string) (string, bool, error) {
 	return arg, fi.IsDir(), nil
 }
 
-func parseOptionFlags() *mc.RunScenarioOptions {
+func newExecutor() (mc.ScenarioExecutor, error) {
+	return am.NewVMTestExecutor()
+}
+
+// reportFiles are the files where the scenario reports are written, if set
+type reportFiles struct {
+	junitPath string
+	jsonPath  string
+}
+
+func parseOptionFlags() (*mc.RunScenarioOptions, *reportFiles, error) {
 	forceTraceGas := flag.Bool("force-trace-gas", false, "overrides the traceGas option in the scenarios")
+	parallel := flag.Int("parallel", 1, "number of scenario files run at the same time, when running a directory of scenarios sharing a gas schedule")
+	reportJUnit := flag.String("report-junit", "", "writes a JUnit XML report of the scenarios and their steps to this file")
+	reportJSON := flag.String("report-json", "", "writes a JSON report of the scenarios and their steps to this file")
+	run := flag.String("run", "", "only runs the scenarios whose name or path matches this regular expression")
+	tags := flag.String("tags", "", "only runs the scenarios having one of these comma-separated tags")
+	excludeTags := flag.String("exclude-tags", "", "skips the scenarios having one of these comma-separated tags")
 	flag.Parse()
 
-	return &mc.RunScenarioOptions{
+	options := &mc.RunScenarioOptions{
 		ForceTraceGas: *forceTraceGas,
+		Parallel:      *parallel,
+		IncludeTags:   splitTags(*tags),
+		ExcludeTags:   splitTags(*excludeTags),
+	}
+	if len(*run) > 0 {
+		runFilter, err := regexp.Compile(*run)
+		if err != nil {
+			return nil, nil, fmt.Errorf("bad -run expression: %w", err)
+		}
+		options.RunFilter = runFilter
 	}
+
+	return options, &reportFiles{
+		junitPath: *reportJUnit,
+		jsonPath:  *reportJSON,
+	}, nil
+}
+
+func splitTags(tags string) []string {
+	result := make([]string, 0)
+	for _, tag := range strings.Split(tags, ",") {
+		tag = strings.TrimSpace(tag)
+		if len(tag) > 0 {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+func writeReports(collector *mc.ReportCollector, files *reportFiles) error {
+	if len(files.junitPath) > 0 {
+		err := collector.WriteJUnitFile(files.junitPath)
+		if err != nil {
+			return err
+		}
+	}
+	if len(files.jsonPath) > 0 {
+		err := collector.WriteJSONFile(files.jsonPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 // ScenariosTestCLI provides the functionality for any scenarios test executor.
 func ScenariosTestCLI() {
-	options := parseOptionFlags()
+	options, reports, err := parseOptionFlags()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
 
 	// directory of this executable
 	exeDir, err := os.Getwd()
@@ -62,12 +126,15 @@ func ScenariosTestCLI() {
 	}
 
 	// execute
+	reportCollector := mc.NewReportCollector()
 	switch {
 	case isDir:
 		runner := mc.NewScenarioRunner(
 			executor,
 			mc.NewDefaultFileResolver(),
 		)
+		runner.ExecutorFactory = newExecutor
+		runner.AddReporter(reportCollector)
 		err = runner.RunAllJSONScenariosInDirectory(
 			jsonFilePath,
 			"",
@@ -79,6 +146,7 @@ func ScenariosTestCLI() {
 			executor,
 			mc.NewDefaultFileResolver(),
 		)
+		runner.AddReporter(reportCollector)
 		err = runner.RunSingleJSONScenario(jsonFilePath, options)
 	default:
 		runner := mc.NewTestRunner(
@@ -88,6 +156,12 @@ func ScenariosTestCLI() {
 		err = runner.RunSingleJSONTest(jsonFilePath)
 	}
 
+	reportErr := writeReports(reportCollector, reports)
+	if reportErr != nil {
+		fmt.Printf("ERROR: could not write the reports: %s\n", reportErr.Error())
+		os.Exit(1)
+	}
+
 	// print result
 	if err == nil {
 		fmt.Println("SUCCESS")
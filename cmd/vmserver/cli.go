@@ -1,10 +1,22 @@
 package main
 
 import (
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
 	"github.com/multiversx/mx-chain-vm-v1_4-go/vmserver"
 	"github.com/urfave/cli"
 )
 
+const (
+	currentBlockFlagsPrefix  = "block-"
+	previousBlockFlagsPrefix = "previous-block-"
+	noEnabledFlags           = "none"
+)
+
 func initializeCLI(facade *vmserver.DebugFacade) *cli.App {
 	app := cli.NewApp()
 	app.Name = "VM Debug"
@@ -19,6 +31,44 @@ func initializeCLI(facade *vmserver.DebugFacade) *cli.App {
 		Destination: &args.ServerAddress,
 	}
 
+	flagCacheWorlds := cli.BoolFlag{
+		Name:        "cache-worlds",
+		Usage:       "keep the worlds in memory, writing them to the database in the background",
+		Destination: &args.CacheWorlds,
+	}
+
+	flagFlushInterval := cli.DurationFlag{
+		Name:        "flush-interval",
+		Usage:       "how often the cached worlds are written to the database, 0 to write them only on shutdown",
+		Value:       time.Second,
+		Destination: &args.FlushInterval,
+	}
+
+	flagGateway := cli.BoolFlag{
+		Name:        "gateway",
+		Usage:       "also serve gateway-compatible routes under /gateway/:world, for the SDKs",
+		Destination: &args.Gateway,
+	}
+
+	flagGatewayDatabase := cli.StringFlag{
+		Name:        "gateway-database",
+		Usage:       "database of the worlds served through the gateway routes",
+		Value:       "./db",
+		Destination: &args.GatewayDatabase,
+	}
+
+	flagGatewayChainID := cli.StringFlag{
+		Name:        "gateway-chain-id",
+		Value:       vmserver.DefaultGatewayChainID,
+		Destination: &args.GatewayChainID,
+	}
+
+	flagGatewayCheckSignatures := cli.BoolFlag{
+		Name:        "gateway-check-signatures",
+		Usage:       "reject the transactions with a wrong signature or chain ID",
+		Destination: &args.GatewayCheckSignatures,
+	}
+
 	// Common for all actions
 	flagDatabase := cli.StringFlag{
 		Name:        "database",
@@ -40,13 +90,14 @@ func initializeCLI(facade *vmserver.DebugFacade) *cli.App {
 	flagContract := cli.StringFlag{
 		Required:    true,
 		Name:        "contract",
+		Usage:       "hex, bech32 or scenario expression (e.g. sc:counter)",
 		Destination: &args.ContractAddress,
 	}
 
 	flagImpersonated := cli.StringFlag{
 		Required:    true,
 		Name:        "impersonated",
-		Usage:       "",
+		Usage:       "hex, bech32 or scenario expression (e.g. address:alice)",
 		Destination: &args.Impersonated,
 	}
 
@@ -59,6 +110,7 @@ func initializeCLI(facade *vmserver.DebugFacade) *cli.App {
 	flagArguments := cli.StringSliceFlag{
 		Required: false,
 		Name:     "arguments",
+		Usage:    "hex, bech32 or scenario expressions (e.g. str:abc, u64:5, biguint:1000), can be repeated",
 		Value:    &args.Arguments,
 	}
 
@@ -77,6 +129,13 @@ func initializeCLI(facade *vmserver.DebugFacade) *cli.App {
 		Destination: &args.GasPrice,
 	}
 
+	flagESDTTransfers := cli.StringSliceFlag{
+		Required: false,
+		Name:     "esdt",
+		Usage:    "token:nonce:value, can be repeated for multi-ESDT transfers",
+		Value:    &args.ESDTTransfers,
+	}
+
 	// For deploy / upgrade
 	flagCode := cli.StringFlag{
 		Name:        "code",
@@ -97,6 +156,7 @@ func initializeCLI(facade *vmserver.DebugFacade) *cli.App {
 	flagAccountAddress := cli.StringFlag{
 		Required:    true,
 		Name:        "address",
+		Usage:       "hex, bech32 or scenario expression (e.g. address:alice)",
 		Destination: &args.AccountAddress,
 	}
 
@@ -114,7 +174,145 @@ func initializeCLI(facade *vmserver.DebugFacade) *cli.App {
 		Destination: &args.AccountNonce,
 	}
 
-	app.Flags = []cli.Flag{}
+	flagAccountESDT := cli.StringSliceFlag{
+		Required: false,
+		Name:     "esdt",
+		Usage:    "token:nonce:value[:attributesHex], can be repeated",
+		Value:    &args.AccountESDT,
+	}
+
+	flagAccountRoles := cli.StringSliceFlag{
+		Required: false,
+		Name:     "esdt-roles",
+		Usage:    "token:role[,role...], can be repeated",
+		Value:    &args.AccountRoles,
+	}
+
+	// For world snapshots / forks
+	flagSnapshot := cli.StringFlag{
+		Required:    true,
+		Name:        "snapshot",
+		Destination: &args.Snapshot,
+	}
+
+	flagNewWorld := cli.StringFlag{
+		Required:    true,
+		Name:        "new-world",
+		Destination: &args.NewWorld,
+	}
+
+	// For block actions
+	flagBlockAdvance := cli.Uint64Flag{
+		Name:        "advance",
+		Usage:       "number of blocks to advance, after setting the block infos",
+		Destination: &args.BlockAdvance,
+	}
+
+	flagBlockAutoAdvance := cli.BoolFlag{
+		Name:        "auto-advance",
+		Usage:       "advance one block before each run (--auto-advance=false to stop)",
+		Destination: &args.BlockAutoAdvance,
+	}
+
+	flagSecondsPerBlock := cli.Uint64Flag{
+		Name:        "seconds-per-block",
+		Usage:       "timestamp increment when advancing blocks",
+		Destination: &args.SecondsPerBlock,
+	}
+
+	// For world configuration
+	flagGasSchedule := cli.StringFlag{
+		Name:        "gas-schedule",
+		Usage:       "embedded gas schedule version: dummy, v3 or v4",
+		Destination: &args.GasSchedule,
+	}
+
+	flagGasSchedulePath := cli.StringFlag{
+		Name:        "gas-schedule-path",
+		Usage:       "path to a TOML gas schedule file",
+		Destination: &args.GasSchedulePath,
+	}
+
+	flagEnabledFlags := cli.StringSliceFlag{
+		Name:  "enabled-flags",
+		Usage: "flag name, * for all flags or " + noEnabledFlags + " to clear them, can be repeated",
+		Value: &args.EnabledFlags,
+	}
+
+	// For inspection actions
+	flagStorageKey := cli.StringFlag{
+		Required:    true,
+		Name:        "key",
+		Usage:       "hex or scenario expression (e.g. str:COUNTER) storage key",
+		Destination: &args.StorageKey,
+	}
+
+	flagTokenIdentifier := cli.StringFlag{
+		Required:    true,
+		Name:        "token",
+		Destination: &args.TokenIdentifier,
+	}
+
+	// For the transaction log
+	flagLogContract := cli.StringFlag{
+		Name:        "contract",
+		Usage:       "only the entries of this contract (hex, bech32 or scenario expression)",
+		Destination: &args.ContractAddress,
+	}
+
+	flagLogFunction := cli.StringFlag{
+		Name:        "function",
+		Usage:       "only the entries calling this function",
+		Destination: &args.Function,
+	}
+
+	flagLogEvent := cli.StringFlag{
+		Name:        "event",
+		Usage:       "only the entries logging this event identifier",
+		Destination: &args.EventIdentifier,
+	}
+
+	flagTxHash := cli.StringFlag{
+		Required:    true,
+		Name:        "tx-hash",
+		Destination: &args.TxHash,
+	}
+
+	// For recordings
+	flagScenarioName := cli.StringFlag{
+		Name:        "name",
+		Usage:       "the name of the scenario",
+		Destination: &args.ScenarioName,
+	}
+
+	flagCheckState := cli.BoolFlag{
+		Name:        "check-state",
+		Usage:       "end the scenario with a checkState step, holding the current state of the world",
+		Destination: &args.CheckState,
+	}
+
+	flagScenarioFile := cli.StringFlag{
+		Name:        "scenario-file",
+		Usage:       "also write the scenario to this .scen.json file",
+		Destination: &args.ScenarioFile,
+	}
+
+	flagWorldStore := cli.StringFlag{
+		Name:        "world-store",
+		Usage:       "where the worlds are kept: json (a file per world) or bolt (a key-value store, writing only the accounts changed by each request)",
+		Value:       vmserver.WorldStoreJSON,
+		Destination: &args.WorldStore,
+	}
+
+	app.Flags = []cli.Flag{flagWorldStore}
+
+	app.Before = func(context *cli.Context) error {
+		if args.WorldStore != vmserver.WorldStoreJSON {
+			facade = vmserver.NewDebugFacadeWithArgs(vmserver.ArgsNewDebugFacade{WorldStore: args.WorldStore})
+		}
+
+		return nil
+	}
 
 	app.Authors = []cli.Author{
 		{
@@ -128,18 +326,46 @@ func initializeCLI(facade *vmserver.DebugFacade) *cli.App {
 			Name:        "server",
 			Description: "start debug server",
 			Action: func(context *cli.Context) error {
-				server := vmserver.NewDebugServer(facade, args.ServerAddress)
-				return server.Start()
+				serverFacade := facade
+				if args.CacheWorlds {
+					serverFacade = vmserver.NewDebugFacadeWithArgs(vmserver.ArgsNewDebugFacade{
+						CacheWorlds:   true,
+						FlushInterval: args.FlushInterval,
+						WorldStore:    args.WorldStore,
+					})
+				}
+
+				server := vmserver.NewDebugServer(serverFacade, args.ServerAddress)
+				if args.Gateway {
+					server.EnableGateway(vmserver.ArgsGateway{
+						DatabasePath:    args.GatewayDatabase,
+						ChainID:         args.GatewayChainID,
+						CheckSignatures: args.GatewayCheckSignatures,
+					})
+				}
+
+				return startServer(server, serverFacade)
 			},
 			Flags: []cli.Flag{
 				flagServerAddress,
+				flagCacheWorlds,
+				flagFlushInterval,
+				flagGateway,
+				flagGatewayDatabase,
+				flagGatewayChainID,
+				flagGatewayCheckSignatures,
 			},
 		},
 		{
 			Name:        "deploy",
 			Description: "deploy a smart contract",
 			Action: func(context *cli.Context) error {
-				_, err := facade.DeploySmartContract(args.toDeployRequest())
+				request, err := args.toDeployRequest()
+				if err != nil {
+					return err
+				}
+
+				_, err = facade.DeploySmartContract(request)
 				return err
 			},
 			Flags: []cli.Flag{
@@ -154,13 +380,19 @@ func initializeCLI(facade *vmserver.DebugFacade) *cli.App {
 				flagValue,
 				flagGasLimit,
 				flagGasPrice,
+				flagESDTTransfers,
 			},
 		},
 		{
 			Name:        "upgrade",
 			Description: "upgrade smart contract",
 			Action: func(context *cli.Context) error {
-				_, err := facade.UpgradeSmartContract(args.toUpgradeRequest())
+				request, err := args.toUpgradeRequest()
+				if err != nil {
+					return err
+				}
+
+				_, err = facade.UpgradeSmartContract(request)
 				return err
 			},
 			Flags: []cli.Flag{
@@ -176,13 +408,19 @@ func initializeCLI(facade *vmserver.DebugFacade) *cli.App {
 				flagValue,
 				flagGasLimit,
 				flagGasPrice,
+				flagESDTTransfers,
 			},
 		},
 		{
 			Name:        "run",
 			Description: "run smart contract",
 			Action: func(context *cli.Context) error {
-				_, err := facade.RunSmartContract(args.toRunRequest())
+				request, err := args.toRunRequest()
+				if err != nil {
+					return err
+				}
+
+				_, err = facade.RunSmartContract(request)
 				return err
 			},
 			Flags: []cli.Flag{
@@ -196,13 +434,19 @@ func initializeCLI(facade *vmserver.DebugFacade) *cli.App {
 				flagValue,
 				flagGasLimit,
 				flagGasPrice,
+				flagESDTTransfers,
 			},
 		},
 		{
 			Name:        "query",
 			Description: "query smart contract",
 			Action: func(context *cli.Context) error {
-				_, err := facade.QuerySmartContract(args.toQueryRequest())
+				request, err := args.toQueryRequest()
+				if err != nil {
+					return err
+				}
+
+				_, err = facade.QuerySmartContract(request)
 				return err
 			},
 			Flags: []cli.Flag{
@@ -220,7 +464,12 @@ func initializeCLI(facade *vmserver.DebugFacade) *cli.App {
 			Name:        "create-account",
 			Description: "create account",
 			Action: func(context *cli.Context) error {
-				_, err := facade.CreateAccount(args.toCreateAccountRequest())
+				request, err := args.toCreateAccountRequest()
+				if err != nil {
+					return err
+				}
+
+				_, err = facade.CreateAccount(request)
 				return err
 			},
 			Flags: []cli.Flag{
@@ -230,9 +479,259 @@ func initializeCLI(facade *vmserver.DebugFacade) *cli.App {
 				flagAccountAddress,
 				flagAccountBalance,
 				flagAccountNonce,
+				flagAccountESDT,
+				flagAccountRoles,
+			},
+		},
+		{
+			Name:        "block",
+			Description: "set the current / previous block infos of a world, or advance blocks",
+			Action: func(context *cli.Context) error {
+				_, err := facade.SetBlock(args.toBlockRequest(context))
+				return err
+			},
+			Flags: append([]cli.Flag{
+				flagOutcome,
+				flagWorld,
+				flagDatabase,
+				flagBlockAdvance,
+				flagBlockAutoAdvance,
+				flagSecondsPerBlock,
+			}, append(
+				blockInfoFlags(currentBlockFlagsPrefix, &args.CurrentBlock),
+				blockInfoFlags(previousBlockFlagsPrefix, &args.PreviousBlock)...,
+			)...),
+		},
+		{
+			Name:        "config",
+			Description: "set the gas schedule and the enabled flags of a world",
+			Action: func(context *cli.Context) error {
+				_, err := facade.SetWorldConfig(args.toWorldConfigRequest(context))
+				return err
+			},
+			Flags: []cli.Flag{
+				flagOutcome,
+				flagWorld,
+				flagDatabase,
+				flagGasSchedule,
+				flagGasSchedulePath,
+				flagEnabledFlags,
+			},
+		},
+		{
+			Name:        "snapshot",
+			Description: "save the state of a world under a snapshot name",
+			Action: func(context *cli.Context) error {
+				_, err := facade.SnapshotWorld(args.toSnapshotWorldRequest())
+				return err
+			},
+			Flags: []cli.Flag{
+				flagOutcome,
+				flagWorld,
+				flagDatabase,
+				flagSnapshot,
+			},
+		},
+		{
+			Name:        "fork",
+			Description: "copy the state of a world into a new world",
+			Action: func(context *cli.Context) error {
+				_, err := facade.ForkWorld(args.toForkWorldRequest())
+				return err
+			},
+			Flags: []cli.Flag{
+				flagOutcome,
+				flagWorld,
+				flagDatabase,
+				flagNewWorld,
+			},
+		},
+		{
+			Name:        "revert",
+			Description: "bring a world back to a snapshot",
+			Action: func(context *cli.Context) error {
+				_, err := facade.RevertWorld(args.toRevertWorldRequest())
+				return err
+			},
+			Flags: []cli.Flag{
+				flagOutcome,
+				flagWorld,
+				flagDatabase,
+				flagSnapshot,
+			},
+		},
+		{
+			Name:        "accounts",
+			Description: "show all accounts of a world",
+			Action: func(context *cli.Context) error {
+				_, err := facade.GetAccounts(args.toAccountsRequest())
+				return err
+			},
+			Flags: []cli.Flag{
+				flagOutcome,
+				flagWorld,
+				flagDatabase,
+			},
+		},
+		{
+			Name:        "account",
+			Description: "show an account",
+			Action: func(context *cli.Context) error {
+				_, err := facade.GetAccount(args.toAccountRequest())
+				return err
+			},
+			Flags: []cli.Flag{
+				flagOutcome,
+				flagWorld,
+				flagDatabase,
+				flagAccountAddress,
+			},
+		},
+		{
+			Name:        "storage",
+			Description: "show a storage value of an account",
+			Action: func(context *cli.Context) error {
+				_, err := facade.GetStorage(args.toStorageRequest())
+				return err
+			},
+			Flags: []cli.Flag{
+				flagOutcome,
+				flagWorld,
+				flagDatabase,
+				flagAccountAddress,
+				flagStorageKey,
+			},
+		},
+		{
+			Name:        "esdt",
+			Description: "show an ESDT token held by an account",
+			Action: func(context *cli.Context) error {
+				_, err := facade.GetESDT(args.toESDTRequest())
+				return err
+			},
+			Flags: []cli.Flag{
+				flagOutcome,
+				flagWorld,
+				flagDatabase,
+				flagAccountAddress,
+				flagTokenIdentifier,
+			},
+		},
+		{
+			Name:        "transactions",
+			Description: "show the transaction log of a world",
+			Action: func(context *cli.Context) error {
+				_, err := facade.GetTransactionLog(args.toTransactionLogRequest())
+				return err
+			},
+			Flags: []cli.Flag{
+				flagOutcome,
+				flagWorld,
+				flagDatabase,
+				flagLogContract,
+				flagLogFunction,
+				flagLogEvent,
+			},
+		},
+		{
+			Name:        "transaction",
+			Description: "show an entry of the transaction log of a world",
+			Action: func(context *cli.Context) error {
+				_, err := facade.GetTransactionLogEntry(args.toTransactionLogEntryRequest())
+				return err
+			},
+			Flags: []cli.Flag{
+				flagOutcome,
+				flagWorld,
+				flagDatabase,
+				flagTxHash,
+			},
+		},
+		{
+			Name:        "record",
+			Description: "start recording the requests executed on a world, from its current state",
+			Action: func(context *cli.Context) error {
+				_, err := facade.StartRecording(args.toRecordingRequest())
+				return err
+			},
+			Flags: []cli.Flag{
+				flagOutcome,
+				flagWorld,
+				flagDatabase,
+			},
+		},
+		{
+			Name:        "scenario",
+			Description: "export the recording of a world as a scenario",
+			Action: func(context *cli.Context) error {
+				response, err := facade.ExportScenario(args.toScenarioRequest())
+				if err != nil || len(args.ScenarioFile) == 0 {
+					return err
+				}
+
+				return ioutil.WriteFile(args.ScenarioFile, append(response.Scenario, '\n'), 0644)
+			},
+			Flags: []cli.Flag{
+				flagOutcome,
+				flagWorld,
+				flagDatabase,
+				flagScenarioName,
+				flagCheckState,
+				flagScenarioFile,
 			},
 		},
 	}
 
 	return app
 }
+
+func blockInfoFlags(prefix string, blockInfo *cliBlockInfo) []cli.Flag {
+	return []cli.Flag{
+		cli.Uint64Flag{
+			Name:        prefix + "timestamp",
+			Destination: &blockInfo.Timestamp,
+		},
+		cli.Uint64Flag{
+			Name:        prefix + "nonce",
+			Destination: &blockInfo.Nonce,
+		},
+		cli.Uint64Flag{
+			Name:        prefix + "round",
+			Destination: &blockInfo.Round,
+		},
+		cli.Uint64Flag{
+			Name:        prefix + "epoch",
+			Destination: &blockInfo.Epoch,
+		},
+		cli.StringFlag{
+			Name:        prefix + "random-seed",
+			Usage:       "hex, 48 bytes",
+			Destination: &blockInfo.RandomSeed,
+		},
+	}
+}
+
+// startServer runs the debug server until it fails or the process is interrupted, then writes the cached worlds
+func startServer(server *vmserver.DebugServer, facade *vmserver.DebugFacade) error {
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- server.Start()
+	}()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+
+	var err error
+	select {
+	case err = <-errChan:
+	case sig := <-sigChan:
+		log.Info("shutting down", "signal", sig.String())
+	}
+
+	closeErr := facade.Close()
+	if err != nil {
+		return err
+	}
+
+	return closeErr
+}
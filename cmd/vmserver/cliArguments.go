@@ -1,16 +1,37 @@
 package main
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/multiversx/mx-chain-vm-v1_4-go/vmserver"
 	"github.com/urfave/cli"
 )
 
+type cliBlockInfo struct {
+	Timestamp  uint64
+	Nonce      uint64
+	Round      uint64
+	Epoch      uint64
+	RandomSeed string
+}
+
 type cliArguments struct {
 	// Common arguments
 	ServerAddress string
 	Database      string
 	World         string
 	Outcome       string
+	WorldStore    string
+	// For the server
+	CacheWorlds            bool
+	FlushInterval          time.Duration
+	Gateway                bool
+	GatewayDatabase        string
+	GatewayChainID         string
+	GatewayCheckSignatures bool
 	// For contract-related actions
 	Impersonated    string
 	ContractAddress string
@@ -23,35 +44,80 @@ type cliArguments struct {
 	Value           string
 	GasLimit        uint64
 	GasPrice        uint64
+	ESDTTransfers   cli.StringSlice
 	// For blockchain-related action
 	AccountAddress string
 	AccountBalance string
 	AccountNonce   uint64
+	AccountESDT    cli.StringSlice
+	AccountRoles   cli.StringSlice
+	// For world-related actions
+	Snapshot string
+	NewWorld string
+	// For block actions
+	CurrentBlock     cliBlockInfo
+	PreviousBlock    cliBlockInfo
+	BlockAdvance     uint64
+	BlockAutoAdvance bool
+	SecondsPerBlock  uint64
+	// For world configuration
+	GasSchedule     string
+	GasSchedulePath string
+	EnabledFlags    cli.StringSlice
+	// For inspection actions
+	StorageKey      string
+	TokenIdentifier string
+	// For the transaction log
+	EventIdentifier string
+	TxHash          string
+	// For recordings
+	ScenarioName string
+	CheckState   bool
+	ScenarioFile string
 }
 
-func (args *cliArguments) toDeployRequest() vmserver.DeployRequest {
+func (args *cliArguments) toDeployRequest() (vmserver.DeployRequest, error) {
 	request := &vmserver.DeployRequest{}
-	args.populateDeployRequest(request)
+	err := args.populateDeployRequest(request)
 
-	return *request
+	return *request, err
 }
 
-func (args *cliArguments) populateDeployRequest(request *vmserver.DeployRequest) {
-	args.populateContractRequestBase(&request.ContractRequestBase)
+func (args *cliArguments) populateDeployRequest(request *vmserver.DeployRequest) error {
+	err := args.populateContractRequestBase(&request.ContractRequestBase)
+	if err != nil {
+		return err
+	}
 
 	request.CodeHex = args.Code
 	request.CodePath = args.CodePath
 	request.CodeMetadata = args.CodeMetadata
 	request.ArgumentsHex = args.Arguments
+	return nil
 }
 
-func (args *cliArguments) populateContractRequestBase(request *vmserver.ContractRequestBase) {
+func (args *cliArguments) populateContractRequestBase(request *vmserver.ContractRequestBase) error {
 	args.populateRequestBase(&request.RequestBase)
 
 	request.ImpersonatedHex = args.Impersonated
 	request.Value = args.Value
 	request.GasLimit = args.GasLimit
 	request.GasPrice = args.GasPrice
+
+	for _, transferArg := range args.ESDTTransfers {
+		tokenIdentifier, nonce, value, _, err := parseESDTArgument(transferArg, 0)
+		if err != nil {
+			return err
+		}
+
+		request.ESDTTransfers = append(request.ESDTTransfers, &vmserver.ESDTTransfer{
+			TokenIdentifier: tokenIdentifier,
+			Nonce:           nonce,
+			Value:           value,
+		})
+	}
+
+	return nil
 }
 
 func (args *cliArguments) populateRequestBase(request *vmserver.RequestBase) {
@@ -60,42 +126,254 @@ func (args *cliArguments) populateRequestBase(request *vmserver.RequestBase) {
 	request.Outcome = args.Outcome
 }
 
-func (args *cliArguments) toUpgradeRequest() vmserver.UpgradeRequest {
+func (args *cliArguments) toUpgradeRequest() (vmserver.UpgradeRequest, error) {
 	request := &vmserver.UpgradeRequest{}
-	args.populateDeployRequest(&request.DeployRequest)
+	err := args.populateDeployRequest(&request.DeployRequest)
 
 	request.ContractAddressHex = args.ContractAddress
-	return *request
+	return *request, err
 }
 
-func (args *cliArguments) toRunRequest() vmserver.RunRequest {
+func (args *cliArguments) toRunRequest() (vmserver.RunRequest, error) {
 	request := &vmserver.RunRequest{}
-	args.populateRunRequest(request)
+	err := args.populateRunRequest(request)
 
-	return *request
+	return *request, err
 }
 
-func (args *cliArguments) populateRunRequest(request *vmserver.RunRequest) {
-	args.populateContractRequestBase(&request.ContractRequestBase)
+func (args *cliArguments) populateRunRequest(request *vmserver.RunRequest) error {
+	err := args.populateContractRequestBase(&request.ContractRequestBase)
+	if err != nil {
+		return err
+	}
 
 	request.ContractAddressHex = args.ContractAddress
 	request.Function = args.Function
 	request.ArgumentsHex = args.Arguments
+	return nil
 }
 
-func (args *cliArguments) toQueryRequest() vmserver.QueryRequest {
+func (args *cliArguments) toQueryRequest() (vmserver.QueryRequest, error) {
 	request := &vmserver.QueryRequest{}
-	args.populateRunRequest(&request.RunRequest)
+	err := args.populateRunRequest(&request.RunRequest)
 
-	return *request
+	return *request, err
 }
 
-func (args *cliArguments) toCreateAccountRequest() vmserver.CreateAccountRequest {
+func (args *cliArguments) toCreateAccountRequest() (vmserver.CreateAccountRequest, error) {
 	request := &vmserver.CreateAccountRequest{}
 	args.populateRequestBase(&request.RequestBase)
 
 	request.AddressHex = args.AccountAddress
 	request.Balance = args.AccountBalance
 	request.Nonce = args.AccountNonce
+
+	for _, esdtArg := range args.AccountESDT {
+		tokenIdentifier, nonce, value, optional, err := parseESDTArgument(esdtArg, 1)
+		if err != nil {
+			return *request, err
+		}
+
+		accountESDT := &vmserver.AccountESDT{
+			TokenIdentifier: tokenIdentifier,
+			Nonce:           nonce,
+			Value:           value,
+		}
+		if len(optional) > 0 {
+			accountESDT.AttributesHex = optional[0]
+		}
+		request.ESDT = append(request.ESDT, accountESDT)
+	}
+
+	for _, rolesArg := range args.AccountRoles {
+		parts := strings.SplitN(rolesArg, ":", 2)
+		if len(parts) != 2 {
+			return *request, fmt.Errorf("invalid ESDT roles %s, expected token:role,role", rolesArg)
+		}
+
+		request.ESDT = append(request.ESDT, &vmserver.AccountESDT{
+			TokenIdentifier: parts[0],
+			Roles:           strings.Split(parts[1], ","),
+		})
+	}
+
+	return *request, nil
+}
+
+// parseESDTArgument parses a token:nonce:value argument, followed by at most maxOptional optional fields
+func parseESDTArgument(arg string, maxOptional int) (string, uint64, string, []string, error) {
+	parts := strings.Split(arg, ":")
+	if len(parts) < 3 || len(parts) > 3+maxOptional {
+		return "", 0, "", nil, fmt.Errorf("invalid ESDT %s, expected token:nonce:value", arg)
+	}
+
+	nonce, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return "", 0, "", nil, fmt.Errorf("invalid ESDT nonce in %s: %w", arg, err)
+	}
+
+	return parts[0], nonce, parts[2], parts[3:], nil
+}
+
+func (args *cliArguments) toSnapshotWorldRequest() vmserver.SnapshotWorldRequest {
+	request := &vmserver.SnapshotWorldRequest{}
+	args.populateRequestBase(&request.RequestBase)
+
+	request.Snapshot = args.Snapshot
+	return *request
+}
+
+func (args *cliArguments) toForkWorldRequest() vmserver.ForkWorldRequest {
+	request := &vmserver.ForkWorldRequest{}
+	args.populateRequestBase(&request.RequestBase)
+
+	request.NewWorld = args.NewWorld
+	return *request
+}
+
+func (args *cliArguments) toRevertWorldRequest() vmserver.RevertWorldRequest {
+	request := &vmserver.RevertWorldRequest{}
+	args.populateRequestBase(&request.RequestBase)
+
+	request.Snapshot = args.Snapshot
+	return *request
+}
+
+func (args *cliArguments) toAccountsRequest() vmserver.AccountsRequest {
+	request := &vmserver.AccountsRequest{}
+	args.populateRequestBase(&request.RequestBase)
+
+	return *request
+}
+
+func (args *cliArguments) toTransactionLogRequest() vmserver.TransactionLogRequest {
+	request := &vmserver.TransactionLogRequest{}
+	args.populateRequestBase(&request.RequestBase)
+	request.ContractHex = args.ContractAddress
+	request.Function = args.Function
+	request.EventIdentifier = args.EventIdentifier
+
+	return *request
+}
+
+func (args *cliArguments) toTransactionLogEntryRequest() vmserver.TransactionLogEntryRequest {
+	request := &vmserver.TransactionLogEntryRequest{}
+	args.populateRequestBase(&request.RequestBase)
+	request.TxHash = args.TxHash
+
+	return *request
+}
+
+func (args *cliArguments) toRecordingRequest() vmserver.RecordingRequest {
+	request := &vmserver.RecordingRequest{}
+	args.populateRequestBase(&request.RequestBase)
+
+	return *request
+}
+
+func (args *cliArguments) toScenarioRequest() vmserver.ScenarioRequest {
+	request := &vmserver.ScenarioRequest{}
+	args.populateRequestBase(&request.RequestBase)
+	request.Name = args.ScenarioName
+	request.CheckState = args.CheckState
+
+	return *request
+}
+
+func (args *cliArguments) toAccountRequest() vmserver.AccountRequest {
+	request := &vmserver.AccountRequest{}
+	args.populateAccountRequest(request)
+
+	return *request
+}
+
+func (args *cliArguments) populateAccountRequest(request *vmserver.AccountRequest) {
+	args.populateRequestBase(&request.RequestBase)
+
+	request.AddressHex = args.AccountAddress
+}
+
+func (args *cliArguments) toStorageRequest() vmserver.StorageRequest {
+	request := &vmserver.StorageRequest{}
+	args.populateAccountRequest(&request.AccountRequest)
+
+	request.Key = args.StorageKey
+	return *request
+}
+
+func (args *cliArguments) toESDTRequest() vmserver.ESDTRequest {
+	request := &vmserver.ESDTRequest{}
+	args.populateAccountRequest(&request.AccountRequest)
+
+	request.TokenIdentifier = args.TokenIdentifier
 	return *request
 }
+
+func (args *cliArguments) toBlockRequest(context *cli.Context) vmserver.BlockRequest {
+	request := &vmserver.BlockRequest{}
+	args.populateRequestBase(&request.RequestBase)
+
+	request.Current = args.CurrentBlock.toBlockInfoRequest(context, currentBlockFlagsPrefix)
+	request.Previous = args.PreviousBlock.toBlockInfoRequest(context, previousBlockFlagsPrefix)
+	request.Advance = args.BlockAdvance
+	if context.IsSet("auto-advance") {
+		request.AutoAdvance = &args.BlockAutoAdvance
+	}
+	if context.IsSet("seconds-per-block") {
+		request.SecondsPerBlock = &args.SecondsPerBlock
+	}
+
+	return *request
+}
+
+func (args *cliArguments) toWorldConfigRequest(context *cli.Context) vmserver.WorldConfigRequest {
+	request := &vmserver.WorldConfigRequest{}
+	args.populateRequestBase(&request.RequestBase)
+
+	request.GasSchedule = args.GasSchedule
+	request.GasSchedulePath = args.GasSchedulePath
+	if context.IsSet("enabled-flags") {
+		enabledFlags := make([]string, 0, len(args.EnabledFlags))
+		for _, flag := range args.EnabledFlags {
+			if flag != noEnabledFlags {
+				enabledFlags = append(enabledFlags, flag)
+			}
+		}
+		request.EnabledFlags = &enabledFlags
+	}
+
+	return *request
+}
+
+// toBlockInfoRequest only sets the fields given on the command line, returns nil if none was given
+func (blockInfo *cliBlockInfo) toBlockInfoRequest(context *cli.Context, prefix string) *vmserver.BlockInfoRequest {
+	request := &vmserver.BlockInfoRequest{}
+	isSet := false
+
+	if context.IsSet(prefix + "timestamp") {
+		request.Timestamp = &blockInfo.Timestamp
+		isSet = true
+	}
+	if context.IsSet(prefix + "nonce") {
+		request.Nonce = &blockInfo.Nonce
+		isSet = true
+	}
+	if context.IsSet(prefix + "round") {
+		request.Round = &blockInfo.Round
+		isSet = true
+	}
+	if context.IsSet(prefix + "epoch") {
+		epoch := uint32(blockInfo.Epoch)
+		request.Epoch = &epoch
+		isSet = true
+	}
+	if context.IsSet(prefix + "random-seed") {
+		request.RandomSeedHex = &blockInfo.RandomSeed
+		isSet = true
+	}
+
+	if !isSet {
+		return nil
+	}
+	return request
+}
@@ -11,6 +11,7 @@ type GasCost struct {
 	ManagedBufferAPICost ManagedBufferAPICost
 	CryptoAPICost        CryptoAPICost
 	WASMOpcodeCost       WASMOpcodeCost
+	PluginAPICost        PluginAPICost
 }
 
 type BaseOperationCost struct {
@@ -210,6 +211,11 @@ type ManagedBufferAPICost struct {
 	MBufferSetRandom          uint64
 }
 
+type PluginAPICost struct {
+	PluginCall            uint64
+	PluginDataCopyPerByte uint64
+}
+
 type WASMOpcodeCost struct {
 	Unreachable            uint32
 	Nop                    uint32
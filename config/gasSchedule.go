@@ -11,6 +11,10 @@ const GasValueForTests = 1
 
 var AsyncCallbackGasLockForTests = uint64(100_000)
 
+// DefaultPluginCallCost is the base cost of a plugin call, used when the gas
+// schedule does not contain a PluginAPICost section
+const DefaultPluginCallCost = 100_000
+
 // GasScheduleMap (alias) is the map for gas schedule
 type GasScheduleMap = map[string]map[string]uint64
 
@@ -103,6 +107,22 @@ func CreateGasConfig(gasMap GasScheduleMap) (*GasCost, error) {
 		return nil, err
 	}
 
+	pluginGasMap, ok := gasMap["PluginAPICost"]
+	if !ok {
+		pluginGasMap = makeDefaultPluginAPICosts(baseOps)
+	}
+
+	pluginOps := &PluginAPICost{}
+	err = mapstructure.Decode(pluginGasMap, pluginOps)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkForZeroUint64Fields(*pluginOps)
+	if err != nil {
+		return nil, err
+	}
+
 	gasCost := &GasCost{
 		BaseOperationCost:    *baseOps,
 		BigIntAPICost:        *bigIntOps,
@@ -112,11 +132,22 @@ func CreateGasConfig(gasMap GasScheduleMap) (*GasCost, error) {
 		CryptoAPICost:        *cryptOps,
 		ManagedBufferAPICost: *MBufferOps,
 		WASMOpcodeCost:       *opcodeCosts,
+		PluginAPICost:        *pluginOps,
 	}
 
 	return gasCost, nil
 }
 
+// makeDefaultPluginAPICosts keeps gas schedules that predate VM plugins usable,
+// charging plugin data like any other data copy
+func makeDefaultPluginAPICosts(baseOps *BaseOperationCost) map[string]uint64 {
+	gasMap := make(map[string]uint64)
+	gasMap["PluginCall"] = DefaultPluginCallCost
+	gasMap["PluginDataCopyPerByte"] = baseOps.DataCopyPerByte
+
+	return gasMap
+}
+
 func checkForZeroUint64Fields(arg interface{}) error {
 	v := reflect.ValueOf(arg)
 	for i := 0; i < v.NumField(); i++ {
@@ -148,6 +179,7 @@ func FillGasMap(gasMap GasScheduleMap, value, asyncCallbackGasLock uint64) GasSc
 	gasMap["BigFloatAPICost"] = FillGasMapBigFloatAPICosts(value)
 	gasMap["CryptoAPICost"] = FillGasMapCryptoAPICosts(value)
 	gasMap["ManagedBufferAPICost"] = FillGasMapManagedBufferAPICosts(value)
+	gasMap["PluginAPICost"] = FillGasMapPluginAPICosts(value)
 	gasMap["WASMOpcodeCost"] = FillGasMapWASMOpcodeValues(value)
 
 	return gasMap
@@ -396,6 +428,14 @@ func FillGasMapManagedBufferAPICosts(value uint64) map[string]uint64 {
 	return gasMap
 }
 
+func FillGasMapPluginAPICosts(value uint64) map[string]uint64 {
+	gasMap := make(map[string]uint64)
+	gasMap["PluginCall"] = value
+	gasMap["PluginDataCopyPerByte"] = value
+
+	return gasMap
+}
+
 func FillGasMapWASMOpcodeValues(value uint64) map[string]uint64 {
 	gasMap := make(map[string]uint64)
 	gasMap["Unreachable"] = value
@@ -80,3 +80,22 @@ func TestDecode_ZeroGasCostError(t *testing.T) {
 	err = checkForZeroUint64Fields(*wasmCosts)
 	assert.Error(t, err)
 }
+
+func TestCreateGasConfig_PluginAPICost(t *testing.T) {
+	gasMap := MakeGasMap(7, 1)
+
+	gasCost, err := CreateGasConfig(gasMap)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(7), gasCost.PluginAPICost.PluginCall)
+	assert.Equal(t, uint64(7), gasCost.PluginAPICost.PluginDataCopyPerByte)
+
+	delete(gasMap, "PluginAPICost")
+	gasCost, err = CreateGasConfig(gasMap)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(DefaultPluginCallCost), gasCost.PluginAPICost.PluginCall)
+	assert.Equal(t, gasCost.BaseOperationCost.DataCopyPerByte, gasCost.PluginAPICost.PluginDataCopyPerByte)
+
+	gasMap["PluginAPICost"] = map[string]uint64{"PluginCall": 1}
+	_, err = CreateGasConfig(gasMap)
+	assert.Error(t, err)
+}
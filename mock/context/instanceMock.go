@@ -16,6 +16,7 @@ type mockMethod func() *InstanceMock
 type InstanceMock struct {
 	Code            []byte
 	Exports         wasmer.ExportsMap
+	Signatures      wasmer.ExportSignaturesMap
 	DefaultErrors   map[string]error
 	Methods         map[string]mockMethod
 	Points          uint64
@@ -34,6 +35,7 @@ func NewInstanceMock(code []byte) *InstanceMock {
 	return &InstanceMock{
 		Code:            code,
 		Exports:         make(wasmer.ExportsMap),
+		Signatures:      make(wasmer.ExportSignaturesMap),
 		DefaultErrors:   make(map[string]error),
 		Methods:         make(map[string]mockMethod),
 		Points:          0,
@@ -79,6 +81,11 @@ func (instance *InstanceMock) CallFunction(funcName string) (wasmer.Value, error
 	return wasmer.Void(), err
 }
 
+// CallFunctionWithArgs mocked method
+func (instance *InstanceMock) CallFunctionWithArgs(funcName string, _ ...interface{}) (wasmer.Value, error) {
+	return instance.CallFunction(funcName)
+}
+
 // HasMemory mocked method
 func (instance *InstanceMock) HasMemory() bool {
 	return true
@@ -148,6 +155,11 @@ func (instance *InstanceMock) GetSignature(functionName string) (*wasmer.Exporte
 		return nil, false
 	}
 
+	signature, ok := instance.Signatures[functionName]
+	if ok {
+		return signature, true
+	}
+
 	return &wasmer.ExportedFunctionSignature{
 		InputArity:  0,
 		OutputArity: 0,
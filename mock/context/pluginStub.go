@@ -0,0 +1,78 @@
+package mock
+
+import (
+	"github.com/multiversx/mx-chain-vm-v1_4-go/vmhost"
+)
+
+var _ vmhost.Plugin = (*PluginStub)(nil)
+var _ vmhost.DeterministicPlugin = (*PluginStub)(nil)
+var _ vmhost.StatefulPlugin = (*PluginStub)(nil)
+
+// PluginStub is an in-process VM plugin used in tests to check the calls made by contracts through mxPlugCall
+type PluginStub struct {
+	PluginName     string
+	PluginMethods  []vmhost.PluginMethod
+	Deterministic  bool
+	CallCalled     func(callCtx *vmhost.PluginCallContext, methodName string, args []byte) ([]byte, error)
+	SnapshotCalled func()
+	RevertCalled   func()
+	CommitCalled   func()
+}
+
+// NewPluginStub creates a PluginStub exposing the given methods, free of any extra gas cost
+func NewPluginStub(name string, methodNames ...string) *PluginStub {
+	methods := make([]vmhost.PluginMethod, len(methodNames))
+	for i, methodName := range methodNames {
+		methods[i] = vmhost.PluginMethod{Name: methodName}
+	}
+
+	return &PluginStub{
+		PluginName:    name,
+		PluginMethods: methods,
+		Deterministic: true,
+	}
+}
+
+// Name mocked method
+func (p *PluginStub) Name() string {
+	return p.PluginName
+}
+
+// Methods mocked method
+func (p *PluginStub) Methods() []vmhost.PluginMethod {
+	return p.PluginMethods
+}
+
+// IsDeterministic mocked method
+func (p *PluginStub) IsDeterministic() bool {
+	return p.Deterministic
+}
+
+// Call mocked method
+func (p *PluginStub) Call(callCtx *vmhost.PluginCallContext, methodName string, args []byte) ([]byte, error) {
+	if p.CallCalled != nil {
+		return p.CallCalled(callCtx, methodName, args)
+	}
+	return make([]byte, 0), nil
+}
+
+// Snapshot mocked method
+func (p *PluginStub) Snapshot() {
+	if p.SnapshotCalled != nil {
+		p.SnapshotCalled()
+	}
+}
+
+// Revert mocked method
+func (p *PluginStub) Revert() {
+	if p.RevertCalled != nil {
+		p.RevertCalled()
+	}
+}
+
+// Commit mocked method
+func (p *PluginStub) Commit() {
+	if p.CommitCalled != nil {
+		p.CommitCalled()
+	}
+}
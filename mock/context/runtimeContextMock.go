@@ -15,6 +15,7 @@ type RuntimeContextMock struct {
 	SCAddress              []byte
 	SCCode                 []byte
 	SCCodeSize             uint64
+	CodeHash               []byte
 	CallFunctionName       string
 	VMType                 []byte
 	ReadOnlyFlag           bool
@@ -142,6 +143,11 @@ func (r *RuntimeContextMock) GetSCCode() ([]byte, error) {
 	return r.SCCode, r.Err
 }
 
+// GetCodeHash mocked method
+func (r *RuntimeContextMock) GetCodeHash() []byte {
+	return r.CodeHash
+}
+
 // GetSCCodeSize mocked method
 func (r *RuntimeContextMock) GetSCCodeSize() uint64 {
 	return r.SCCodeSize
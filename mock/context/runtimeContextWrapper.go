@@ -30,6 +30,8 @@ type RuntimeContextWrapper struct {
 	// function that will be called by the corresponding RuntimeContext function implementation (by default this will call the same wrapped context function)
 	GetSCCodeSizeFunc func() uint64
 	// function that will be called by the corresponding RuntimeContext function implementation (by default this will call the same wrapped context function)
+	GetCodeHashFunc func() []byte
+	// function that will be called by the corresponding RuntimeContext function implementation (by default this will call the same wrapped context function)
 	GetVMTypeFunc func() []byte
 	// function that will be called by the corresponding RuntimeContext function implementation (by default this will call the same wrapped context function)
 	FunctionFunc func() string
@@ -174,6 +176,10 @@ func NewRuntimeContextWrapper(inputRuntimeContext *vmhost.RuntimeContext) *Runti
 		return runtimeWrapper.runtimeContext.GetSCCodeSize()
 	}
 
+	runtimeWrapper.GetCodeHashFunc = func() []byte {
+		return runtimeWrapper.runtimeContext.GetCodeHash()
+	}
+
 	runtimeWrapper.GetVMTypeFunc = func() []byte {
 		return runtimeWrapper.runtimeContext.GetVMType()
 	}
@@ -426,6 +432,11 @@ func (contextWrapper *RuntimeContextWrapper) GetSCCodeSize() uint64 {
 	return contextWrapper.GetSCCodeSizeFunc()
 }
 
+// GetCodeHash calls corresponding xxxFunc function, that by default in turn calls the original method of the wrapped RuntimeContext
+func (contextWrapper *RuntimeContextWrapper) GetCodeHash() []byte {
+	return contextWrapper.GetCodeHashFunc()
+}
+
 // GetVMType calls corresponding xxxFunc function, that by default in turn calls the original method of the wrapped RuntimeContext
 func (contextWrapper *RuntimeContextWrapper) GetVMType() []byte {
 	return contextWrapper.GetVMTypeFunc()
@@ -26,6 +26,7 @@ type VMHostMock struct {
 	StorageContext           vmhost.StorageContext
 	EnableEpochsHandlerField vmcommon.EnableEpochsHandler
 	ManagedTypesContext      vmhost.ManagedTypesContext
+	PluginsContext           vmhost.PluginsContext
 
 	SCAPIMethods  *wasmer.Imports
 	IsBuiltinFunc bool
@@ -71,8 +72,9 @@ func (host *VMHostMock) Storage() vmhost.StorageContext {
 	return host.StorageContext
 }
 
-func (host *VMHostMock) Plugins() *vmhost.PluginsContext {
-	return nil
+// Plugins mocked method
+func (host *VMHostMock) Plugins() vmhost.PluginsContext {
+	return host.PluginsContext
 }
 
 // EnableEpochsHandler mocked method
@@ -26,6 +26,7 @@ type VMHostStub struct {
 	OutputCalled                func() vmhost.OutputContext
 	MeteringCalled              func() vmhost.MeteringContext
 	StorageCalled               func() vmhost.StorageContext
+	PluginsCalled               func() vmhost.PluginsContext
 	EnableEpochsHandlerCalled   func() vmcommon.EnableEpochsHandler
 	ExecuteESDTTransferCalled   func(destination []byte, sender []byte, transfers []*vmcommon.ESDTTransfer, callType vm.CallType) (*vmcommon.VMOutput, uint64, error)
 	CreateNewContractCalled     func(input *vmcommon.ContractCreateInput) ([]byte, error)
@@ -116,7 +117,11 @@ func (vhs *VMHostStub) ManagedTypes() vmhost.ManagedTypesContext {
 	return nil
 }
 
-func (vhs *VMHostStub) Plugins() *vmhost.PluginsContext {
+// Plugins mocked method
+func (vhs *VMHostStub) Plugins() vmhost.PluginsContext {
+	if vhs.PluginsCalled != nil {
+		return vhs.PluginsCalled()
+	}
 	return nil
 }
 
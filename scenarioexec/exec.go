@@ -2,6 +2,7 @@ package scenarioexec
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/multiversx/mx-chain-core-go/core"
 	logger "github.com/multiversx/mx-chain-logger-go"
@@ -24,19 +25,31 @@ var log = logger.GetOrCreate("vm/scenarios")
 // TestVMType is the VM type argument we use in tests.
 var TestVMType = []byte{0, 0}
 
+// vmHostCreationMutex serializes the creation of the VM hosts of the executors running in parallel,
+// since each host sets the imports and the opcode costs of wasmer for the whole process.
+var vmHostCreationMutex sync.Mutex
+
 // VMTestExecutor parses, interprets and executes both .test.json tests and .scen.json scenarios with VM.
 type VMTestExecutor struct {
 	World             *worldhook.MockWorld
+	PluginsConfig     vmhost.PluginsConfig
 	vm                vmi.VMExecutionHandler
 	vmHost            vmhost.VMHost
 	checkGas          bool
 	scenarioTraceGas  []bool
 	fileResolver      fr.FileResolver
 	exprReconstructor er.ExprReconstructor
+	scriptedPlugins   map[string]*scriptedPlugin
+	pluginCalls       []*scriptedPluginCall
+	stepListener      mc.StepListener
+	scenarioDepth     int
+	gasUsed           uint64
+	currentTxID       string
 }
 
 var _ mc.TestExecutor = (*VMTestExecutor)(nil)
 var _ mc.ScenarioExecutor = (*VMTestExecutor)(nil)
+var _ mc.StepReportingExecutor = (*VMTestExecutor)(nil)
 
 // NewVMTestExecutor prepares a new VMTestExecutor instance.
 func NewVMTestExecutor() (*VMTestExecutor, error) {
@@ -49,6 +62,8 @@ func NewVMTestExecutor() (*VMTestExecutor, error) {
 		scenarioTraceGas:  make([]bool, 0),
 		fileResolver:      nil,
 		exprReconstructor: er.ExprReconstructor{},
+		scriptedPlugins:   make(map[string]*scriptedPlugin),
+		pluginCalls:       make([]*scriptedPluginCall, 0),
 	}, nil
 }
 
@@ -71,6 +86,8 @@ func (ae *VMTestExecutor) InitVM(scenGasSchedule mj.GasSchedule) error {
 
 	blockGasLimit := uint64(10000000)
 	esdtTransferParser, _ := parsers.NewESDTTransferParser(worldhook.WorldMarshalizer)
+	vmHostCreationMutex.Lock()
+	defer vmHostCreationMutex.Unlock()
 	vm, err := hostCore.NewVMHost(ae.World, &vmhost.VMHostParameters{
 		VMType:               TestVMType,
 		BlockGasLimit:        blockGasLimit,
@@ -103,9 +120,11 @@ func (ae *VMTestExecutor) InitVM(scenGasSchedule mj.GasSchedule) error {
 			IsAheadOfTimeGasUsageFlagEnabledField:                true,
 			IsCheckFunctionArgumentFlagEnabledField:              true,
 			IsCheckExecuteOnReadOnlyFlagEnabledField:             true,
+			IsVMPluginsFlagEnabledField:                          true,
 		},
 		WasmerSIGSEGVPassthrough: false,
 		Hasher:                   worldhook.DefaultHasher,
+		PluginsConfig:            ae.PluginsConfig,
 	})
 	if err != nil {
 		return err
@@ -116,6 +135,32 @@ func (ae *VMTestExecutor) InitVM(scenGasSchedule mj.GasSchedule) error {
 	return nil
 }
 
+// RegisterPlugin adds an in-process VM plugin, callable by the contracts in the scenarios;
+// plugins registered before InitVM are loaded together with the ones in PluginsConfig
+func (ae *VMTestExecutor) RegisterPlugin(plugin vmhost.Plugin) error {
+	if ae.vmHost == nil {
+		ae.PluginsConfig.Plugins = append(ae.PluginsConfig.Plugins, plugin)
+		return nil
+	}
+
+	return ae.vmHost.Plugins().RegisterPlugin(plugin)
+}
+
+// UnregisterPlugin removes an in-process VM plugin, from the VM host or from the plugins waiting for InitVM
+func (ae *VMTestExecutor) UnregisterPlugin(pluginName string) error {
+	if ae.vmHost != nil {
+		return ae.vmHost.Plugins().UnregisterPlugin(pluginName)
+	}
+
+	for i, plugin := range ae.PluginsConfig.Plugins {
+		if plugin.Name() == pluginName {
+			ae.PluginsConfig.Plugins = append(ae.PluginsConfig.Plugins[:i], ae.PluginsConfig.Plugins[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", vmhost.ErrPluginNotFound, pluginName)
+}
+
 // GetVM yields a reference to the VMExecutionHandler used.
 func (ae *VMTestExecutor) GetVM() vmi.VMExecutionHandler {
 	return ae.vm
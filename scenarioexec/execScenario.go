@@ -2,6 +2,7 @@ package scenarioexec
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/multiversx/mx-chain-core-go/core/check"
 	vmi "github.com/multiversx/mx-chain-vm-common-go"
@@ -18,6 +19,14 @@ func (ae *VMTestExecutor) Reset() {
 		ae.vmHost.Reset()
 	}
 	ae.World.Clear()
+	for name := range ae.scriptedPlugins {
+		err := ae.UnregisterPlugin(name)
+		if err != nil {
+			log.Warn("could not unregister scripted plugin", "name", name, "error", err)
+		}
+	}
+	ae.scriptedPlugins = make(map[string]*scriptedPlugin)
+	ae.pluginCalls = make([]*scriptedPluginCall, 0)
 }
 
 // Close will simply close the VM
@@ -33,6 +42,11 @@ func (ae *VMTestExecutor) ExecuteScenario(scenario *mj.Scenario, fileResolver fr
 	ae.checkGas = scenario.CheckGas
 	resetGasTracesIfNewTest(ae, scenario)
 
+	ae.scenarioDepth++
+	defer func() {
+		ae.scenarioDepth--
+	}()
+
 	err := ae.InitVM(scenario.GasSchedule)
 	if err != nil {
 		return err
@@ -40,8 +54,15 @@ func (ae *VMTestExecutor) ExecuteScenario(scenario *mj.Scenario, fileResolver fr
 
 	txIndex := 0
 	for _, generalStep := range scenario.Steps {
+		externalStep, isExternal := generalStep.(*mj.ExternalStepsStep)
+		if isExternal && scenario.SkipsExternalStep(externalStep) {
+			log.Trace("ExternalStepsStep skipped", "path", externalStep.Path)
+			txIndex++
+			continue
+		}
+
 		setGasTraceInMetering(ae, true)
-		err := ae.ExecuteStep(generalStep)
+		err := ae.executeReportedStep(txIndex, generalStep)
 		if err != nil {
 			return err
 		}
@@ -91,7 +112,7 @@ func (ae *VMTestExecutor) ExecuteExternalStep(step *mj.ExternalStepsStep) error
 	extAbsPth := ae.fileResolver.ResolveAbsolutePath(step.Path)
 	setExternalStepGasTracing(ae, step)
 
-	err := externalStepsRunner.RunSingleJSONScenario(extAbsPth, mc.DefaultRunScenarioOptions())
+	err := externalStepsRunner.RunJSONScenarioSteps(extAbsPth, mc.DefaultRunScenarioOptions())
 	if err != nil {
 		return err
 	}
@@ -136,12 +157,13 @@ func (ae *VMTestExecutor) ExecuteSetStateStep(step *mj.SetStateStep) error {
 	addressMocksToAdd := convertNewAddressMocks(step.NewAddressMocks)
 	ae.World.NewAddressMocks = append(ae.World.NewAddressMocks, addressMocksToAdd...)
 
-	return nil
+	return ae.setPluginMocks(step.Plugins)
 }
 
 // ExecuteTxStep executes a TxStep.
 func (ae *VMTestExecutor) ExecuteTxStep(step *mj.TxStep) (*vmi.VMOutput, error) {
 	log.Trace("ExecuteTxStep", "id", step.TxIdent)
+	ae.currentTxID = step.TxIdent
 	if len(step.Comment) > 0 {
 		log.Trace("ExecuteTxStep", "comment", step.Comment)
 	}
@@ -150,6 +172,7 @@ func (ae *VMTestExecutor) ExecuteTxStep(step *mj.TxStep) (*vmi.VMOutput, error)
 		vmhost.SetLoggingForTests()
 	}
 
+	pluginCallsBefore := len(ae.pluginCalls)
 	output, err := ae.executeTx(step.TxIdent, step.Tx)
 	if err != nil {
 		return nil, err
@@ -165,6 +188,12 @@ func (ae *VMTestExecutor) ExecuteTxStep(step *mj.TxStep) (*vmi.VMOutput, error)
 		if err != nil {
 			return nil, err
 		}
+
+		baseErrMsg := fmt.Sprintf("Tx '%s'.", step.TxIdent)
+		err = ae.checkPluginCalls(baseErrMsg, step.ExpectedResult.PluginCalls, ae.pluginCalls[pluginCallsBefore:])
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return output, nil
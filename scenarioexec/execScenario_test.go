@@ -0,0 +1,92 @@
+package scenarioexec
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	mc "github.com/multiversx/mx-chain-vm-v1_4-go/scenarios/controller"
+	"github.com/stretchr/testify/require"
+)
+
+// esdtCallScenario pays for the token transfer with the gas schedule of the scenario,
+// then the call fails on the code of the contract, using the gas left
+const esdtCallScenario = `{
+	"gasSchedule": "%s",
+	"steps": [
+		{
+			"step": "setState",
+			"accounts": {
+				"address:alice": {"balance": "0", "esdt": {"str:TOKEN-123456": "100"}},
+				"sc:contract": {"balance": "0", "code": "0x0061736d01000000"}
+			}
+		},
+		{
+			"step": "scCall",
+			"id": "call",
+			"tx": {
+				"from": "address:alice",
+				"to": "sc:contract",
+				"esdtValue": [{"tokenIdentifier": "str:TOKEN-123456", "value": "10"}],
+				"function": "call",
+				"arguments": [],
+				"gasLimit": "10,000,000",
+				"gasPrice": "0"
+			},
+			"expect": {"status": "*", "message": "*", "gas": "*", "refund": "*"}
+		}
+	]
+}`
+
+func runScenarioFiles(t *testing.T, files map[string]string, parallel int) ([]*mc.ScenarioReport, error) {
+	dir := t.TempDir()
+	for file, gasSchedule := range files {
+		err := ioutil.WriteFile(filepath.Join(dir, file), []byte(fmt.Sprintf(esdtCallScenario, gasSchedule)), 0644)
+		require.Nil(t, err)
+	}
+
+	executor, err := NewVMTestExecutor()
+	require.Nil(t, err)
+	defer executor.Close()
+
+	runner := mc.NewScenarioRunner(executor, mc.NewDefaultFileResolver())
+	runner.ExecutorFactory = func() (mc.ScenarioExecutor, error) {
+		return NewVMTestExecutor()
+	}
+	collector := mc.NewReportCollector()
+	runner.AddReporter(collector)
+
+	options := mc.DefaultRunScenarioOptions()
+	options.Parallel = parallel
+	err = runner.RunAllJSONScenariosInDirectory(dir, "", ".scen.json", []string{}, options)
+	return collector.Reports, err
+}
+
+func TestRunScenariosInParallel_GasSchedules(t *testing.T) {
+	expectedGasUsed := make(map[string]uint64)
+	for _, gasSchedule := range []string{"v3", "v4"} {
+		reports, err := runScenarioFiles(t, map[string]string{"a.scen.json": gasSchedule}, 1)
+		require.Nil(t, err)
+		expectedGasUsed[gasSchedule] = reports[0].GasUsed()
+	}
+	require.NotEqual(t, expectedGasUsed["v3"], expectedGasUsed["v4"])
+
+	_, err := runScenarioFiles(t, map[string]string{"a.scen.json": "v3", "b.scen.json": "v4"}, 2)
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "different gas schedules")
+
+	for _, gasSchedule := range []string{"v3", "v4"} {
+		files := make(map[string]string)
+		for i := 0; i < 8; i++ {
+			files[fmt.Sprintf("s%d.scen.json", i)] = gasSchedule
+		}
+
+		reports, err := runScenarioFiles(t, files, 4)
+		require.Nil(t, err)
+		require.Len(t, reports, len(files))
+		for _, report := range reports {
+			require.Equal(t, expectedGasUsed[gasSchedule], report.GasUsed())
+		}
+	}
+}
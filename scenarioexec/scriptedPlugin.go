@@ -0,0 +1,108 @@
+package scenarioexec
+
+import (
+	"bytes"
+	"fmt"
+
+	mj "github.com/multiversx/mx-chain-vm-v1_4-go/scenarios/model"
+	"github.com/multiversx/mx-chain-vm-v1_4-go/vmhost"
+)
+
+var _ vmhost.Plugin = (*scriptedPlugin)(nil)
+var _ vmhost.DeterministicPlugin = (*scriptedPlugin)(nil)
+
+// scriptedPluginCall is a plugin call made by a contract during the scenario
+type scriptedPluginCall struct {
+	plugin string
+	method string
+	args   []byte
+}
+
+// scriptedPlugin is an in-process VM plugin answering with the responses declared in setState steps
+type scriptedPlugin struct {
+	name        string
+	methodNames []string
+	responses   map[string][]*mj.PluginResponseMock
+	executor    *VMTestExecutor
+}
+
+func newScriptedPlugin(name string, executor *VMTestExecutor) *scriptedPlugin {
+	return &scriptedPlugin{
+		name:        name,
+		methodNames: make([]string, 0),
+		responses:   make(map[string][]*mj.PluginResponseMock),
+		executor:    executor,
+	}
+}
+
+// Name returns the plugin name, as declared in the scenario
+func (plugin *scriptedPlugin) Name() string {
+	return plugin.name
+}
+
+// Methods returns the scripted methods, which cost no gas besides the plugin call itself
+func (plugin *scriptedPlugin) Methods() []vmhost.PluginMethod {
+	methods := make([]vmhost.PluginMethod, len(plugin.methodNames))
+	for i, methodName := range plugin.methodNames {
+		methods[i] = vmhost.PluginMethod{Name: methodName}
+	}
+	return methods
+}
+
+// IsDeterministic returns true, scripted responses only depend on the call arguments
+func (plugin *scriptedPlugin) IsDeterministic() bool {
+	return true
+}
+
+// Call records the call, then returns the first scripted response matching its arguments
+func (plugin *scriptedPlugin) Call(_ *vmhost.PluginCallContext, methodName string, args []byte) ([]byte, error) {
+	plugin.executor.pluginCalls = append(plugin.executor.pluginCalls, &scriptedPluginCall{
+		plugin: plugin.name,
+		method: methodName,
+		args:   append([]byte{}, args...),
+	})
+
+	for _, response := range plugin.responses[methodName] {
+		if !response.Args.Unspecified && !bytes.Equal(response.Args.Value, args) {
+			continue
+		}
+		if !response.UserError.Unspecified {
+			return nil, &vmhost.PluginUserError{Message: string(response.UserError.Value)}
+		}
+		return append([]byte{}, response.Result.Value...), nil
+	}
+
+	return nil, fmt.Errorf("%w: no scripted response for %s/%s with args 0x%x",
+		vmhost.ErrPluginCallFailed, plugin.name, methodName, args)
+}
+
+// setMethod replaces the scripted responses of a method
+func (plugin *scriptedPlugin) setMethod(methodMock *mj.PluginMethodMock) {
+	_, exists := plugin.responses[methodMock.Name]
+	if !exists {
+		plugin.methodNames = append(plugin.methodNames, methodMock.Name)
+	}
+	plugin.responses[methodMock.Name] = methodMock.Responses
+}
+
+// setPluginMocks scripts the plugins declared by a setState step,
+// registering the plugins not seen before in the scenario
+func (ae *VMTestExecutor) setPluginMocks(pluginMocks []*mj.PluginMock) error {
+	for _, pluginMock := range pluginMocks {
+		plugin, exists := ae.scriptedPlugins[pluginMock.Name]
+		if !exists {
+			plugin = newScriptedPlugin(pluginMock.Name, ae)
+			err := ae.RegisterPlugin(plugin)
+			if err != nil {
+				return fmt.Errorf("cannot register scripted plugin %s: %w", pluginMock.Name, err)
+			}
+			ae.scriptedPlugins[pluginMock.Name] = plugin
+		}
+
+		for _, methodMock := range pluginMock.Methods {
+			plugin.setMethod(methodMock)
+		}
+	}
+
+	return nil
+}
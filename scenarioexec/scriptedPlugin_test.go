@@ -0,0 +1,75 @@
+package scenarioexec
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	mc "github.com/multiversx/mx-chain-vm-v1_4-go/scenarios/controller"
+	"github.com/multiversx/mx-chain-vm-v1_4-go/vmhost"
+	"github.com/stretchr/testify/require"
+)
+
+const oracleScenario = `{
+	"steps": [
+		{
+			"step": "setState",
+			"plugins": {"oracle": {"getPrice": {"result": "u64:1000"}}}
+		}
+	]
+}`
+
+const noPluginsScenario = `{
+	"steps": [
+		{
+			"step": "setState",
+			"accounts": {"address:owner": {"balance": "1"}}
+		}
+	]
+}`
+
+func runScenarioDirectory(t *testing.T, executor *VMTestExecutor, scenarios map[string]string) error {
+	dir := t.TempDir()
+	for file, content := range scenarios {
+		err := ioutil.WriteFile(filepath.Join(dir, file), []byte(content), 0644)
+		require.Nil(t, err)
+	}
+
+	runner := mc.NewScenarioRunner(executor, mc.NewDefaultFileResolver())
+	return runner.RunAllJSONScenariosInDirectory(dir, "", ".scen.json", []string{}, mc.DefaultRunScenarioOptions())
+}
+
+func TestScriptedPlugins_NotKeptAcrossScenarioFiles(t *testing.T) {
+	executor, err := NewVMTestExecutor()
+	require.Nil(t, err)
+	defer executor.Close()
+
+	err = runScenarioDirectory(t, executor, map[string]string{
+		"a.scen.json": oracleScenario,
+		"b.scen.json": noPluginsScenario,
+	})
+	require.Nil(t, err)
+
+	// a contract calling the plugin in b.scen.json gets the same error as when running the file alone
+	_, _, err = executor.vmHost.Plugins().GetPluginMethod("oracle", "getPrice")
+	require.True(t, errors.Is(err, vmhost.ErrPluginNotFound))
+	require.Len(t, executor.scriptedPlugins, 0)
+}
+
+func TestScriptedPlugins_DeclaredAgainInLaterScenarioFile(t *testing.T) {
+	executor, err := NewVMTestExecutor()
+	require.Nil(t, err)
+	defer executor.Close()
+
+	err = runScenarioDirectory(t, executor, map[string]string{
+		"a.scen.json": oracleScenario,
+		"b.scen.json": oracleScenario,
+	})
+	require.Nil(t, err)
+
+	plugin, method, err := executor.vmHost.Plugins().GetPluginMethod("oracle", "getPrice")
+	require.Nil(t, err)
+	require.Equal(t, "oracle", plugin.Name())
+	require.Equal(t, "getPrice", method.Name)
+}
@@ -23,7 +23,14 @@ func (ae *VMTestExecutor) ExecuteCheckStateStep(step *mj.CheckStateStep) error {
 	}
 
 	baseErrMsg := checkStateBaseErrorMsg(step)
-	return ae.checkAccounts(baseErrMsg, step.CheckAccounts)
+	if step.CheckAccounts != nil {
+		err := ae.checkAccounts(baseErrMsg, step.CheckAccounts)
+		if err != nil {
+			return err
+		}
+	}
+
+	return ae.checkPluginCalls(baseErrMsg, step.PluginCalls, ae.pluginCalls)
 }
 
 func checkStateBaseErrorMsg(step *mj.CheckStateStep) string {
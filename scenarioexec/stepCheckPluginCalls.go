@@ -0,0 +1,62 @@
+package scenarioexec
+
+import (
+	"fmt"
+
+	er "github.com/multiversx/mx-chain-vm-v1_4-go/scenarios/expression/reconstructor"
+	mj "github.com/multiversx/mx-chain-vm-v1_4-go/scenarios/model"
+	oj "github.com/multiversx/mx-chain-vm-v1_4-go/scenarios/orderedjson"
+)
+
+// checkPluginCalls compares the calls made to the scripted plugins with the expected ones, in order
+func (ae *VMTestExecutor) checkPluginCalls(
+	baseErrMsg string,
+	expectedCalls *mj.PluginCallList,
+	actualCalls []*scriptedPluginCall,
+) error {
+	// unspecified or "*" means any calls are accepted
+	if expectedCalls == nil || expectedCalls.IsStar {
+		return nil
+	}
+
+	if len(actualCalls) < len(expectedCalls.List) {
+		return fmt.Errorf("%s too few plugin calls. Want: %d. Got: %d",
+			baseErrMsg,
+			len(expectedCalls.List),
+			len(actualCalls))
+	}
+
+	for i, actualCall := range actualCalls {
+		if i >= len(expectedCalls.List) {
+			if expectedCalls.MoreAllowedAtEnd {
+				return nil
+			}
+			return fmt.Errorf("%s unexpected plugin call. Call index: %d. Call: %s",
+				baseErrMsg,
+				i,
+				ae.pluginCallPretty(actualCall))
+		}
+
+		expectedCall := expectedCalls.List[i]
+		if expectedCall.Plugin != actualCall.plugin ||
+			expectedCall.Method != actualCall.method ||
+			!expectedCall.Args.Check(actualCall.args) {
+			return fmt.Errorf("%s plugin call mismatch. Call index: %d. Want: %s/%s(%s). Have: %s",
+				baseErrMsg,
+				i,
+				expectedCall.Plugin,
+				expectedCall.Method,
+				oj.JSONString(expectedCall.Args.Original),
+				ae.pluginCallPretty(actualCall))
+		}
+	}
+
+	return nil
+}
+
+func (ae *VMTestExecutor) pluginCallPretty(call *scriptedPluginCall) string {
+	return fmt.Sprintf("%s/%s(%s)",
+		call.plugin,
+		call.method,
+		ae.exprReconstructor.Reconstruct(call.args, er.NoHint))
+}
@@ -17,7 +17,11 @@ import (
 
 const includeProtectedStorage = false
 
-func (ae *VMTestExecutor) convertMockAccountToScenarioFormat(account *worldmock.Account) (*mj.Account, error) {
+// ConvertMockAccountToScenarioFormat converts an account of the mock world to the scenario format,
+// with the values reconstructed as readable expressions. Protected storage keys are left out.
+func ConvertMockAccountToScenarioFormat(world *worldmock.MockWorld, account *worldmock.Account) (*mj.Account, error) {
+	exprReconstructor := er.ExprReconstructor{}
+
 	var storageKeys []string
 	for storageKey := range account.Storage {
 		storageKeys = append(storageKeys, storageKey)
@@ -32,18 +36,18 @@ func (ae *VMTestExecutor) convertMockAccountToScenarioFormat(account *worldmock.
 			storageKvps = append(storageKvps, &mj.StorageKeyValuePair{
 				Key: mj.JSONBytesFromString{
 					Value:    []byte(storageKey),
-					Original: ae.exprReconstructor.Reconstruct([]byte(storageKey), er.NoHint),
+					Original: exprReconstructor.Reconstruct([]byte(storageKey), er.NoHint),
 				},
 				Value: mj.JSONBytesFromTree{
 					Value:    storageValue,
-					Original: &oj.OJsonString{Value: ae.exprReconstructor.Reconstruct(storageValue, er.NoHint)},
+					Original: &oj.OJsonString{Value: exprReconstructor.Reconstruct(storageValue, er.NoHint)},
 				},
 			})
 		}
 	}
 
 	systemAccStorage := make(map[string][]byte)
-	systemAcc, exists := ae.World.AcctMap[string(vmcommon.SystemAccountAddress)]
+	systemAcc, exists := world.AcctMap[string(vmcommon.SystemAccountAddress)]
 	if exists {
 		systemAccStorage = systemAcc.Storage
 	}
@@ -71,7 +75,7 @@ func (ae *VMTestExecutor) convertMockAccountToScenarioFormat(account *worldmock.
 			if len(mockInstance.TokenMetaData.Creator) > 0 {
 				creator = mj.JSONBytesFromString{
 					Value:    mockInstance.TokenMetaData.Creator,
-					Original: ae.exprReconstructor.Reconstruct(mockInstance.TokenMetaData.Creator, er.AddressHint),
+					Original: exprReconstructor.Reconstruct(mockInstance.TokenMetaData.Creator, er.AddressHint),
 				}
 			}
 
@@ -79,7 +83,7 @@ func (ae *VMTestExecutor) convertMockAccountToScenarioFormat(account *worldmock.
 			if mockInstance.TokenMetaData.Royalties > 0 {
 				royalties = mj.JSONUint64{
 					Value:    uint64(mockInstance.TokenMetaData.Royalties),
-					Original: ae.exprReconstructor.ReconstructFromUint64(uint64(mockInstance.TokenMetaData.Royalties)),
+					Original: exprReconstructor.ReconstructFromUint64(uint64(mockInstance.TokenMetaData.Royalties)),
 				}
 			}
 
@@ -87,7 +91,7 @@ func (ae *VMTestExecutor) convertMockAccountToScenarioFormat(account *worldmock.
 			if len(mockInstance.TokenMetaData.Hash) > 0 {
 				hash = mj.JSONBytesFromString{
 					Value:    mockInstance.TokenMetaData.Hash,
-					Original: ae.exprReconstructor.Reconstruct(mockInstance.TokenMetaData.Hash, er.NoHint),
+					Original: exprReconstructor.Reconstruct(mockInstance.TokenMetaData.Hash, er.NoHint),
 				}
 			}
 
@@ -95,7 +99,7 @@ func (ae *VMTestExecutor) convertMockAccountToScenarioFormat(account *worldmock.
 			for _, uri := range mockInstance.TokenMetaData.URIs {
 				jsonUris = append(jsonUris, mj.JSONBytesFromString{
 					Value:    uri,
-					Original: ae.exprReconstructor.Reconstruct(uri, er.StrHint),
+					Original: exprReconstructor.Reconstruct(uri, er.StrHint),
 				})
 			}
 
@@ -103,18 +107,18 @@ func (ae *VMTestExecutor) convertMockAccountToScenarioFormat(account *worldmock.
 			if len(mockInstance.TokenMetaData.Attributes) > 0 {
 				attributes = mj.JSONBytesFromString{
 					Value:    mockInstance.TokenMetaData.Attributes,
-					Original: ae.exprReconstructor.Reconstruct(mockInstance.TokenMetaData.Attributes, er.NoHint),
+					Original: exprReconstructor.Reconstruct(mockInstance.TokenMetaData.Attributes, er.NoHint),
 				}
 			}
 
 			scenInstances = append(scenInstances, &mj.ESDTInstance{
 				Nonce: mj.JSONUint64{
 					Value:    mockInstance.TokenMetaData.Nonce,
-					Original: ae.exprReconstructor.ReconstructFromUint64(mockInstance.TokenMetaData.Nonce),
+					Original: exprReconstructor.ReconstructFromUint64(mockInstance.TokenMetaData.Nonce),
 				},
 				Balance: mj.JSONBigInt{
 					Value:    mockInstance.Value,
-					Original: ae.exprReconstructor.ReconstructFromBigInt(mockInstance.Value),
+					Original: exprReconstructor.ReconstructFromBigInt(mockInstance.Value),
 				},
 				Creator:    creator,
 				Royalties:  royalties,
@@ -127,12 +131,12 @@ func (ae *VMTestExecutor) convertMockAccountToScenarioFormat(account *worldmock.
 		scenESDT = append(scenESDT, &mj.ESDTData{
 			TokenIdentifier: mj.JSONBytesFromString{
 				Value:    esdtObj.TokenIdentifier,
-				Original: ae.exprReconstructor.Reconstruct(esdtObj.TokenIdentifier, er.StrHint),
+				Original: exprReconstructor.Reconstruct(esdtObj.TokenIdentifier, er.StrHint),
 			},
 			Instances: scenInstances,
 			LastNonce: mj.JSONUint64{
 				Value:    esdtObj.LastNonce,
-				Original: ae.exprReconstructor.ReconstructFromUint64(esdtObj.LastNonce),
+				Original: exprReconstructor.ReconstructFromUint64(esdtObj.LastNonce),
 			},
 			Roles: scenRoles,
 		})
@@ -141,21 +145,21 @@ func (ae *VMTestExecutor) convertMockAccountToScenarioFormat(account *worldmock.
 	return &mj.Account{
 		Address: mj.JSONBytesFromString{
 			Value:    account.Address,
-			Original: ae.exprReconstructor.Reconstruct(account.Address, er.AddressHint),
+			Original: exprReconstructor.Reconstruct(account.Address, er.AddressHint),
 		},
 		Nonce: mj.JSONUint64{
 			Value:    account.Nonce,
-			Original: ae.exprReconstructor.ReconstructFromUint64(account.Nonce),
+			Original: exprReconstructor.ReconstructFromUint64(account.Nonce),
 		},
 		Balance: mj.JSONBigInt{
 			Value:    account.Balance,
-			Original: ae.exprReconstructor.ReconstructFromBigInt(account.Balance),
+			Original: exprReconstructor.ReconstructFromBigInt(account.Balance),
 		},
 		Storage:  storageKvps,
 		ESDTData: scenESDT,
 		Owner: mj.JSONBytesFromString{
 			Value:    account.OwnerAddress,
-			Original: ae.exprReconstructor.Reconstruct(account.OwnerAddress, er.AddressHint),
+			Original: exprReconstructor.Reconstruct(account.OwnerAddress, er.AddressHint),
 		},
 	}, nil
 }
@@ -166,7 +170,7 @@ func (ae *VMTestExecutor) DumpWorld() error {
 	var scenAccounts []*mj.Account
 
 	for _, account := range ae.World.AcctMap {
-		scenAccount, err := ae.convertMockAccountToScenarioFormat(account)
+		scenAccount, err := ConvertMockAccountToScenarioFormat(ae.World, account)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,40 @@
+package scenarioexec
+
+import (
+	"time"
+
+	mc "github.com/multiversx/mx-chain-vm-v1_4-go/scenarios/controller"
+	mj "github.com/multiversx/mx-chain-vm-v1_4-go/scenarios/model"
+)
+
+// SetStepListener sets the listener receiving the report of each top level step of the scenarios; nil stops the reports.
+// The steps of external steps are part of the external step that runs them.
+func (ae *VMTestExecutor) SetStepListener(listener mc.StepListener) {
+	ae.stepListener = listener
+}
+
+func (ae *VMTestExecutor) executeReportedStep(index int, step mj.Step) error {
+	if ae.stepListener == nil || ae.scenarioDepth > 1 {
+		return ae.ExecuteStep(step)
+	}
+
+	ae.currentTxID = ""
+	gasUsedBefore := ae.gasUsed
+	start := time.Now()
+	err := ae.ExecuteStep(step)
+
+	stepReport := &mc.StepReport{
+		Index:    index,
+		Step:     step.StepTypeName(),
+		TxID:     ae.currentTxID,
+		Passed:   err == nil,
+		Duration: time.Since(start),
+		GasUsed:  ae.gasUsed - gasUsedBefore,
+	}
+	if err != nil {
+		stepReport.Error = err.Error()
+	}
+	ae.stepListener(stepReport)
+
+	return err
+}
@@ -66,6 +66,7 @@ func (ae *VMTestExecutor) executeTx(txIndex string, tx *mj.Transaction) (*vmcomm
 			if err != nil {
 				return nil, err
 			}
+			ae.gasUsed += gasForExecution - output.GasRemaining
 			if ae.PeekTraceGas() {
 				fmt.Println("\nIn txID:", txIndex, ", step type:Deploy", ", total gas used:", gasForExecution-output.GasRemaining)
 			}
@@ -82,6 +83,7 @@ func (ae *VMTestExecutor) executeTx(txIndex string, tx *mj.Transaction) (*vmcomm
 			if err != nil {
 				return nil, err
 			}
+			ae.gasUsed += gasForExecution - output.GasRemaining
 			if ae.PeekTraceGas() {
 				fmt.Println("\nIn txID:", txIndex, ", step type:ScCall, function:", tx.Function, ", total gas used:", gasForExecution-output.GasRemaining)
 			}
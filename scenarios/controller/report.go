@@ -0,0 +1,111 @@
+package scencontroller
+
+import (
+	"time"
+)
+
+// StepReport is the outcome of a step at the top level of a scenario.
+type StepReport struct {
+	// Index is the position of the step in the steps of the scenario.
+	Index int
+	// Step is the step type name, e.g. "scCall".
+	Step string
+	// TxID is the id of the last transaction executed by the step, the failing one for a failed external step.
+	TxID     string
+	Passed   bool
+	Error    string
+	Duration time.Duration
+	// GasUsed is the gas consumed by the VM in the transactions of the step.
+	GasUsed uint64
+}
+
+// StepListener receives the report of each step, as soon as the step is done.
+type StepListener func(step *StepReport)
+
+// StepReportingExecutor is a ScenarioExecutor that can report the top level steps of the scenarios it executes.
+type StepReportingExecutor interface {
+	ScenarioExecutor
+
+	// SetStepListener sets the listener of the steps; nil stops the reports.
+	SetStepListener(listener StepListener)
+}
+
+// ScenarioReport is the outcome of a scenario file.
+type ScenarioReport struct {
+	Path     string
+	Name     string
+	Skipped  bool
+	Err      error
+	Duration time.Duration
+	// Steps are only reported by a StepReportingExecutor; they end with the failing step, if a step failed.
+	Steps []*StepReport
+}
+
+// Passed returns true if the scenario ran without error.
+func (report *ScenarioReport) Passed() bool {
+	return !report.Skipped && report.Err == nil
+}
+
+// FailedStep returns the step that failed the scenario, or nil if the scenario passed or failed outside its steps.
+func (report *ScenarioReport) FailedStep() *StepReport {
+	if len(report.Steps) == 0 {
+		return nil
+	}
+
+	lastStep := report.Steps[len(report.Steps)-1]
+	if lastStep.Passed {
+		return nil
+	}
+	return lastStep
+}
+
+// GasUsed returns the gas consumed in all the steps of the scenario.
+func (report *ScenarioReport) GasUsed() uint64 {
+	gasUsed := uint64(0)
+	for _, step := range report.Steps {
+		gasUsed += step.GasUsed
+	}
+	return gasUsed
+}
+
+// Reporter receives the report of each scenario run by a ScenarioRunner.
+// The reports come in the order of the scenario files, even when they run in parallel, and never concurrently.
+type Reporter interface {
+	ScenarioFinished(report *ScenarioReport)
+}
+
+// ReportCollector is a Reporter keeping all the reports, to write them once the run is over.
+type ReportCollector struct {
+	Reports []*ScenarioReport
+}
+
+// NewReportCollector creates a new ReportCollector instance.
+func NewReportCollector() *ReportCollector {
+	return &ReportCollector{
+		Reports: make([]*ScenarioReport, 0),
+	}
+}
+
+// ScenarioFinished keeps the report.
+func (collector *ReportCollector) ScenarioFinished(report *ScenarioReport) {
+	collector.Reports = append(collector.Reports, report)
+}
+
+// countReports returns the number of passed, failed and skipped scenarios, and their total duration.
+func countReports(reports []*ScenarioReport) (int, int, int, time.Duration) {
+	var nrPassed, nrFailed, nrSkipped int
+	duration := time.Duration(0)
+	for _, report := range reports {
+		switch {
+		case report.Skipped:
+			nrSkipped++
+		case report.Err == nil:
+			nrPassed++
+		default:
+			nrFailed++
+		}
+		duration += report.Duration
+	}
+
+	return nrPassed, nrFailed, nrSkipped, duration
+}
@@ -0,0 +1,116 @@
+package scencontroller
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+const (
+	reportStatusPass = "pass"
+	reportStatusFail = "fail"
+	reportStatusSkip = "skip"
+)
+
+type jsonStepReport struct {
+	Index           int     `json:"index"`
+	Step            string  `json:"step"`
+	TxID            string  `json:"txId,omitempty"`
+	Status          string  `json:"status"`
+	Error           string  `json:"error,omitempty"`
+	DurationSeconds float64 `json:"durationSeconds"`
+	GasUsed         uint64  `json:"gasUsed"`
+}
+
+type jsonScenarioReport struct {
+	Path            string            `json:"path"`
+	Name            string            `json:"name,omitempty"`
+	Status          string            `json:"status"`
+	Error           string            `json:"error,omitempty"`
+	FailedStepIndex *int              `json:"failedStepIndex,omitempty"`
+	FailedTxID      string            `json:"failedTxId,omitempty"`
+	DurationSeconds float64           `json:"durationSeconds"`
+	GasUsed         uint64            `json:"gasUsed"`
+	Steps           []*jsonStepReport `json:"steps"`
+}
+
+type jsonRunReport struct {
+	Passed          int                   `json:"passed"`
+	Failed          int                   `json:"failed"`
+	Skipped         int                   `json:"skipped"`
+	DurationSeconds float64               `json:"durationSeconds"`
+	Scenarios       []*jsonScenarioReport `json:"scenarios"`
+}
+
+// WriteJSONFile writes the collected reports as a JSON document, with the totals and every scenario and step.
+func (collector *ReportCollector) WriteJSONFile(toPath string) error {
+	nrPassed, nrFailed, nrSkipped, duration := countReports(collector.Reports)
+	runReport := &jsonRunReport{
+		Passed:          nrPassed,
+		Failed:          nrFailed,
+		Skipped:         nrSkipped,
+		DurationSeconds: duration.Seconds(),
+		Scenarios:       make([]*jsonScenarioReport, 0, len(collector.Reports)),
+	}
+	for _, report := range collector.Reports {
+		runReport.Scenarios = append(runReport.Scenarios, toJSONScenarioReport(report))
+	}
+
+	reportJSON, err := json.MarshalIndent(runReport, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(toPath), os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(toPath, reportJSON, 0644)
+}
+
+func toJSONScenarioReport(report *ScenarioReport) *jsonScenarioReport {
+	scenarioReport := &jsonScenarioReport{
+		Path:            report.Path,
+		Name:            report.Name,
+		Status:          reportStatus(report.Skipped, report.Err == nil),
+		DurationSeconds: report.Duration.Seconds(),
+		GasUsed:         report.GasUsed(),
+		Steps:           make([]*jsonStepReport, 0, len(report.Steps)),
+	}
+	if report.Err != nil {
+		scenarioReport.Error = report.Err.Error()
+	}
+
+	failedStep := report.FailedStep()
+	if failedStep != nil {
+		scenarioReport.FailedStepIndex = &failedStep.Index
+		scenarioReport.FailedTxID = failedStep.TxID
+	}
+
+	for _, step := range report.Steps {
+		scenarioReport.Steps = append(scenarioReport.Steps, &jsonStepReport{
+			Index:           step.Index,
+			Step:            step.Step,
+			TxID:            step.TxID,
+			Status:          reportStatus(false, step.Passed),
+			Error:           step.Error,
+			DurationSeconds: step.Duration.Seconds(),
+			GasUsed:         step.GasUsed,
+		})
+	}
+
+	return scenarioReport
+}
+
+func reportStatus(skipped bool, passed bool) string {
+	switch {
+	case skipped:
+		return reportStatusSkip
+	case passed:
+		return reportStatusPass
+	default:
+		return reportStatusFail
+	}
+}
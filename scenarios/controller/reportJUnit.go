@@ -0,0 +1,147 @@
+package scencontroller
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitTestCase struct {
+	XMLName    xml.Name        `xml:"testcase"`
+	Name       string          `xml:"name,attr"`
+	ClassName  string          `xml:"classname,attr"`
+	Time       string          `xml:"time,attr"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	Failure    *junitFailure   `xml:"failure,omitempty"`
+	Skipped    *struct{}       `xml:"skipped,omitempty"`
+}
+
+type junitTestSuite struct {
+	XMLName    xml.Name         `xml:"testsuite"`
+	Name       string           `xml:"name,attr"`
+	Tests      int              `xml:"tests,attr"`
+	Failures   int              `xml:"failures,attr"`
+	Skipped    int              `xml:"skipped,attr"`
+	Time       string           `xml:"time,attr"`
+	Properties []junitProperty  `xml:"properties>property,omitempty"`
+	TestCases  []*junitTestCase `xml:"testcase"`
+}
+
+type junitTestSuites struct {
+	XMLName    xml.Name          `xml:"testsuites"`
+	Name       string            `xml:"name,attr"`
+	Tests      int               `xml:"tests,attr"`
+	Failures   int               `xml:"failures,attr"`
+	Skipped    int               `xml:"skipped,attr"`
+	Time       string            `xml:"time,attr"`
+	TestSuites []*junitTestSuite `xml:"testsuite"`
+}
+
+// WriteJUnitFile writes the collected reports as JUnit XML: a test suite per scenario file, with a test case per step.
+// Scenarios that are skipped, or fail outside their steps, get a test case of their own.
+func (collector *ReportCollector) WriteJUnitFile(toPath string) error {
+	_, _, _, duration := countReports(collector.Reports)
+	testSuites := &junitTestSuites{
+		Name:       "scenarios",
+		Time:       junitTime(duration),
+		TestSuites: make([]*junitTestSuite, 0, len(collector.Reports)),
+	}
+	for _, report := range collector.Reports {
+		testSuite := toJUnitTestSuite(report)
+		testSuites.Tests += testSuite.Tests
+		testSuites.Failures += testSuite.Failures
+		testSuites.Skipped += testSuite.Skipped
+		testSuites.TestSuites = append(testSuites.TestSuites, testSuite)
+	}
+
+	reportXML, err := xml.MarshalIndent(testSuites, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(toPath), os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(toPath, append([]byte(xml.Header), reportXML...), 0644)
+}
+
+func toJUnitTestSuite(report *ScenarioReport) *junitTestSuite {
+	testSuite := &junitTestSuite{
+		Name: report.Path,
+		Time: junitTime(report.Duration),
+		Properties: []junitProperty{
+			{Name: "name", Value: report.Name},
+			{Name: "gasUsed", Value: fmt.Sprint(report.GasUsed())},
+		},
+		TestCases: make([]*junitTestCase, 0, len(report.Steps)+1),
+	}
+
+	for _, step := range report.Steps {
+		testCase := &junitTestCase{
+			Name:      junitStepName(step),
+			ClassName: report.Path,
+			Time:      junitTime(step.Duration),
+			Properties: []junitProperty{
+				{Name: "gasUsed", Value: fmt.Sprint(step.GasUsed)},
+			},
+		}
+		if !step.Passed {
+			testCase.Failure = &junitFailure{Message: step.Error, Text: step.Error}
+		}
+		testSuite.TestCases = append(testSuite.TestCases, testCase)
+	}
+
+	scenarioFailedOutsideSteps := report.Err != nil && report.FailedStep() == nil
+	if report.Skipped || scenarioFailedOutsideSteps || len(report.Steps) == 0 {
+		testCase := &junitTestCase{
+			Name:      "scenario",
+			ClassName: report.Path,
+			Time:      junitTime(report.Duration),
+		}
+		switch {
+		case report.Skipped:
+			testCase.Skipped = &struct{}{}
+		case report.Err != nil:
+			testCase.Failure = &junitFailure{Message: report.Err.Error(), Text: report.Err.Error()}
+		}
+		testSuite.TestCases = append(testSuite.TestCases, testCase)
+	}
+
+	for _, testCase := range testSuite.TestCases {
+		testSuite.Tests++
+		if testCase.Failure != nil {
+			testSuite.Failures++
+		}
+		if testCase.Skipped != nil {
+			testSuite.Skipped++
+		}
+	}
+
+	return testSuite
+}
+
+func junitStepName(step *StepReport) string {
+	if len(step.TxID) > 0 {
+		return fmt.Sprintf("step %d: %s %s", step.Index, step.Step, step.TxID)
+	}
+	return fmt.Sprintf("step %d: %s", step.Index, step.Step)
+}
+
+func junitTime(duration time.Duration) string {
+	return fmt.Sprintf("%.3f", duration.Seconds())
+}
@@ -0,0 +1,177 @@
+package scencontroller
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	fr "github.com/multiversx/mx-chain-vm-v1_4-go/scenarios/fileresolver"
+	mj "github.com/multiversx/mx-chain-vm-v1_4-go/scenarios/model"
+	"github.com/stretchr/testify/require"
+)
+
+// reportingExecutorStub reports each step, using 10 gas per step and failing the transaction with the id "fail"
+type reportingExecutorStub struct {
+	listener StepListener
+}
+
+func (e *reportingExecutorStub) Reset() {
+}
+
+func (e *reportingExecutorStub) SetStepListener(listener StepListener) {
+	e.listener = listener
+}
+
+func (e *reportingExecutorStub) ExecuteScenario(scenario *mj.Scenario, _ fr.FileResolver) error {
+	for index, generalStep := range scenario.Steps {
+		step := &StepReport{Index: index, Step: generalStep.StepTypeName(), Passed: true, GasUsed: 10}
+		txStep, isTx := generalStep.(*mj.TxStep)
+		if isTx {
+			step.TxID = txStep.TxIdent
+		}
+		if step.TxID == "fail" {
+			step.Passed = false
+			step.Error = "wrong result"
+		}
+		if e.listener != nil {
+			e.listener(step)
+		}
+		if !step.Passed {
+			return errors.New(step.Error)
+		}
+	}
+
+	return nil
+}
+
+const reportTransferStep = `{
+	"step": "transfer",
+	"txId": "%s",
+	"tx": {"from": "address:a", "to": "address:b", "egldValue": "1"}
+}`
+
+func writeReportScenarios(t *testing.T) string {
+	dir := t.TempDir()
+	scenarios := map[string]string{
+		"a.scen.json": fmt.Sprintf(`{"name": "passing", "steps": [{"step": "setState"}, %s]}`, fmt.Sprintf(reportTransferStep, "1")),
+		"b.scen.json": fmt.Sprintf(`{"name": "failing", "steps": [%s, %s, %s]}`,
+			fmt.Sprintf(reportTransferStep, "1"), fmt.Sprintf(reportTransferStep, "fail"), fmt.Sprintf(reportTransferStep, "3")),
+		"c.scen.json": `{"name": "excluded", "steps": []}`,
+		"d.scen.json": `{"name": "invalid", "steps": [{"step": "unknown"}]}`,
+	}
+	for file, content := range scenarios {
+		err := ioutil.WriteFile(filepath.Join(dir, file), []byte(content), 0644)
+		require.Nil(t, err)
+	}
+
+	return dir
+}
+
+func runReportScenarios(t *testing.T, parallel int) *ReportCollector {
+	dir := writeReportScenarios(t)
+	collector := NewReportCollector()
+	runner := NewScenarioRunner(&reportingExecutorStub{}, NewDefaultFileResolver())
+	runner.ExecutorFactory = func() (ScenarioExecutor, error) {
+		return &reportingExecutorStub{}, nil
+	}
+	runner.AddReporter(collector)
+
+	options := DefaultRunScenarioOptions()
+	options.Parallel = parallel
+	err := runner.RunAllJSONScenariosInDirectory(dir, "", ".scen.json", []string{"c.scen.json"}, options)
+	require.NotNil(t, err)
+
+	return collector
+}
+
+func TestReportCollector_Reports(t *testing.T) {
+	for _, parallel := range []int{1, 3} {
+		collector := runReportScenarios(t, parallel)
+		require.Len(t, collector.Reports, 4)
+
+		passing := collector.Reports[0]
+		require.Equal(t, "a.scen.json", passing.Path)
+		require.Equal(t, "passing", passing.Name)
+		require.True(t, passing.Passed())
+		require.Nil(t, passing.FailedStep())
+		require.Len(t, passing.Steps, 2)
+		require.Equal(t, uint64(20), passing.GasUsed())
+
+		failing := collector.Reports[1]
+		require.False(t, failing.Passed())
+		require.Len(t, failing.Steps, 2)
+		require.Equal(t, 1, failing.FailedStep().Index)
+		require.Equal(t, "fail", failing.FailedStep().TxID)
+		require.Equal(t, "transfer", failing.FailedStep().Step)
+
+		require.True(t, collector.Reports[2].Skipped)
+
+		invalid := collector.Reports[3]
+		require.NotNil(t, invalid.Err)
+		require.Nil(t, invalid.FailedStep())
+		require.Len(t, invalid.Steps, 0)
+	}
+}
+
+func TestReportCollector_WriteJSONFile(t *testing.T) {
+	collector := runReportScenarios(t, 2)
+	reportPath := filepath.Join(t.TempDir(), "reports", "scenarios.json")
+	err := collector.WriteJSONFile(reportPath)
+	require.Nil(t, err)
+
+	reportJSON, err := os.ReadFile(reportPath)
+	require.Nil(t, err)
+	report := &jsonRunReport{}
+	err = json.Unmarshal(reportJSON, report)
+	require.Nil(t, err)
+
+	require.Equal(t, 1, report.Passed)
+	require.Equal(t, 2, report.Failed)
+	require.Equal(t, 1, report.Skipped)
+	require.Len(t, report.Scenarios, 4)
+	require.Equal(t, reportStatusPass, report.Scenarios[0].Status)
+	require.Nil(t, report.Scenarios[0].FailedStepIndex)
+
+	failing := report.Scenarios[1]
+	require.Equal(t, reportStatusFail, failing.Status)
+	require.Equal(t, "wrong result", failing.Error)
+	require.Equal(t, 1, *failing.FailedStepIndex)
+	require.Equal(t, "fail", failing.FailedTxID)
+	require.Equal(t, uint64(20), failing.GasUsed)
+	require.Equal(t, reportStatusFail, failing.Steps[1].Status)
+	require.Equal(t, reportStatusSkip, report.Scenarios[2].Status)
+}
+
+func TestReportCollector_WriteJUnitFile(t *testing.T) {
+	collector := runReportScenarios(t, 1)
+	reportPath := filepath.Join(t.TempDir(), "scenarios.xml")
+	err := collector.WriteJUnitFile(reportPath)
+	require.Nil(t, err)
+
+	reportXML, err := os.ReadFile(reportPath)
+	require.Nil(t, err)
+	report := &junitTestSuites{}
+	err = xml.Unmarshal(reportXML, report)
+	require.Nil(t, err)
+
+	require.Equal(t, 6, report.Tests)
+	require.Equal(t, 2, report.Failures)
+	require.Equal(t, 1, report.Skipped)
+	require.Len(t, report.TestSuites, 4)
+
+	failing := report.TestSuites[1]
+	require.Equal(t, "b.scen.json", failing.Name)
+	require.Equal(t, 2, failing.Tests)
+	require.Equal(t, "step 1: transfer fail", failing.TestCases[1].Name)
+	require.Equal(t, "wrong result", failing.TestCases[1].Failure.Message)
+	require.Nil(t, failing.TestCases[0].Failure)
+
+	require.NotNil(t, report.TestSuites[2].TestCases[0].Skipped)
+	require.Equal(t, "scenario", report.TestSuites[3].TestCases[0].Name)
+	require.NotNil(t, report.TestSuites[3].TestCases[0].Failure)
+}
@@ -7,10 +7,27 @@ import (
 	"path"
 	"path/filepath"
 	"strings"
+	"sync"
+
+	mj "github.com/multiversx/mx-chain-vm-v1_4-go/scenarios/model"
 )
 
+// scenarioFile is a scenario file found in the directory; the report of its run is sent on done.
+type scenarioFile struct {
+	path        string
+	skipped     bool
+	parsed      bool
+	gasSchedule mj.GasSchedule
+	done        chan *ScenarioReport
+}
+
 // RunAllJSONScenariosInDirectory walks directory, parses and prepares all json scenarios,
 // then calls scenarioExecutor for each of them.
+// Scenarios marked "skip", or left out by the filters of the options, are skipped;
+// if some of the remaining scenarios are marked "only", only those run.
+// With options.Parallel > 1, the scenarios are spread over that many workers, each with an executor from the ExecutorFactory;
+// the results are still printed in the order of the files. The VM hosts of the workers share the opcode costs
+// of the process, so the scenarios only run in parallel if they all have the same gas schedule.
 func (r *ScenarioRunner) RunAllJSONScenariosInDirectory(
 	generalTestPath string,
 	specificTestPath string,
@@ -19,36 +36,227 @@ func (r *ScenarioRunner) RunAllJSONScenariosInDirectory(
 	options *RunScenarioOptions) error {
 
 	mainDirPath := path.Join(generalTestPath, specificTestPath)
+	scenarioFiles, err := findScenarioFiles(mainDirPath, generalTestPath, allowedSuffix, excludedFilePatterns)
+	if err != nil {
+		return err
+	}
+	r.selectScenarioFiles(scenarioFiles, generalTestPath, options)
+	if options.Parallel > 1 {
+		err = checkSameGasSchedule(scenarioFiles, generalTestPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	workers, err := r.createWorkers(options.Parallel)
+	if err != nil {
+		return err
+	}
+	waitWorkers := runScenarioFiles(workers, scenarioFiles, options, len(r.Reporters) > 0)
+
 	var nrPassed, nrFailed, nrSkipped int
+	for _, file := range scenarioFiles {
+		shortPath := shortenTestPath(file.path, generalTestPath)
+		fmt.Printf("Scenario: %s ... ", shortPath)
+		if file.skipped {
+			nrSkipped++
+			fmt.Print("  skip\n")
+			r.notifyReporters(&ScenarioReport{Path: shortPath, Skipped: true})
+			continue
+		}
+
+		report := <-file.done
+		report.Path = shortPath
+		r.notifyReporters(report)
+
+		testErr := report.Err
+		if testErr == nil {
+			nrPassed++
+			fmt.Print("  ok\n")
+		} else {
+			nrFailed++
+			fmt.Printf("  FAIL: %s\n", testErr.Error())
+		}
+	}
+
+	waitWorkers()
+	r.closeWorkers(workers)
+
+	fmt.Printf("Done. Passed: %d. Failed: %d. Skipped: %d.\n", nrPassed, nrFailed, nrSkipped)
+	if nrFailed > 0 {
+		return errors.New("some tests failed")
+	}
+
+	return nil
+}
 
+func findScenarioFiles(
+	mainDirPath string,
+	generalTestPath string,
+	allowedSuffix string,
+	excludedFilePatterns []string) ([]*scenarioFile, error) {
+
+	scenarioFiles := make([]*scenarioFile, 0)
 	err := filepath.Walk(mainDirPath, func(testFilePath string, info os.FileInfo, err error) error {
 		if strings.HasSuffix(testFilePath, allowedSuffix) {
-			fmt.Printf("Scenario: %s ... ", shortenTestPath(testFilePath, generalTestPath))
-			if isExcluded(excludedFilePatterns, testFilePath, generalTestPath) {
-				nrSkipped++
-				fmt.Print("  skip\n")
-			} else {
-				r.Executor.Reset()
-				r.RunsNewTest = true
-				testErr := r.RunSingleJSONScenario(testFilePath, options)
-				if testErr == nil {
-					nrPassed++
-					fmt.Print("  ok\n")
-				} else {
-					nrFailed++
-					fmt.Printf("  FAIL: %s\n", testErr.Error())
-				}
-			}
+			scenarioFiles = append(scenarioFiles, &scenarioFile{
+				path:    testFilePath,
+				skipped: isExcluded(excludedFilePatterns, testFilePath, generalTestPath),
+				done:    make(chan *ScenarioReport, 1),
+			})
 		}
 		return nil
 	})
-	if err != nil {
-		return err
+
+	return scenarioFiles, err
+}
+
+// selectScenarioFiles skips the scenarios that are marked "skip" or left out by the filters, then,
+// if some of the remaining ones are marked "only", all the others.
+// The scenarios that cannot be parsed are kept, to report the error when they run.
+func (r *ScenarioRunner) selectScenarioFiles(scenarioFiles []*scenarioFile, generalTestPath string, options *RunScenarioOptions) {
+	focused := make(map[*scenarioFile]bool)
+	for _, file := range scenarioFiles {
+		if file.skipped {
+			continue
+		}
+
+		scenario, err := ParseScenariosScenario(r.Parser, file.path)
+		if err != nil {
+			continue
+		}
+
+		file.parsed = true
+		file.gasSchedule = scenario.GasSchedule
+		file.skipped = !isScenarioSelected(scenario, shortenTestPath(file.path, generalTestPath), options)
+		if !file.skipped && scenario.Only {
+			focused[file] = true
+		}
 	}
-	fmt.Printf("Done. Passed: %d. Failed: %d. Skipped: %d.\n", nrPassed, nrFailed, nrSkipped)
-	if nrFailed > 0 {
-		return errors.New("some tests failed")
+
+	if len(focused) == 0 {
+		return
+	}
+	for _, file := range scenarioFiles {
+		file.skipped = file.skipped || !focused[file]
+	}
+}
+
+// checkSameGasSchedule returns an error naming two selected scenarios with different gas schedules, if there are any.
+func checkSameGasSchedule(scenarioFiles []*scenarioFile, generalTestPath string) error {
+	var first *scenarioFile
+	for _, file := range scenarioFiles {
+		if file.skipped || !file.parsed {
+			continue
+		}
+		if first == nil {
+			first = file
+			continue
+		}
+		if file.gasSchedule != first.gasSchedule {
+			return fmt.Errorf("scenarios with different gas schedules cannot run in parallel: %s and %s",
+				shortenTestPath(first.path, generalTestPath), shortenTestPath(file.path, generalTestPath))
+		}
 	}
 
 	return nil
 }
+
+func isScenarioSelected(scenario *mj.Scenario, shortPath string, options *RunScenarioOptions) bool {
+	if scenario.Skip {
+		return false
+	}
+	if options.RunFilter != nil && !options.RunFilter.MatchString(scenario.Name) && !options.RunFilter.MatchString(shortPath) {
+		return false
+	}
+	if len(options.IncludeTags) > 0 && !hasAnyTag(scenario, options.IncludeTags) {
+		return false
+	}
+
+	return !hasAnyTag(scenario, options.ExcludeTags)
+}
+
+func hasAnyTag(scenario *mj.Scenario, tags []string) bool {
+	for _, scenarioTag := range scenario.Tags {
+		for _, tag := range tags {
+			if scenarioTag == tag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// createWorkers returns the runner itself when the scenarios run one after another,
+// or a runner per worker, each with its own executor and parser.
+func (r *ScenarioRunner) createWorkers(parallel int) ([]*ScenarioRunner, error) {
+	if parallel <= 1 {
+		return []*ScenarioRunner{r}, nil
+	}
+	if r.ExecutorFactory == nil {
+		return nil, errors.New("running scenarios in parallel requires an executor factory")
+	}
+
+	workers := make([]*ScenarioRunner, 0, parallel)
+	for i := 0; i < parallel; i++ {
+		executor, err := r.ExecutorFactory()
+		if err != nil {
+			r.closeWorkers(workers)
+			return nil, err
+		}
+
+		parser := r.Parser
+		parser.ExprInterpreter.FileResolver = r.Parser.ExprInterpreter.FileResolver.Clone()
+		workers = append(workers, &ScenarioRunner{
+			Executor: executor,
+			Parser:   parser,
+		})
+	}
+
+	return workers, nil
+}
+
+// closeWorkers closes the executors created for the workers, if they can be closed.
+func (r *ScenarioRunner) closeWorkers(workers []*ScenarioRunner) {
+	for _, worker := range workers {
+		if worker == r {
+			continue
+		}
+
+		closer, ok := worker.Executor.(interface{ Close() })
+		if ok {
+			closer.Close()
+		}
+	}
+}
+
+// runScenarioFiles starts the workers on the files that are not excluded, and returns a function waiting for all of them.
+func runScenarioFiles(
+	workers []*ScenarioRunner,
+	scenarioFiles []*scenarioFile,
+	options *RunScenarioOptions,
+	reportSteps bool) func() {
+
+	queue := make(chan *scenarioFile, len(scenarioFiles))
+	for _, file := range scenarioFiles {
+		if !file.skipped {
+			queue <- file
+		}
+	}
+	close(queue)
+
+	var wg sync.WaitGroup
+	wg.Add(len(workers))
+	for _, worker := range workers {
+		go func(worker *ScenarioRunner) {
+			defer wg.Done()
+			for file := range queue {
+				worker.Executor.Reset()
+				worker.RunsNewTest = true
+				file.done <- worker.runJSONScenario(file.path, options, reportSteps)
+			}
+		}(worker)
+	}
+
+	return wg.Wait
+}
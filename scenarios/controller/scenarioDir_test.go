@@ -0,0 +1,263 @@
+package scencontroller
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"testing"
+
+	fr "github.com/multiversx/mx-chain-vm-v1_4-go/scenarios/fileresolver"
+	mj "github.com/multiversx/mx-chain-vm-v1_4-go/scenarios/model"
+	"github.com/stretchr/testify/require"
+)
+
+// executorStub records the scenarios it executes, failing the ones named "fail"
+type executorStub struct {
+	mutex    *sync.Mutex
+	executed map[string]*executorStub
+	closed   bool
+}
+
+func (e *executorStub) Reset() {
+}
+
+func (e *executorStub) ExecuteScenario(scenario *mj.Scenario, _ fr.FileResolver) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.executed[scenario.Comment] = e
+	if scenario.Name == "fail" {
+		return errors.New("failed")
+	}
+	return nil
+}
+
+func (e *executorStub) Close() {
+	e.closed = true
+}
+
+func writeScenarioFiles(t *testing.T, numFiles int, failing int) string {
+	dir := t.TempDir()
+	for i := 0; i < numFiles; i++ {
+		name := "ok"
+		if i == failing {
+			name = "fail"
+		}
+		content := fmt.Sprintf(`{"name": "%s", "comment": "%d", "steps": []}`, name, i)
+		err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("s%02d.scen.json", i)), []byte(content), 0644)
+		require.Nil(t, err)
+	}
+
+	return dir
+}
+
+func TestRunAllJSONScenariosInDirectory_Parallel(t *testing.T) {
+	dir := writeScenarioFiles(t, 20, -1)
+	mutex := &sync.Mutex{}
+	executed := make(map[string]*executorStub)
+	executors := make([]*executorStub, 0)
+
+	runner := NewScenarioRunner(&executorStub{mutex: mutex, executed: executed}, NewDefaultFileResolver())
+	runner.ExecutorFactory = func() (ScenarioExecutor, error) {
+		executor := &executorStub{mutex: mutex, executed: executed}
+		executors = append(executors, executor)
+		return executor, nil
+	}
+
+	options := DefaultRunScenarioOptions()
+	options.Parallel = 4
+	err := runner.RunAllJSONScenariosInDirectory(dir, "", ".scen.json", []string{"s03.scen.json"}, options)
+	require.Nil(t, err)
+	require.Len(t, executors, 4)
+	require.Len(t, executed, 19)
+	require.NotContains(t, executed, "3")
+	for _, executor := range executors {
+		require.True(t, executor.closed)
+	}
+	for _, executor := range executed {
+		require.NotEqual(t, runner.Executor, executor)
+	}
+}
+
+func TestRunAllJSONScenariosInDirectory_ParallelFailure(t *testing.T) {
+	dir := writeScenarioFiles(t, 5, 2)
+	mutex := &sync.Mutex{}
+	executed := make(map[string]*executorStub)
+
+	runner := NewScenarioRunner(&executorStub{mutex: mutex, executed: executed}, NewDefaultFileResolver())
+	options := DefaultRunScenarioOptions()
+	options.Parallel = 2
+	err := runner.RunAllJSONScenariosInDirectory(dir, "", ".scen.json", []string{}, options)
+	require.NotNil(t, err)
+	require.Len(t, executed, 0)
+
+	runner.ExecutorFactory = func() (ScenarioExecutor, error) {
+		return &executorStub{mutex: mutex, executed: executed}, nil
+	}
+	err = runner.RunAllJSONScenariosInDirectory(dir, "", ".scen.json", []string{}, options)
+	require.Equal(t, "some tests failed", err.Error())
+	require.Len(t, executed, 5)
+}
+
+func TestRunAllJSONScenariosInDirectory_ParallelGasSchedules(t *testing.T) {
+	dir := t.TempDir()
+	scenarios := map[string]string{
+		"v3.scen.json":      `{"comment": "v3", "gasSchedule": "v3", "steps": []}`,
+		"v4.scen.json":      `{"comment": "v4", "gasSchedule": "v4", "steps": []}`,
+		"v4-also.scen.json": `{"comment": "v4-also", "gasSchedule": "v4", "steps": []}`,
+	}
+	for file, content := range scenarios {
+		err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644)
+		require.Nil(t, err)
+	}
+
+	mutex := &sync.Mutex{}
+	executed := make(map[string]*executorStub)
+	numExecutors := 0
+	runner := NewScenarioRunner(&executorStub{mutex: mutex, executed: executed}, NewDefaultFileResolver())
+	runner.ExecutorFactory = func() (ScenarioExecutor, error) {
+		numExecutors++
+		return &executorStub{mutex: mutex, executed: executed}, nil
+	}
+
+	options := DefaultRunScenarioOptions()
+	options.Parallel = 2
+	err := runner.RunAllJSONScenariosInDirectory(dir, "", ".scen.json", []string{}, options)
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), "different gas schedules")
+	require.Equal(t, 0, numExecutors)
+	require.Len(t, executed, 0)
+
+	err = runner.RunAllJSONScenariosInDirectory(dir, "", ".scen.json", []string{"v3.scen.json"}, options)
+	require.Nil(t, err)
+	require.Equal(t, 2, numExecutors)
+	require.Len(t, executed, 2)
+
+	err = runner.RunAllJSONScenariosInDirectory(dir, "", ".scen.json", []string{}, DefaultRunScenarioOptions())
+	require.Nil(t, err)
+	require.Len(t, executed, 3)
+}
+
+func TestRunAllJSONScenariosInDirectory_Sequential(t *testing.T) {
+	dir := writeScenarioFiles(t, 3, -1)
+	mutex := &sync.Mutex{}
+	executed := make(map[string]*executorStub)
+	executor := &executorStub{mutex: mutex, executed: executed}
+
+	runner := NewScenarioRunner(executor, NewDefaultFileResolver())
+	err := runner.RunAllJSONScenariosInDirectory(dir, "", ".scen.json", []string{}, DefaultRunScenarioOptions())
+	require.Nil(t, err)
+	require.Len(t, executed, 3)
+	for _, executedBy := range executed {
+		require.Equal(t, executor, executedBy)
+	}
+	require.False(t, executor.closed)
+}
+
+func TestRunAllJSONScenariosInDirectory_Selection(t *testing.T) {
+	dir := t.TempDir()
+	scenarios := map[string]string{
+		"fast.scen.json":    `{"name": "transfer fast", "comment": "fast", "tags": ["fast"], "steps": []}`,
+		"slow.scen.json":    `{"name": "transfer slow", "comment": "slow", "tags": ["slow", "fast"], "steps": []}`,
+		"skipped.scen.json": `{"name": "transfer skipped", "comment": "skipped", "skip": true, "steps": []}`,
+		"deploy.scen.json":  `{"name": "deploy", "comment": "deploy", "steps": []}`,
+	}
+	for file, content := range scenarios {
+		err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644)
+		require.Nil(t, err)
+	}
+
+	runSelected := func(options *RunScenarioOptions) []string {
+		executed := make(map[string]*executorStub)
+		runner := NewScenarioRunner(&executorStub{mutex: &sync.Mutex{}, executed: executed}, NewDefaultFileResolver())
+		err := runner.RunAllJSONScenariosInDirectory(dir, "", ".scen.json", []string{}, options)
+		require.Nil(t, err)
+
+		names := make([]string, 0)
+		for name := range executed {
+			names = append(names, name)
+		}
+		return names
+	}
+
+	options := DefaultRunScenarioOptions()
+	require.ElementsMatch(t, []string{"fast", "slow", "deploy"}, runSelected(options))
+
+	options.RunFilter = regexp.MustCompile("^transfer")
+	require.ElementsMatch(t, []string{"fast", "slow"}, runSelected(options))
+
+	options.RunFilter = regexp.MustCompile("deploy.scen")
+	require.ElementsMatch(t, []string{"deploy"}, runSelected(options))
+
+	options = DefaultRunScenarioOptions()
+	options.IncludeTags = []string{"fast"}
+	options.ExcludeTags = []string{"slow"}
+	require.ElementsMatch(t, []string{"fast"}, runSelected(options))
+
+	err := os.WriteFile(filepath.Join(dir, "deploy.scen.json"), []byte(`{"comment": "deploy", "only": true, "steps": []}`), 0644)
+	require.Nil(t, err)
+	require.ElementsMatch(t, []string{"deploy"}, runSelected(DefaultRunScenarioOptions()))
+
+	options = DefaultRunScenarioOptions()
+	options.IncludeTags = []string{"fast"}
+	require.ElementsMatch(t, []string{"fast", "slow"}, runSelected(options))
+}
+
+func TestRunSingleJSONScenario_Selection(t *testing.T) {
+	dir := t.TempDir()
+	scenarios := map[string]string{
+		"fast.scen.json":    `{"name": "transfer fast", "comment": "fast", "tags": ["fast"], "steps": []}`,
+		"skipped.scen.json": `{"name": "transfer skipped", "comment": "skipped", "skip": true, "steps": []}`,
+		"only.scen.json":    `{"name": "deploy", "comment": "only", "only": true, "steps": []}`,
+	}
+	for file, content := range scenarios {
+		err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0644)
+		require.Nil(t, err)
+	}
+
+	runSingle := func(file string, options *RunScenarioOptions) (bool, *ScenarioReport) {
+		executed := make(map[string]*executorStub)
+		collector := NewReportCollector()
+		runner := NewScenarioRunner(&executorStub{mutex: &sync.Mutex{}, executed: executed}, NewDefaultFileResolver())
+		runner.AddReporter(collector)
+		err := runner.RunSingleJSONScenario(filepath.Join(dir, file), options)
+		require.Nil(t, err)
+		require.Len(t, collector.Reports, 1)
+
+		return len(executed) == 1, collector.Reports[0]
+	}
+
+	executed, report := runSingle("fast.scen.json", DefaultRunScenarioOptions())
+	require.True(t, executed)
+	require.False(t, report.Skipped)
+
+	executed, report = runSingle("skipped.scen.json", DefaultRunScenarioOptions())
+	require.False(t, executed)
+	require.True(t, report.Skipped)
+
+	executed, _ = runSingle("only.scen.json", DefaultRunScenarioOptions())
+	require.True(t, executed)
+
+	options := DefaultRunScenarioOptions()
+	options.RunFilter = regexp.MustCompile("^deploy")
+	executed, report = runSingle("fast.scen.json", options)
+	require.False(t, executed)
+	require.True(t, report.Skipped)
+	executed, _ = runSingle("only.scen.json", options)
+	require.True(t, executed)
+
+	options = DefaultRunScenarioOptions()
+	options.IncludeTags = []string{"fast"}
+	executed, _ = runSingle("fast.scen.json", options)
+	require.True(t, executed)
+	executed, _ = runSingle("only.scen.json", options)
+	require.False(t, executed)
+
+	options = DefaultRunScenarioOptions()
+	options.ExcludeTags = []string{"fast"}
+	executed, _ = runSingle("fast.scen.json", options)
+	require.False(t, executed)
+}
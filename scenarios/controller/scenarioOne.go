@@ -1,11 +1,24 @@
 package scencontroller
 
 import (
+	"fmt"
+	"regexp"
+	"time"
+
 	mj "github.com/multiversx/mx-chain-vm-v1_4-go/scenarios/model"
 )
 
 type RunScenarioOptions struct {
 	ForceTraceGas bool
+	// Parallel is the number of scenario files run at the same time, each worker on its own executor.
+	// 0 or 1 runs them one after another, on the executor of the runner.
+	Parallel int
+	// RunFilter selects the scenarios whose name or path matches it.
+	RunFilter *regexp.Regexp
+	// IncludeTags selects the scenarios having at least one of these tags.
+	IncludeTags []string
+	// ExcludeTags leaves out the scenarios having any of these tags.
+	ExcludeTags []string
 }
 
 func applyScenarioOptions(scenario *mj.Scenario, options *RunScenarioOptions) {
@@ -17,16 +30,61 @@ func applyScenarioOptions(scenario *mj.Scenario, options *RunScenarioOptions) {
 func DefaultRunScenarioOptions() *RunScenarioOptions {
 	return &RunScenarioOptions{
 		ForceTraceGas: false,
+		Parallel:      1,
 	}
 }
 
 // RunSingleJSONScenario parses and prepares test, then calls testCallback.
+// A scenario marked "skip", or left out by the filters of the options, is reported as skipped;
+// the "only" marker changes nothing, since the scenario is the only one that runs.
 func (r *ScenarioRunner) RunSingleJSONScenario(contextPath string, options *RunScenarioOptions) error {
+	if !r.isSingleScenarioSelected(contextPath, options) {
+		fmt.Printf("Scenario: %s ...   skip\n", contextPath)
+		r.notifyReporters(&ScenarioReport{Path: contextPath, Skipped: true})
+		return nil
+	}
+
+	report := r.runJSONScenario(contextPath, options, len(r.Reporters) > 0)
+	r.notifyReporters(report)
+	return report.Err
+}
+
+// RunJSONScenarioSteps runs the scenario whatever its markers and the filters of the options, without reporting it.
+// It runs the external steps of other scenarios, which are skipped by the markers of the step instead.
+func (r *ScenarioRunner) RunJSONScenarioSteps(contextPath string, options *RunScenarioOptions) error {
+	return r.runJSONScenario(contextPath, options, false).Err
+}
+
+// isSingleScenarioSelected applies the selection of the directory runs to a single scenario.
+// A scenario that cannot be parsed is selected, to report the error when it runs.
+func (r *ScenarioRunner) isSingleScenarioSelected(contextPath string, options *RunScenarioOptions) bool {
+	scenario, err := ParseScenariosScenario(r.Parser, contextPath)
+	if err != nil {
+		return true
+	}
+
+	return isScenarioSelected(scenario, contextPath, options)
+}
+
+// runJSONScenario runs the scenario and returns its report;
+// the steps are only reported if requested and if the executor can report them.
+func (r *ScenarioRunner) runJSONScenario(contextPath string, options *RunScenarioOptions, reportSteps bool) *ScenarioReport {
+	report := &ScenarioReport{
+		Path:  contextPath,
+		Steps: make([]*StepReport, 0),
+	}
+	start := time.Now()
+	defer func() {
+		report.Duration = time.Since(start)
+	}()
+
 	scenario, parseErr := ParseScenariosScenario(r.Parser, contextPath)
 
 	if parseErr != nil {
-		return parseErr
+		report.Err = parseErr
+		return report
 	}
+	report.Name = scenario.Name
 
 	if r.RunsNewTest {
 		scenario.IsNewTest = true
@@ -35,5 +93,14 @@ func (r *ScenarioRunner) RunSingleJSONScenario(contextPath string, options *RunS
 
 	applyScenarioOptions(scenario, options)
 
-	return r.Executor.ExecuteScenario(scenario, r.Parser.ExprInterpreter.FileResolver)
+	reportingExecutor, canReportSteps := r.Executor.(StepReportingExecutor)
+	if reportSteps && canReportSteps {
+		reportingExecutor.SetStepListener(func(step *StepReport) {
+			report.Steps = append(report.Steps, step)
+		})
+		defer reportingExecutor.SetStepListener(nil)
+	}
+
+	report.Err = r.Executor.ExecuteScenario(scenario, r.Parser.ExprInterpreter.FileResolver)
+	return report
 }
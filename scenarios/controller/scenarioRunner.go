@@ -17,11 +17,17 @@ type ScenarioExecutor interface {
 	ExecuteScenario(*mj.Scenario, fr.FileResolver) error
 }
 
+// ScenarioExecutorFactory creates the executors of the workers, when scenarios run in parallel.
+// Executors that have a Close() method are closed once their worker is done.
+type ScenarioExecutorFactory func() (ScenarioExecutor, error)
+
 // ScenarioRunner is a component that can run json scenarios, using a provided executor.
 type ScenarioRunner struct {
-	Executor    ScenarioExecutor
-	RunsNewTest bool
-	Parser      mjparse.Parser
+	Executor        ScenarioExecutor
+	ExecutorFactory ScenarioExecutorFactory
+	RunsNewTest     bool
+	Parser          mjparse.Parser
+	Reporters       []Reporter
 }
 
 // NewScenarioRunner creates new ScenarioRunner instance.
@@ -31,3 +37,14 @@ func NewScenarioRunner(executor ScenarioExecutor, fileResolver fr.FileResolver)
 		Parser:   mjparse.NewParser(fileResolver),
 	}
 }
+
+// AddReporter registers a reporter, notified after each scenario.
+func (r *ScenarioRunner) AddReporter(reporter Reporter) {
+	r.Reporters = append(r.Reporters, reporter)
+}
+
+func (r *ScenarioRunner) notifyReporters(report *ScenarioReport) {
+	for _, reporter := range r.Reporters {
+		reporter.ScenarioFinished(report)
+	}
+}
@@ -29,3 +29,29 @@ func TestWriteScenario(t *testing.T) {
 
 	require.Equal(t, contents, []byte(serialized))
 }
+
+func TestWriteScenario_TagsAndFocus(t *testing.T) {
+	contents := `{
+    "name": "focused",
+    "tags": [
+        "esdt",
+        "slow"
+    ],
+    "skip": true,
+    "only": true,
+    "steps": [
+        {
+            "step": "externalSteps",
+            "path": "a.steps.json",
+            "skip": true,
+            "only": true
+        }
+    ]
+}
+`
+
+	p := mjparse.NewParser(fr.NewDefaultFileResolver())
+	scenario, parseErr := p.ParseScenarioFile([]byte(contents))
+	require.Nil(t, parseErr)
+	require.Equal(t, contents, mjwrite.ScenarioToJSONString(scenario))
+}
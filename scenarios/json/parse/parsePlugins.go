@@ -0,0 +1,160 @@
+package scenjsonparse
+
+import (
+	"errors"
+	"fmt"
+
+	mj "github.com/multiversx/mx-chain-vm-v1_4-go/scenarios/model"
+	oj "github.com/multiversx/mx-chain-vm-v1_4-go/scenarios/orderedjson"
+)
+
+func (p *Parser) processPluginMocks(pluginsRaw oj.OJsonObject) ([]*mj.PluginMock, error) {
+	pluginsMap, isMap := pluginsRaw.(*oj.OJsonMap)
+	if !isMap {
+		return nil, errors.New("unmarshalled plugins object is not a map")
+	}
+
+	var plugins []*mj.PluginMock
+	for _, pluginKvp := range pluginsMap.OrderedKV {
+		methodsMap, isMap := pluginKvp.Value.(*oj.OJsonMap)
+		if !isMap {
+			return nil, fmt.Errorf("methods of plugin %s are not a map", pluginKvp.Key)
+		}
+
+		plugin := &mj.PluginMock{Name: pluginKvp.Key}
+		for _, methodKvp := range methodsMap.OrderedKV {
+			responses, err := p.processPluginResponseMocks(methodKvp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid responses of plugin method %s/%s: %w", pluginKvp.Key, methodKvp.Key, err)
+			}
+			plugin.Methods = append(plugin.Methods, &mj.PluginMethodMock{
+				Name:      methodKvp.Key,
+				Responses: responses,
+			})
+		}
+		plugins = append(plugins, plugin)
+	}
+
+	return plugins, nil
+}
+
+// processPluginResponseMocks accepts either a single response or a list of responses
+func (p *Parser) processPluginResponseMocks(responsesRaw oj.OJsonObject) ([]*mj.PluginResponseMock, error) {
+	responsesList, isList := responsesRaw.(*oj.OJsonList)
+	if !isList {
+		response, err := p.processPluginResponseMock(responsesRaw)
+		if err != nil {
+			return nil, err
+		}
+		return []*mj.PluginResponseMock{response}, nil
+	}
+
+	var responses []*mj.PluginResponseMock
+	for _, responseRaw := range responsesList.AsList() {
+		response, err := p.processPluginResponseMock(responseRaw)
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, response)
+	}
+
+	return responses, nil
+}
+
+func (p *Parser) processPluginResponseMock(responseRaw oj.OJsonObject) (*mj.PluginResponseMock, error) {
+	responseMap, isMap := responseRaw.(*oj.OJsonMap)
+	if !isMap {
+		return nil, errors.New("plugin response is not a map")
+	}
+
+	response := &mj.PluginResponseMock{
+		Args:      mj.JSONBytesFromTree{Unspecified: true},
+		Result:    mj.JSONBytesFromTree{Unspecified: true},
+		UserError: mj.JSONBytesEmpty(),
+	}
+	var err error
+	for _, kvp := range responseMap.OrderedKV {
+		switch kvp.Key {
+		case "args":
+			response.Args, err = p.processSubTreeAsByteArray(kvp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid plugin response args: %w", err)
+			}
+		case "result":
+			response.Result, err = p.processSubTreeAsByteArray(kvp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid plugin response result: %w", err)
+			}
+		case "userError":
+			response.UserError, err = p.processStringAsByteArray(kvp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid plugin response userError: %w", err)
+			}
+		default:
+			return nil, fmt.Errorf("unknown plugin response field: %s", kvp.Key)
+		}
+	}
+
+	if !response.Result.Unspecified && !response.UserError.Unspecified {
+		return nil, errors.New("plugin response cannot have both result and userError")
+	}
+
+	return response, nil
+}
+
+func (p *Parser) processPluginCallList(callsRaw oj.OJsonObject) (*mj.PluginCallList, error) {
+	if IsStar(callsRaw) {
+		return &mj.PluginCallList{IsStar: true}, nil
+	}
+
+	callsList, isList := callsRaw.(*oj.OJsonList)
+	if !isList {
+		return nil, errors.New("unmarshalled plugin calls list is not a list")
+	}
+
+	result := &mj.PluginCallList{}
+	var err error
+	for _, callRaw := range callsList.AsList() {
+		switch callItem := callRaw.(type) {
+		case *oj.OJsonString:
+			if callItem.Value != "+" {
+				return nil, errors.New("unmarshalled plugin call entry is an invalid string")
+			}
+			result.MoreAllowedAtEnd = true
+		case *oj.OJsonMap:
+			if result.MoreAllowedAtEnd {
+				return nil, errors.New("plugin call entry found after \"+\"")
+			}
+
+			callEntry := &mj.PluginCallCheck{
+				Args: mj.JSONCheckBytesStar(),
+			}
+			for _, kvp := range callItem.OrderedKV {
+				switch kvp.Key {
+				case "plugin":
+					callEntry.Plugin, err = p.parseString(kvp.Value)
+					if err != nil {
+						return nil, fmt.Errorf("invalid plugin call plugin name: %w", err)
+					}
+				case "method":
+					callEntry.Method, err = p.parseString(kvp.Value)
+					if err != nil {
+						return nil, fmt.Errorf("invalid plugin call method name: %w", err)
+					}
+				case "args":
+					callEntry.Args, err = p.parseCheckBytes(kvp.Value)
+					if err != nil {
+						return nil, fmt.Errorf("invalid plugin call args: %w", err)
+					}
+				default:
+					return nil, fmt.Errorf("unknown plugin call field: %s", kvp.Key)
+				}
+			}
+			result.List = append(result.List, callEntry)
+		default:
+			return nil, errors.New("plugin call entry should be either string or object")
+		}
+	}
+
+	return result, nil
+}
@@ -38,6 +38,21 @@ func (p *Parser) ParseScenarioFile(jsonString []byte) (*mj.Scenario, error) {
 			if err != nil {
 				return nil, fmt.Errorf("bad scenario comment: %w", err)
 			}
+		case "tags":
+			scenario.Tags, err = p.processStringList(kvp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("bad scenario tags: %w", err)
+			}
+		case "skip":
+			scenario.Skip, err = p.parseBool(kvp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("bad scenario skip flag: %w", err)
+			}
+		case "only":
+			scenario.Only, err = p.parseBool(kvp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("bad scenario only flag: %w", err)
+			}
 		case "checkGas":
 			checkGasOJ, isBool := kvp.Value.(*oj.OJsonBool)
 			if !isBool {
@@ -159,6 +174,16 @@ func (p *Parser) processScenarioStep(stepObj oj.OJsonObject) (mj.Step, error) {
 				if err != nil {
 					return nil, fmt.Errorf("bad externalSteps path: %w", err)
 				}
+			case "skip":
+				step.Skip, err = p.parseBool(kvp.Value)
+				if err != nil {
+					return nil, fmt.Errorf("bad externalSteps skip flag: %w", err)
+				}
+			case "only":
+				step.Only, err = p.parseBool(kvp.Value)
+				if err != nil {
+					return nil, fmt.Errorf("bad externalSteps only flag: %w", err)
+				}
 			default:
 				return nil, fmt.Errorf("invalid externalSteps field: %s", kvp.Key)
 			}
@@ -204,6 +229,11 @@ func (p *Parser) processScenarioStep(stepObj oj.OJsonObject) (mj.Step, error) {
 				if err != nil {
 					return nil, fmt.Errorf("error parsing block hashes: %w", err)
 				}
+			case "plugins":
+				step.Plugins, err = p.processPluginMocks(kvp.Value)
+				if err != nil {
+					return nil, fmt.Errorf("error parsing plugins: %w", err)
+				}
 			default:
 				return nil, fmt.Errorf("invalid set state field: %s", kvp.Key)
 			}
@@ -229,6 +259,11 @@ func (p *Parser) processScenarioStep(stepObj oj.OJsonObject) (mj.Step, error) {
 				if err != nil {
 					return nil, fmt.Errorf("cannot parse check state step: %w", err)
 				}
+			case "pluginCalls":
+				step.PluginCalls, err = p.processPluginCallList(kvp.Value)
+				if err != nil {
+					return nil, fmt.Errorf("cannot parse check state plugin calls: %w", err)
+				}
 			default:
 				return nil, fmt.Errorf("invalid check state field: %s", kvp.Key)
 			}
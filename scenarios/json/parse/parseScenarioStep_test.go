@@ -55,3 +55,131 @@ func TestParseScenario(t *testing.T) {
 	require.Equal(t, "scCall", step.StepTypeName())
 	require.Equal(t, true, step.(*mj.TxStep).DisplayLogs)
 }
+
+func TestParseScenario_SetStatePlugins(t *testing.T) {
+	snippet := `
+	{
+		"step": "setState",
+		"plugins": {
+			"oracle": {
+				"getPrice": {
+					"args": "str:EGLD",
+					"result": "u64:1000"
+				},
+				"getVolume": [
+					{
+						"args": "str:EGLD",
+						"userError": "str:market closed"
+					},
+					{
+						"result": "0"
+					}
+				]
+			}
+		}
+	}`
+
+	p := Parser{}
+	step, parseErr := p.ParseScenarioStep(snippet)
+	require.Nil(t, parseErr)
+	plugins := step.(*mj.SetStateStep).Plugins
+	require.Len(t, plugins, 1)
+	require.Equal(t, "oracle", plugins[0].Name)
+	require.Len(t, plugins[0].Methods, 2)
+
+	getPrice := plugins[0].Methods[0]
+	require.Equal(t, "getPrice", getPrice.Name)
+	require.Len(t, getPrice.Responses, 1)
+	require.Equal(t, []byte("EGLD"), getPrice.Responses[0].Args.Value)
+	require.Equal(t, []byte{0, 0, 0, 0, 0, 0, 0x03, 0xe8}, getPrice.Responses[0].Result.Value)
+	require.True(t, getPrice.Responses[0].UserError.Unspecified)
+
+	getVolume := plugins[0].Methods[1]
+	require.Len(t, getVolume.Responses, 2)
+	require.Equal(t, []byte("market closed"), getVolume.Responses[0].UserError.Value)
+	require.True(t, getVolume.Responses[1].Args.Unspecified)
+
+	_, parseErr = p.ParseScenarioStep(`{
+		"step": "setState",
+		"plugins": {"oracle": {"getPrice": {"result": "1", "userError": "str:fail"}}}
+	}`)
+	require.NotNil(t, parseErr)
+}
+
+func TestParseScenario_PluginCalls(t *testing.T) {
+	snippet := `
+	{
+		"step": "scCall",
+		"tx": {
+			"from": "address:owner",
+			"to": "sc:oracle-user",
+			"function": "readPrice",
+			"arguments": [],
+			"gasLimit": "5,000,000",
+			"gasPrice": "0"
+		},
+		"expect": {
+			"out": [],
+			"pluginCalls": [
+				{
+					"plugin": "oracle",
+					"method": "getPrice",
+					"args": "str:EGLD"
+				},
+				{
+					"plugin": "oracle",
+					"method": "getVolume"
+				},
+				"+"
+			]
+		}
+	}`
+
+	p := Parser{}
+	step, parseErr := p.ParseScenarioStep(snippet)
+	require.Nil(t, parseErr)
+	calls := step.(*mj.TxStep).ExpectedResult.PluginCalls
+	require.NotNil(t, calls)
+	require.True(t, calls.MoreAllowedAtEnd)
+	require.Len(t, calls.List, 2)
+	require.True(t, calls.List[0].Args.Check([]byte("EGLD")))
+	require.False(t, calls.List[0].Args.Check([]byte("BTC")))
+	require.True(t, calls.List[1].Args.IsStar)
+
+	step, parseErr = p.ParseScenarioStep(`{"step": "checkState", "pluginCalls": "*"}`)
+	require.Nil(t, parseErr)
+	require.Nil(t, step.(*mj.CheckStateStep).CheckAccounts)
+	require.True(t, step.(*mj.CheckStateStep).PluginCalls.IsStar)
+}
+
+func TestParseScenario_TagsAndFocus(t *testing.T) {
+	snippet := `
+	{
+		"name": "focused",
+		"tags": ["esdt", "slow"],
+		"only": true,
+		"steps": [
+			{"step": "externalSteps", "path": "a.steps.json", "skip": true},
+			{"step": "externalSteps", "path": "b.steps.json", "only": true},
+			{"step": "externalSteps", "path": "c.steps.json"}
+		]
+	}`
+
+	p := Parser{}
+	scenario, parseErr := p.ParseScenarioFile([]byte(snippet))
+	require.Nil(t, parseErr)
+	require.Equal(t, []string{"esdt", "slow"}, scenario.Tags)
+	require.True(t, scenario.Only)
+	require.False(t, scenario.Skip)
+	require.Len(t, scenario.Steps, 3)
+
+	require.True(t, scenario.SkipsExternalStep(scenario.Steps[0].(*mj.ExternalStepsStep)))
+	require.False(t, scenario.SkipsExternalStep(scenario.Steps[1].(*mj.ExternalStepsStep)))
+	require.True(t, scenario.SkipsExternalStep(scenario.Steps[2].(*mj.ExternalStepsStep)))
+
+	scenario.Steps[1].(*mj.ExternalStepsStep).Only = false
+	require.False(t, scenario.SkipsExternalStep(scenario.Steps[2].(*mj.ExternalStepsStep)))
+
+	_, parseErr = p.ParseScenarioFile([]byte(`{"skip": "yes", "steps": []}`))
+	require.NotNil(t, parseErr)
+}
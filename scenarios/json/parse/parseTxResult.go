@@ -54,6 +54,11 @@ func (p *Parser) processTxExpectedResult(blrRaw oj.OJsonObject) (*mj.Transaction
 			if err != nil {
 				return nil, fmt.Errorf("invalid block result refund: %w", err)
 			}
+		case "pluginCalls":
+			blr.PluginCalls, err = p.processPluginCallList(kvp.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid block result plugin calls: %w", err)
+			}
 		default:
 			return nil, fmt.Errorf("unknown tx result field: %s", kvp.Key)
 		}
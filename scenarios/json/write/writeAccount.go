@@ -23,7 +23,7 @@ func AccountsToOJ(accounts []*mj.Account) oj.OJsonObject {
 			acctOJ.Put("balance", bigIntToOJ(account.Balance))
 		}
 		if len(account.ESDTData) > 0 {
-			acctOJ.Put("esdt", esdtDataToOJ(account.ESDTData))
+			acctOJ.Put("esdt", ESDTDataToOJ(account.ESDTData))
 		}
 		storageOJ := oj.NewMap()
 		for _, st := range account.Storage {
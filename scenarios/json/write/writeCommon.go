@@ -32,6 +32,9 @@ func resultToOJ(res *mj.TransactionResult) oj.OJsonObject {
 	if !res.Refund.IsUnspecified() {
 		resultOJ.Put("refund", checkBigIntToOJ(res.Refund))
 	}
+	if res.PluginCalls != nil {
+		resultOJ.Put("pluginCalls", pluginCallsToOJ(res.PluginCalls))
+	}
 
 	return resultOJ
 }
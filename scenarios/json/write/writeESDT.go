@@ -32,7 +32,8 @@ func esdtTxRawEntryToOJ(esdtItemRaw *mj.ESDTTxData) *oj.OJsonMap {
 	return esdtItemOJ
 }
 
-func esdtDataToOJ(esdtItems []*mj.ESDTData) *oj.OJsonMap {
+// ESDTDataToOJ converts scenarios-format ESDT tokens to an ordered JSON map, keyed by token identifier.
+func ESDTDataToOJ(esdtItems []*mj.ESDTData) *oj.OJsonMap {
 	esdtItemsOJ := oj.NewMap()
 	for _, esdtItem := range esdtItems {
 		esdtItemsOJ.Put(esdtItem.TokenIdentifier.Original, esdtItemToOJ(esdtItem))
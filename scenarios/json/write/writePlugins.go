@@ -0,0 +1,68 @@
+package scenjsonwrite
+
+import (
+	mj "github.com/multiversx/mx-chain-vm-v1_4-go/scenarios/model"
+	oj "github.com/multiversx/mx-chain-vm-v1_4-go/scenarios/orderedjson"
+)
+
+func pluginMocksToOJ(plugins []*mj.PluginMock) oj.OJsonObject {
+	pluginsOJ := oj.NewMap()
+	for _, plugin := range plugins {
+		methodsOJ := oj.NewMap()
+		for _, method := range plugin.Methods {
+			var responseList []oj.OJsonObject
+			for _, response := range method.Responses {
+				responseList = append(responseList, pluginResponseMockToOJ(response))
+			}
+			responsesOJ := oj.OJsonList(responseList)
+			methodsOJ.Put(method.Name, &responsesOJ)
+		}
+		pluginsOJ.Put(plugin.Name, methodsOJ)
+	}
+
+	return pluginsOJ
+}
+
+func pluginResponseMockToOJ(response *mj.PluginResponseMock) oj.OJsonObject {
+	responseOJ := oj.NewMap()
+	if !response.Args.Unspecified {
+		responseOJ.Put("args", pluginBytesToOJ(response.Args))
+	}
+	if !response.Result.Unspecified {
+		responseOJ.Put("result", pluginBytesToOJ(response.Result))
+	}
+	if !response.UserError.Unspecified {
+		responseOJ.Put("userError", bytesFromStringToOJ(response.UserError))
+	}
+
+	return responseOJ
+}
+
+func pluginBytesToOJ(bytes mj.JSONBytesFromTree) oj.OJsonObject {
+	if bytes.Original == nil {
+		bytes.Original = &oj.OJsonString{}
+	}
+	return bytesFromTreeToOJ(bytes)
+}
+
+func pluginCallsToOJ(calls *mj.PluginCallList) oj.OJsonObject {
+	if calls.IsStar {
+		return stringToOJ("*")
+	}
+
+	var callList []oj.OJsonObject
+	for _, call := range calls.List {
+		callOJ := oj.NewMap()
+		callOJ.Put("plugin", stringToOJ(call.Plugin))
+		callOJ.Put("method", stringToOJ(call.Method))
+		if !call.Args.IsStar {
+			callOJ.Put("args", checkBytesToOJ(call.Args))
+		}
+		callList = append(callList, callOJ)
+	}
+	if calls.MoreAllowedAtEnd {
+		callList = append(callList, stringToOJ("+"))
+	}
+	callsOJ := oj.OJsonList(callList)
+	return &callsOJ
+}
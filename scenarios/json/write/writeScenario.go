@@ -23,6 +23,23 @@ func ScenarioToOrderedJSON(scenario *mj.Scenario) oj.OJsonObject {
 		scenarioOJ.Put("comment", stringToOJ(scenario.Comment))
 	}
 
+	if len(scenario.Tags) > 0 {
+		var tagOJList []oj.OJsonObject
+		for _, tag := range scenario.Tags {
+			tagOJList = append(tagOJList, stringToOJ(tag))
+		}
+		tagsOJ := oj.OJsonList(tagOJList)
+		scenarioOJ.Put("tags", &tagsOJ)
+	}
+
+	if scenario.Skip {
+		scenarioOJ.Put("skip", boolToOJ(true))
+	}
+
+	if scenario.Only {
+		scenarioOJ.Put("only", boolToOJ(true))
+	}
+
 	if !scenario.CheckGas {
 		ojFalse := oj.OJsonBool(false)
 		scenarioOJ.Put("checkGas", &ojFalse)
@@ -48,6 +65,12 @@ func ScenarioToOrderedJSON(scenario *mj.Scenario) oj.OJsonObject {
 				stepOJ.Put("comment", stringToOJ(step.Comment))
 			}
 			stepOJ.Put("path", stringToOJ(step.Path))
+			if step.Skip {
+				stepOJ.Put("skip", boolToOJ(true))
+			}
+			if step.Only {
+				stepOJ.Put("only", boolToOJ(true))
+			}
 		case *mj.SetStateStep:
 			if len(step.SetStateIdent) > 0 {
 				stepOJ.Put("id", stringToOJ(step.SetStateIdent))
@@ -70,6 +93,9 @@ func ScenarioToOrderedJSON(scenario *mj.Scenario) oj.OJsonObject {
 			if !step.BlockHashes.IsUnspecified() {
 				stepOJ.Put("blockHashes", valueListToOJ(step.BlockHashes))
 			}
+			if len(step.Plugins) > 0 {
+				stepOJ.Put("plugins", pluginMocksToOJ(step.Plugins))
+			}
 		case *mj.CheckStateStep:
 			if len(step.CheckStateIdent) > 0 {
 				stepOJ.Put("id", stringToOJ(step.CheckStateIdent))
@@ -77,7 +103,12 @@ func ScenarioToOrderedJSON(scenario *mj.Scenario) oj.OJsonObject {
 			if len(step.Comment) > 0 {
 				stepOJ.Put("comment", stringToOJ(step.Comment))
 			}
-			stepOJ.Put("accounts", checkAccountsToOJ(step.CheckAccounts))
+			if step.CheckAccounts != nil {
+				stepOJ.Put("accounts", checkAccountsToOJ(step.CheckAccounts))
+			}
+			if step.PluginCalls != nil {
+				stepOJ.Put("pluginCalls", pluginCallsToOJ(step.PluginCalls))
+			}
 		case *mj.DumpStateStep:
 			if len(step.Comment) > 0 {
 				stepOJ.Put("comment", stringToOJ(step.Comment))
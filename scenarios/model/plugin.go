@@ -0,0 +1,36 @@
+package scenjsonmodel
+
+// PluginMock scripts the responses of a VM plugin, as declared in a setState step.
+type PluginMock struct {
+	Name    string
+	Methods []*PluginMethodMock
+}
+
+// PluginMethodMock lists the scripted responses of a plugin method, tried in order.
+type PluginMethodMock struct {
+	Name      string
+	Responses []*PluginResponseMock
+}
+
+// PluginResponseMock is returned by a plugin method when the call arguments match Args.
+// Unspecified Args match any call. A specified UserError makes the call fail with that message.
+type PluginResponseMock struct {
+	Args      JSONBytesFromTree
+	Result    JSONBytesFromTree
+	UserError JSONBytesFromString
+}
+
+// PluginCallList holds the plugin calls expected by a transaction result or a checkState step.
+// When the list is nil, plugin calls are not checked.
+type PluginCallList struct {
+	IsStar           bool
+	MoreAllowedAtEnd bool
+	List             []*PluginCallCheck
+}
+
+// PluginCallCheck is a json object representing an expected plugin call.
+type PluginCallCheck struct {
+	Plugin string
+	Method string
+	Args   JSONCheckBytes
+}
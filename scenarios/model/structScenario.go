@@ -4,6 +4,9 @@ package scenjsonmodel
 type Scenario struct {
 	Name        string
 	Comment     string
+	Tags        []string
+	Skip        bool
+	Only        bool
 	CheckGas    bool
 	TraceGas    bool
 	IsNewTest   bool
@@ -11,6 +14,25 @@ type Scenario struct {
 	Steps       []Step
 }
 
+// SkipsExternalStep returns true if the external step is marked "skip",
+// or if other external steps of the scenario are marked "only" and this one is not.
+func (scenario *Scenario) SkipsExternalStep(step *ExternalStepsStep) bool {
+	if step.Skip {
+		return true
+	}
+	if step.Only {
+		return false
+	}
+
+	for _, generalStep := range scenario.Steps {
+		externalStep, isExternal := generalStep.(*ExternalStepsStep)
+		if isExternal && externalStep.Only && !externalStep.Skip {
+			return true
+		}
+	}
+	return false
+}
+
 // Step is the basic block of a scenario.
 type Step interface {
 	StepTypeName() string
@@ -45,6 +67,8 @@ type ExternalStepsStep struct {
 	Comment  string
 	TraceGas TraceGasStatus
 	Path     string
+	Skip     bool
+	Only     bool
 }
 
 // SetStateStep is a step where data is saved to the blockchain mock.
@@ -56,6 +80,7 @@ type SetStateStep struct {
 	CurrentBlockInfo  *BlockInfo
 	BlockHashes       JSONValueList
 	NewAddressMocks   []*NewAddressMock
+	Plugins           []*PluginMock
 }
 
 // CheckStateStep is a step where the state of the blockchain mock is verified.
@@ -63,6 +88,7 @@ type CheckStateStep struct {
 	CheckStateIdent string
 	Comment         string
 	CheckAccounts   *CheckAccounts
+	PluginCalls     *PluginCallList
 }
 
 // DumpStateStep is a step that simply prints the entire state to console. Useful for debugging.
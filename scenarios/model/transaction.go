@@ -79,12 +79,13 @@ type Transaction struct {
 
 // TransactionResult is a json object representing an expected transaction result.
 type TransactionResult struct {
-	Out     JSONCheckValueList
-	Status  JSONCheckBigInt
-	Message JSONCheckBytes
-	Gas     JSONCheckUint64
-	Refund  JSONCheckBigInt
-	Logs    LogList
+	Out         JSONCheckValueList
+	Status      JSONCheckBigInt
+	Message     JSONCheckBytes
+	Gas         JSONCheckUint64
+	Refund      JSONCheckBigInt
+	Logs        LogList
+	PluginCalls *PluginCallList
 }
 
 type LogList struct {
@@ -7,6 +7,7 @@ import (
 	mock "github.com/multiversx/mx-chain-vm-v1_4-go/mock/context"
 	worldmock "github.com/multiversx/mx-chain-vm-v1_4-go/mock/world"
 	"github.com/multiversx/mx-chain-vm-v1_4-go/vmhost"
+	"github.com/stretchr/testify/require"
 )
 
 type testTemplateConfig struct {
@@ -20,6 +21,8 @@ type testTemplateConfig struct {
 type MockInstancesTestTemplate struct {
 	testTemplateConfig
 	contracts     *[]MockTestSmartContract
+	plugins       []vmhost.Plugin
+	pluginsConfig vmhost.PluginsConfig
 	setup         func(vmhost.VMHost, *worldmock.MockWorld)
 	assertResults func(*worldmock.MockWorld, *VMOutputVerifier)
 }
@@ -48,6 +51,18 @@ func (callerTest *MockInstancesTestTemplate) WithInput(input *vmcommon.ContractC
 	return callerTest
 }
 
+// WithPlugins provides the in-process VM plugins to be registered on the host of the mock contract call test
+func (callerTest *MockInstancesTestTemplate) WithPlugins(plugins ...vmhost.Plugin) *MockInstancesTestTemplate {
+	callerTest.plugins = plugins
+	return callerTest
+}
+
+// WithPluginsConfig provides the plugin settings of the host of the mock contract call test
+func (callerTest *MockInstancesTestTemplate) WithPluginsConfig(pluginsConfig vmhost.PluginsConfig) *MockInstancesTestTemplate {
+	callerTest.pluginsConfig = pluginsConfig
+	return callerTest
+}
+
 // WithSetup provides the setup function to be used by the mock contract call test
 func (callerTest *MockInstancesTestTemplate) WithSetup(setup func(vmhost.VMHost, *worldmock.MockWorld)) *MockInstancesTestTemplate {
 	callerTest.setup = setup
@@ -67,7 +82,7 @@ func (callerTest *MockInstancesTestTemplate) AndAssertResults(assertResults func
 }
 
 func (callerTest *MockInstancesTestTemplate) runTest() {
-	host, world, imb := DefaultTestVMForCallWithInstanceMocks(callerTest.tb)
+	host, world, imb := DefaultTestVMForCallWithInstanceMocksAndPlugins(callerTest.tb, callerTest.pluginsConfig)
 	defer func() {
 		host.Reset()
 	}()
@@ -76,6 +91,11 @@ func (callerTest *MockInstancesTestTemplate) runTest() {
 		mockSC.initialize(callerTest.tb, host, imb)
 	}
 
+	for _, plugin := range callerTest.plugins {
+		err := host.Plugins().RegisterPlugin(plugin)
+		require.Nil(callerTest.tb, err)
+	}
+
 	callerTest.setup(host, world)
 	// create snapshot (normaly done by node)
 	world.CreateStateBackup()
@@ -168,8 +168,13 @@ func DefaultTestVMForCallWithInstanceRecorderMock(tb testing.TB, code []byte, ba
 
 // DefaultTestVMForCallWithInstanceMocks creates an InstanceBuilderMock
 func DefaultTestVMForCallWithInstanceMocks(tb testing.TB) (vmhost.VMHost, *worldmock.MockWorld, *contextmock.InstanceBuilderMock) {
+	return DefaultTestVMForCallWithInstanceMocksAndPlugins(tb, vmhost.PluginsConfig{})
+}
+
+// DefaultTestVMForCallWithInstanceMocksAndPlugins creates an InstanceBuilderMock and a host with the provided plugin settings
+func DefaultTestVMForCallWithInstanceMocksAndPlugins(tb testing.TB, pluginsConfig vmhost.PluginsConfig) (vmhost.VMHost, *worldmock.MockWorld, *contextmock.InstanceBuilderMock) {
 	world := worldmock.NewMockWorld()
-	host := DefaultTestVM(tb, world)
+	host := defaultTestVM(tb, world, config.MakeGasMapForTests(), false, pluginsConfig)
 
 	instanceBuilderMock := contextmock.NewInstanceBuilderMock(world)
 	host.Runtime().ReplaceInstanceBuilder(instanceBuilderMock)
@@ -334,6 +339,7 @@ func DefaultTestVMWithWorldMockWithGasSchedule(tb testing.TB, customGasSchedule
 			IsGlobalMintBurnFlagEnabledField:                 true,
 			IsCheckFunctionArgumentFlagEnabledField:          true,
 			IsCheckExecuteOnReadOnlyFlagEnabledField:         true,
+			IsVMPluginsFlagEnabledField:                      true,
 		},
 		WasmerSIGSEGVPassthrough: false,
 		Hasher:                   worldmock.DefaultHasher,
@@ -362,6 +368,17 @@ func DefaultTestVMWithGasSchedule(
 		gasSchedule = config.MakeGasMapForTests()
 	}
 
+	return defaultTestVM(tb, blockchain, gasSchedule, wasmerSIGSEGVPassthrough, vmhost.PluginsConfig{})
+}
+
+func defaultTestVM(
+	tb testing.TB,
+	blockchain vmcommon.BlockchainHook,
+	gasSchedule config.GasScheduleMap,
+	wasmerSIGSEGVPassthrough bool,
+	pluginsConfig vmhost.PluginsConfig,
+) vmhost.VMHost {
+
 	esdtTransferParser, _ := parsers.NewESDTTransferParser(worldmock.WorldMarshalizer)
 	host, err := hostCore.NewVMHost(blockchain, &vmhost.VMHostParameters{
 		VMType:               DefaultVMType,
@@ -387,9 +404,11 @@ func DefaultTestVMWithGasSchedule(
 			IsGlobalMintBurnFlagEnabledField:                     true,
 			IsCheckFunctionArgumentFlagEnabledField:              true,
 			IsCheckExecuteOnReadOnlyFlagEnabledField:             true,
+			IsVMPluginsFlagEnabledField:                          true,
 		},
 		WasmerSIGSEGVPassthrough: wasmerSIGSEGVPassthrough,
 		Hasher:                   worldmock.DefaultHasher,
+		PluginsConfig:            pluginsConfig,
 	})
 	require.Nil(tb, err)
 	require.NotNil(tb, host)
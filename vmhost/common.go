@@ -63,6 +63,18 @@ const (
 	AsyncUnknown
 )
 
+// PluginLengthPrefixSize is the size of the little endian uint64 length that
+// prefixes both the arguments and the result of a plugin call
+const PluginLengthPrefixSize = 8
+
+// MaxPluginResultLength is the maximum length of a plugin call result, without
+// its length prefix; larger results are rejected before reaching contract memory
+const MaxPluginResultLength = 1024 * 1024
+
+// PluginCallContextVersion is the version of the JSON call context passed to plugins; it is
+// increased whenever fields are added, so that plugins can tell which fields are present
+const PluginCallContextVersion = 2
+
 // CallbackFunctionName is the name of the default asynchronous callback
 // function of a smart contract
 const CallbackFunctionName = "callBack"
@@ -134,6 +146,61 @@ type VMHostParameters struct {
 	EnableEpochsHandler                 vmcommon.EnableEpochsHandler
 	Hasher                              HashComputer
 	TimeOutForSCExecutionInMilliseconds uint32
+	PluginsConfig                       PluginsConfig
+}
+
+// PluginsConfig holds the VM plugin settings of the host
+type PluginsConfig struct {
+	// Disabled turns off VM plugins, including the in-process ones
+	Disabled bool
+	// Paths lists plugin libraries, or directories holding them; when empty, the directory
+	// in the MX_VM_PLUGINS_PATH environment variable is used, falling back to ~/.mx-vm/plugins
+	Paths []string
+	// Required lists the names of the plugins without which the host must not start
+	Required []string
+	// LibrarySHA256 maps library file names to their hex encoded SHA-256; when it is not
+	// nil, libraries missing from it or not matching their hash are not loaded
+	LibrarySHA256 map[string]string
+	// Plugins are in-process plugins, registered without loading any library
+	Plugins []Plugin
+	// Permissions maps plugin names to the contracts allowed to call them; when it is
+	// not nil, plugins missing from it cannot be called by any contract
+	Permissions map[string]PluginPermission
+	// DeterministicOnly refuses calls to plugins that do not declare themselves
+	// deterministic, as required whenever the execution result goes through consensus
+	DeterministicOnly bool
+}
+
+// PluginPermission lists the contracts allowed to call a VM plugin, by address or by the hash of the running code,
+// which under ExecuteOnSameContext is the code of the called library rather than that of the context address
+type PluginPermission struct {
+	Addresses  [][]byte
+	CodeHashes [][]byte
+}
+
+// PluginCallContext describes the contract call from which a VM plugin is called;
+// library plugins receive it as JSON, versioned by PluginCallContextVersion
+type PluginCallContext struct {
+	Version        uint32
+	Address        []byte
+	Caller         []byte
+	OriginalTxHash []byte
+	CurrentTxHash  []byte
+	CallValue      string
+	ESDTTransfers  []PluginESDTTransfer
+	BlockNonce     uint64
+	BlockRound     uint64
+	BlockEpoch     uint32
+	BlockTimestamp uint64
+	ReadOnly       bool
+}
+
+// PluginESDTTransfer describes an ESDT transfer received by the contract calling a VM plugin
+type PluginESDTTransfer struct {
+	TokenIdentifier string
+	Nonce           uint64
+	Value           string
+	TokenType       uint32
 }
 
 // AsyncCallInfo contains the information required to handle the asynchronous call of another SmartContract
@@ -0,0 +1,210 @@
+package contexts
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"unsafe"
+
+	"github.com/ebitengine/purego"
+	"github.com/multiversx/mx-chain-vm-v1_4-go/vmhost"
+)
+
+type vmPluginInitResult struct {
+	Name          string
+	Deterministic bool
+	Methods       []string
+	MethodCosts   map[string]uint64
+}
+
+// vmPluginError is the JSON returned by the optional mx_plug_last_error symbol
+// after mx_plug_call returned a null result
+type vmPluginError struct {
+	Message   string
+	UserError bool
+}
+
+var _ vmhost.Plugin = (*libraryPlugin)(nil)
+var _ vmhost.DeterministicPlugin = (*libraryPlugin)(nil)
+var _ vmhost.StatefulPlugin = (*libraryPlugin)(nil)
+
+// libraryPlugin adapts a plugin library loaded with dlopen to the vmhost.Plugin interface
+type libraryPlugin struct {
+	name          string
+	deterministic bool
+	methods       []vmhost.PluginMethod
+	callFn        func(callCtx string, methodName string, args []byte) unsafe.Pointer
+	freeFn        func(result unsafe.Pointer)
+	lastErrorFn   func() string
+	snapshotFn    func()
+	revertFn      func()
+	commitFn      func()
+}
+
+// loadPluginLibrary dlopens a plugin library, which exports:
+//   - mx_plug_init() string: JSON with the plugin Name, its Methods, optional MethodCosts
+//     and whether the plugin is Deterministic
+//   - mx_plug_call(callCtx string, methodName string, args []byte) pointer: callCtx is a JSON
+//     object versioned by vmhost.PluginCallContextVersion and args are prefixed by their
+//     little endian uint64 length; returns the result, prefixed the same way and holding
+//     at most vmhost.MaxPluginResultLength bytes, or NULL on failure
+//   - mx_plug_free(result pointer), optional: releases a result after the VM copied it
+//   - mx_plug_last_error() string, optional: JSON with the Message and UserError flag
+//     explaining why the last call returned NULL
+//   - mx_plug_snapshot(), mx_plug_revert(), mx_plug_commit(), optional: push a snapshot
+//     of the plugin state, then either roll back to it or drop it, following the VM state stack
+func loadPluginLibrary(libraryPath string) (*libraryPlugin, error) {
+	pluginso, err := purego.Dlopen(libraryPath, purego.RTLD_NOW)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hasPluginSymbol(pluginso, "mx_plug_init") || !hasPluginSymbol(pluginso, "mx_plug_call") {
+		return nil, vmhost.ErrInvalidPluginLibrary
+	}
+
+	var init func() string
+	purego.RegisterLibFunc(&init, pluginso, "mx_plug_init")
+
+	var initResult vmPluginInitResult
+	err = json.Unmarshal([]byte(init()), &initResult)
+	if err != nil {
+		return nil, err
+	}
+	vmPluginLog.Info("initialized VM plugin: ", "name", initResult.Name)
+
+	plugin := &libraryPlugin{
+		name:          initResult.Name,
+		deterministic: initResult.Deterministic,
+		methods:       make([]vmhost.PluginMethod, len(initResult.Methods)),
+		snapshotFn:    registerOptionalPluginHook(pluginso, "mx_plug_snapshot"),
+		revertFn:      registerOptionalPluginHook(pluginso, "mx_plug_revert"),
+		commitFn:      registerOptionalPluginHook(pluginso, "mx_plug_commit"),
+	}
+	for i, methodName := range initResult.Methods {
+		plugin.methods[i] = vmhost.PluginMethod{
+			Name:    methodName,
+			GasCost: initResult.MethodCosts[methodName],
+		}
+	}
+
+	purego.RegisterLibFunc(&plugin.callFn, pluginso, "mx_plug_call")
+	if hasPluginSymbol(pluginso, "mx_plug_free") {
+		purego.RegisterLibFunc(&plugin.freeFn, pluginso, "mx_plug_free")
+	}
+	if hasPluginSymbol(pluginso, "mx_plug_last_error") {
+		purego.RegisterLibFunc(&plugin.lastErrorFn, pluginso, "mx_plug_last_error")
+	}
+
+	return plugin, nil
+}
+
+// Name returns the name declared by the plugin library
+func (plugin *libraryPlugin) Name() string {
+	return plugin.name
+}
+
+// Methods returns the methods declared by the plugin library
+func (plugin *libraryPlugin) Methods() []vmhost.PluginMethod {
+	return plugin.methods
+}
+
+// IsDeterministic returns whether the plugin library declared itself deterministic
+func (plugin *libraryPlugin) IsDeterministic() bool {
+	return plugin.deterministic
+}
+
+// Call encodes the call for mx_plug_call and copies the result out of the library memory
+func (plugin *libraryPlugin) Call(callCtx *vmhost.PluginCallContext, methodName string, args []byte) ([]byte, error) {
+	encodedCallCtx, err := json.Marshal(callCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	prefixedArgs := make([]byte, vmhost.PluginLengthPrefixSize+len(args))
+	binary.LittleEndian.PutUint64(prefixedArgs, uint64(len(args)))
+	copy(prefixedArgs[vmhost.PluginLengthPrefixSize:], args)
+
+	result := plugin.callFn(string(encodedCallCtx), methodName, prefixedArgs)
+	if result == nil {
+		if plugin.lastErrorFn == nil {
+			return nil, vmhost.ErrPluginCallFailed
+		}
+		return nil, decodePluginError(plugin.lastErrorFn())
+	}
+	if plugin.freeFn != nil {
+		defer plugin.freeFn(result)
+	}
+
+	return readPluginResult(result)
+}
+
+// Snapshot calls mx_plug_snapshot, if exported
+func (plugin *libraryPlugin) Snapshot() {
+	if plugin.snapshotFn != nil {
+		plugin.snapshotFn()
+	}
+}
+
+// Revert calls mx_plug_revert, if exported
+func (plugin *libraryPlugin) Revert() {
+	if plugin.revertFn != nil {
+		plugin.revertFn()
+	}
+}
+
+// Commit calls mx_plug_commit, if exported
+func (plugin *libraryPlugin) Commit() {
+	if plugin.commitFn != nil {
+		plugin.commitFn()
+	}
+}
+
+// readPluginResult copies the length-prefixed result out of the library memory, without the prefix,
+// refusing results longer than vmhost.MaxPluginResultLength
+func readPluginResult(result unsafe.Pointer) ([]byte, error) {
+	resultLenBytes := unsafe.Slice((*byte)(result), vmhost.PluginLengthPrefixSize)
+	resultLen := binary.LittleEndian.Uint64(resultLenBytes)
+	if resultLen > vmhost.MaxPluginResultLength {
+		return nil, fmt.Errorf("%w: %d bytes", vmhost.ErrPluginResultTooLarge, resultLen)
+	}
+
+	prefixedResult := unsafe.Slice((*byte)(result), vmhost.PluginLengthPrefixSize+int(resultLen))
+	resultData := make([]byte, resultLen)
+	copy(resultData, prefixedResult[vmhost.PluginLengthPrefixSize:])
+
+	return resultData, nil
+}
+
+func hasPluginSymbol(pluginso uintptr, name string) bool {
+	_, err := purego.Dlsym(pluginso, name)
+	return err == nil
+}
+
+func registerOptionalPluginHook(pluginso uintptr, name string) func() {
+	if !hasPluginSymbol(pluginso, name) {
+		return nil
+	}
+
+	var hook func()
+	purego.RegisterLibFunc(&hook, pluginso, name)
+	return hook
+}
+
+func decodePluginError(encodedError string) error {
+	if len(encodedError) == 0 {
+		return vmhost.ErrPluginCallFailed
+	}
+
+	var pluginError vmPluginError
+	err := json.Unmarshal([]byte(encodedError), &pluginError)
+	if err != nil {
+		return fmt.Errorf("%w: %s", vmhost.ErrPluginCallFailed, encodedError)
+	}
+
+	if pluginError.UserError {
+		return &vmhost.PluginUserError{Message: pluginError.Message}
+	}
+
+	return fmt.Errorf("%w: %s", vmhost.ErrPluginCallFailed, pluginError.Message)
+}
@@ -0,0 +1,77 @@
+package contexts
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+	"unsafe"
+
+	"github.com/multiversx/mx-chain-vm-v1_4-go/vmhost"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlugins_DecodePluginError(t *testing.T) {
+	err := decodePluginError("")
+	require.Equal(t, vmhost.ErrPluginCallFailed, err)
+
+	err = decodePluginError("not json")
+	require.True(t, errors.Is(err, vmhost.ErrPluginCallFailed))
+	require.Contains(t, err.Error(), "not json")
+
+	err = decodePluginError(`{"Message":"oracle offline"}`)
+	require.True(t, errors.Is(err, vmhost.ErrPluginCallFailed))
+	require.Contains(t, err.Error(), "oracle offline")
+
+	err = decodePluginError(`{"Message":"price too old","UserError":true}`)
+	var userErr *vmhost.PluginUserError
+	require.True(t, errors.As(err, &userErr))
+	require.Equal(t, "price too old", userErr.Message)
+}
+
+func TestPlugins_ReadPluginResult(t *testing.T) {
+	prefixedResult := make([]byte, vmhost.PluginLengthPrefixSize+3)
+	binary.LittleEndian.PutUint64(prefixedResult, 3)
+	copy(prefixedResult[vmhost.PluginLengthPrefixSize:], "abc")
+
+	result, err := readPluginResult(unsafe.Pointer(&prefixedResult[0]))
+	require.Nil(t, err)
+	require.Equal(t, []byte("abc"), result)
+
+	binary.LittleEndian.PutUint64(prefixedResult, vmhost.MaxPluginResultLength+1)
+	_, err = readPluginResult(unsafe.Pointer(&prefixedResult[0]))
+	require.True(t, errors.Is(err, vmhost.ErrPluginResultTooLarge))
+}
+
+func TestPlugins_LibraryPluginCall_FreesResultAfterCopy(t *testing.T) {
+	var buffer []byte
+	freed := 0
+	plugin := &libraryPlugin{
+		name: "oracle",
+		callFn: func(_ string, _ string, _ []byte) unsafe.Pointer {
+			return unsafe.Pointer(&buffer[0])
+		},
+		freeFn: func(result unsafe.Pointer) {
+			require.Equal(t, unsafe.Pointer(&buffer[0]), result)
+			for i := range buffer {
+				buffer[i] = 0
+			}
+			freed++
+		},
+	}
+
+	buffer = make([]byte, vmhost.PluginLengthPrefixSize+3)
+	binary.LittleEndian.PutUint64(buffer, 3)
+	copy(buffer[vmhost.PluginLengthPrefixSize:], "abc")
+
+	result, err := plugin.Call(&vmhost.PluginCallContext{}, "getPrice", []byte("EGLD"))
+	require.Nil(t, err)
+	require.Equal(t, []byte("abc"), result)
+	require.Equal(t, 1, freed)
+
+	buffer = make([]byte, vmhost.PluginLengthPrefixSize)
+	binary.LittleEndian.PutUint64(buffer, vmhost.MaxPluginResultLength+1)
+
+	_, err = plugin.Call(&vmhost.PluginCallContext{}, "getPrice", []byte("EGLD"))
+	require.True(t, errors.Is(err, vmhost.ErrPluginResultTooLarge))
+	require.Equal(t, 2, freed)
+}
@@ -1,100 +1,324 @@
 package contexts
 
 import (
-	"encoding/json"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"os"
 	"path"
-	"unsafe"
+	"strings"
 
-	"github.com/ebitengine/purego"
+	"github.com/multiversx/mx-chain-core-go/core/check"
 	logger "github.com/multiversx/mx-chain-logger-go"
 	"github.com/multiversx/mx-chain-vm-v1_4-go/vmhost"
 )
 
 var vmPluginLog = logger.GetOrCreate("vm/plugins")
 
-type vmPluginInitResult struct {
-	Name    string
-	Methods []string
+var _ vmhost.PluginsContext = (*pluginsContext)(nil)
+
+type pluginsContext struct {
+	host       vmhost.VMHost
+	config     vmhost.PluginsConfig
+	plugins    []vmhost.Plugin
+	stateDepth int
 }
 
+// NewPluginsContext creates a new pluginsContext, loading the VM plugins described by the config
 func NewPluginsContext(
 	host vmhost.VMHost,
-) *vmhost.PluginsContext {
+	config vmhost.PluginsConfig,
+) (*pluginsContext, error) {
+	if check.IfNil(host) {
+		return nil, vmhost.ErrNilVMHost
+	}
 
-	return &vmhost.PluginsContext{
-		Plugins: loadPlugins(host),
-		Host:    host,
+	plugins, err := loadPlugins(config)
+	if err != nil {
+		return nil, err
 	}
+
+	context := &pluginsContext{
+		host:    host,
+		config:  config,
+		plugins: plugins,
+	}
+
+	return context, nil
 }
 
-func loadPlugins(host vmhost.VMHost) []vmhost.VmPlugin {
-	list := make([]vmhost.VmPlugin, 0)
-	vmPluginsPath := os.Getenv("MX_VM_PLUGINS_PATH")
-	if vmPluginsPath == "" {
-		homedir, err := os.UserHomeDir()
-		if err != nil {
-			return list
+// InitState does nothing
+func (context *pluginsContext) InitState() {
+}
+
+// PushState asks every stateful plugin to take a snapshot of its state
+func (context *pluginsContext) PushState() {
+	for _, plugin := range context.plugins {
+		statefulPlugin, ok := plugin.(vmhost.StatefulPlugin)
+		if ok {
+			statefulPlugin.Snapshot()
 		}
+	}
+	context.stateDepth++
+}
 
-		vmPluginsPath = path.Join(homedir, ".mx-vm", "plugins")
+// PopSetActiveState asks every stateful plugin to revert to its latest snapshot
+func (context *pluginsContext) PopSetActiveState() {
+	if context.stateDepth == 0 {
+		return
 	}
 
-	vmPluginLog.Info("loading VM plugins from: ", "pluginsPath", vmPluginsPath)
+	for _, plugin := range context.plugins {
+		statefulPlugin, ok := plugin.(vmhost.StatefulPlugin)
+		if ok {
+			statefulPlugin.Revert()
+		}
+	}
+	context.stateDepth--
+}
 
-	dirEntries, err := os.ReadDir(vmPluginsPath)
-	if err != nil {
-		return list
+// PopDiscard asks every stateful plugin to drop its latest snapshot, keeping the current state
+func (context *pluginsContext) PopDiscard() {
+	if context.stateDepth == 0 {
+		return
+	}
+
+	for _, plugin := range context.plugins {
+		statefulPlugin, ok := plugin.(vmhost.StatefulPlugin)
+		if ok {
+			statefulPlugin.Commit()
+		}
 	}
+	context.stateDepth--
+}
+
+// ClearStateStack reverts the snapshots left behind by an interrupted execution
+func (context *pluginsContext) ClearStateStack() {
+	for context.stateDepth > 0 {
+		context.PopSetActiveState()
+	}
+}
 
-	for _, dirEntry := range dirEntries {
-		if dirEntry.IsDir() {
+// RegisterPlugin adds an in-process plugin to the ones loaded from the config;
+// plugins must be registered between executions, never while a contract is running
+func (context *pluginsContext) RegisterPlugin(plugin vmhost.Plugin) error {
+	if plugin == nil {
+		return vmhost.ErrNilPlugin
+	}
+	if context.config.Disabled {
+		return vmhost.ErrPluginsDisabled
+	}
+
+	for _, registered := range context.plugins {
+		if registered.Name() == plugin.Name() {
+			return fmt.Errorf("%w: %s", vmhost.ErrPluginAlreadyRegistered, plugin.Name())
+		}
+	}
+
+	context.plugins = append(context.plugins, plugin)
+	vmPluginLog.Debug("registered VM plugin: ", "name", plugin.Name())
+	return nil
+}
+
+// UnregisterPlugin removes a plugin, registered or loaded from the config;
+// like registering, it must happen between executions
+func (context *pluginsContext) UnregisterPlugin(pluginName string) error {
+	for i, registered := range context.plugins {
+		if registered.Name() == pluginName {
+			context.plugins = append(context.plugins[:i], context.plugins[i+1:]...)
+			vmPluginLog.Debug("unregistered VM plugin: ", "name", pluginName)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", vmhost.ErrPluginNotFound, pluginName)
+}
+
+// GetPluginMethod returns the plugin with the given name, together with the requested method
+func (context *pluginsContext) GetPluginMethod(pluginName string, methodName string) (vmhost.Plugin, *vmhost.PluginMethod, error) {
+	for _, plugin := range context.plugins {
+		if plugin.Name() != pluginName {
 			continue
 		}
 
-		pluginPath := path.Join(vmPluginsPath, dirEntry.Name())
-		_, err := os.Stat(pluginPath)
+		methods := plugin.Methods()
+		for i := range methods {
+			if methods[i].Name == methodName {
+				return plugin, &methods[i], nil
+			}
+		}
+		return nil, nil, fmt.Errorf("%w: %s/%s", vmhost.ErrPluginMethodNotFound, pluginName, methodName)
+	}
+	return nil, nil, fmt.Errorf("%w: %s", vmhost.ErrPluginNotFound, pluginName)
+}
+
+func loadPlugins(config vmhost.PluginsConfig) ([]vmhost.Plugin, error) {
+	list := make([]vmhost.Plugin, 0)
+	if config.Disabled {
+		return list, nil
+	}
+
+	loaded := make(map[string]struct{})
+	addPlugin := func(plugin vmhost.Plugin) {
+		_, exists := loaded[plugin.Name()]
+		if exists {
+			vmPluginLog.Warn("VM plugin already loaded, ignoring duplicate: ", "name", plugin.Name())
+			return
+		}
+
+		loaded[plugin.Name()] = struct{}{}
+		list = append(list, plugin)
+	}
+
+	for _, plugin := range config.Plugins {
+		if plugin == nil {
+			return nil, vmhost.ErrNilPlugin
+		}
+		addPlugin(plugin)
+	}
 
+	for _, libraryPath := range findPluginLibraries(config.Paths) {
+		err := verifyPluginLibrary(libraryPath, config.LibrarySHA256)
 		if err != nil {
+			vmPluginLog.Warn("VM plugin library rejected: ", "pluginPath", libraryPath, "error", err)
 			continue
 		}
 
-		pluginso, err := purego.Dlopen(pluginPath, purego.RTLD_NOW)
+		plugin, err := loadPluginLibrary(libraryPath)
 		if err != nil {
-			vmPluginLog.Warn("error loading VM plugin: ", "pluginPath", pluginPath)
+			vmPluginLog.Warn("error loading VM plugin: ", "pluginPath", libraryPath, "error", err)
 			continue
 		}
 
-		var init func() string
-		purego.RegisterLibFunc(&init, pluginso, "mx_plug_init")
+		addPlugin(plugin)
+		vmPluginLog.Info("completed loading VM plugin: ", "name", plugin.Name(), "pluginPath", libraryPath)
+	}
+
+	for _, requiredName := range config.Required {
+		_, ok := loaded[requiredName]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", vmhost.ErrRequiredPluginMissing, requiredName)
+		}
+	}
+
+	return list, nil
+}
 
-		initResultStr := init()
-		var initResult vmPluginInitResult
+// findPluginLibraries expands the configured paths, which are either libraries or
+// directories holding them, into the list of plugin libraries to load
+func findPluginLibraries(paths []string) []string {
+	if len(paths) == 0 {
+		paths = defaultPluginsPaths()
+	}
 
-		jsonErr := json.Unmarshal([]byte(initResultStr), &initResult)
-		if jsonErr != nil {
-			vmPluginLog.Warn("error loading VM plugin: ", "pluginPath", pluginPath)
+	libraries := make([]string, 0)
+	for _, pluginsPath := range paths {
+		fileInfo, err := os.Stat(pluginsPath)
+		if err != nil {
+			vmPluginLog.Debug("VM plugins path not found: ", "pluginsPath", pluginsPath)
+			continue
 		}
-		vmPluginLog.Info("initialized VM plugin: ", "name", initResult.Name)
 
-		var call func(callCtx string, methodName string, args []byte) unsafe.Pointer
-		purego.RegisterLibFunc(&call, pluginso, "mx_plug_call")
+		if !fileInfo.IsDir() {
+			libraries = append(libraries, pluginsPath)
+			continue
+		}
 
-		methods := make([]vmhost.VmPluginMethod, len(initResult.Methods))
-		for i, methodName := range initResult.Methods {
-			methods[i] = vmhost.VmPluginMethod{
-				Name: methodName,
+		vmPluginLog.Info("loading VM plugins from: ", "pluginsPath", pluginsPath)
+		dirEntries, err := os.ReadDir(pluginsPath)
+		if err != nil {
+			continue
+		}
+
+		for _, dirEntry := range dirEntries {
+			if dirEntry.IsDir() || !isPluginLibraryName(dirEntry.Name()) {
+				continue
 			}
+			libraries = append(libraries, path.Join(pluginsPath, dirEntry.Name()))
 		}
+	}
+
+	return libraries
+}
 
-		list = append(list, vmhost.VmPlugin{
-			Name:    initResult.Name,
-			Methods: methods,
-			CallFn:  call,
-		})
+func defaultPluginsPaths() []string {
+	vmPluginsPath := os.Getenv("MX_VM_PLUGINS_PATH")
+	if vmPluginsPath != "" {
+		return []string{vmPluginsPath}
+	}
 
-		vmPluginLog.Info("completed loading VM plugin: ", "name", initResult.Name, "methods", initResult.Methods)
+	homedir, err := os.UserHomeDir()
+	if err != nil {
+		return nil
 	}
 
-	return list
+	return []string{path.Join(homedir, ".mx-vm", "plugins")}
+}
+
+func isPluginLibraryName(fileName string) bool {
+	extension := path.Ext(fileName)
+	return extension == ".so" || extension == ".dylib"
+}
+
+// verifyPluginLibrary checks the library against its expected SHA-256, when the hashes are configured
+func verifyPluginLibrary(libraryPath string, librarySHA256 map[string]string) error {
+	if librarySHA256 == nil {
+		return nil
+	}
+
+	expectedHash, ok := librarySHA256[path.Base(libraryPath)]
+	if !ok {
+		return vmhost.ErrPluginLibraryNotInManifest
+	}
+
+	libraryBytes, err := os.ReadFile(libraryPath)
+	if err != nil {
+		return err
+	}
+
+	actualHash := sha256.Sum256(libraryBytes)
+	if !strings.EqualFold(hex.EncodeToString(actualHash[:]), expectedHash) {
+		return vmhost.ErrPluginLibraryHashMismatch
+	}
+
+	return nil
+}
+
+// CheckCallAllowed returns an error if the given contract, running the code with the given hash, may not call
+// the plugin, either because of the configured permissions or because the plugin is not deterministic
+func (context *pluginsContext) CheckCallAllowed(plugin vmhost.Plugin, contractAddress []byte, codeHash []byte) error {
+	if context.config.DeterministicOnly && !isDeterministicPlugin(plugin) {
+		return fmt.Errorf("%w: %s", vmhost.ErrPluginNotDeterministic, plugin.Name())
+	}
+
+	if context.config.Permissions == nil {
+		return nil
+	}
+
+	permission, ok := context.config.Permissions[plugin.Name()]
+	if !ok {
+		return fmt.Errorf("%w: %s", vmhost.ErrPluginCallNotAllowed, plugin.Name())
+	}
+	if containsBytes(permission.Addresses, contractAddress) {
+		return nil
+	}
+	if len(codeHash) > 0 && containsBytes(permission.CodeHashes, codeHash) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", vmhost.ErrPluginCallNotAllowed, plugin.Name())
+}
+
+func isDeterministicPlugin(plugin vmhost.Plugin) bool {
+	deterministicPlugin, ok := plugin.(vmhost.DeterministicPlugin)
+	return ok && deterministicPlugin.IsDeterministic()
+}
+
+func containsBytes(list [][]byte, value []byte) bool {
+	for _, item := range list {
+		if bytes.Equal(item, value) {
+			return true
+		}
+	}
+	return false
 }
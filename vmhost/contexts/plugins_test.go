@@ -0,0 +1,189 @@
+package contexts
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path"
+	"testing"
+
+	contextmock "github.com/multiversx/mx-chain-vm-v1_4-go/mock/context"
+	"github.com/multiversx/mx-chain-vm-v1_4-go/vmhost"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPluginsContext_StateStack(t *testing.T) {
+	calls := make([]string, 0)
+	oracle := contextmock.NewPluginStub("oracle")
+	oracle.SnapshotCalled = func() { calls = append(calls, "snapshot") }
+	oracle.RevertCalled = func() { calls = append(calls, "revert") }
+	oracle.CommitCalled = func() { calls = append(calls, "commit") }
+	plugins := &pluginsContext{
+		plugins: []vmhost.Plugin{oracle, &statelessPlugin{}},
+	}
+
+	plugins.PopSetActiveState()
+	plugins.PopDiscard()
+	require.Empty(t, calls)
+
+	plugins.PushState()
+	plugins.PushState()
+	plugins.PopDiscard()
+	plugins.PopSetActiveState()
+	require.Equal(t, []string{"snapshot", "snapshot", "commit", "revert"}, calls)
+
+	calls = calls[:0]
+	plugins.PushState()
+	plugins.PushState()
+	plugins.ClearStateStack()
+	require.Equal(t, []string{"snapshot", "snapshot", "revert", "revert"}, calls)
+	require.Zero(t, plugins.stateDepth)
+}
+
+func TestPluginsContext_GetPluginMethod(t *testing.T) {
+	oracle := contextmock.NewPluginStub("oracle")
+	oracle.PluginMethods = []vmhost.PluginMethod{{Name: "getPrice", GasCost: 10}}
+	plugins := &pluginsContext{
+		plugins: []vmhost.Plugin{oracle},
+	}
+
+	plugin, method, err := plugins.GetPluginMethod("oracle", "getPrice")
+	require.Nil(t, err)
+	require.Equal(t, "oracle", plugin.Name())
+	require.Equal(t, uint64(10), method.GasCost)
+
+	_, _, err = plugins.GetPluginMethod("oracle", "setPrice")
+	require.True(t, errors.Is(err, vmhost.ErrPluginMethodNotFound))
+
+	_, _, err = plugins.GetPluginMethod("bridge", "getPrice")
+	require.True(t, errors.Is(err, vmhost.ErrPluginNotFound))
+}
+
+func TestPluginsContext_CheckCallAllowed(t *testing.T) {
+	oracle := contextmock.NewPluginStub("oracle")
+	random := contextmock.NewPluginStub("random")
+	random.Deterministic = false
+
+	plugins := &pluginsContext{}
+	require.Nil(t, plugins.CheckCallAllowed(oracle, []byte("sc_other"), []byte("other_hash")))
+	require.Nil(t, plugins.CheckCallAllowed(random, []byte("sc_other"), []byte("other_hash")))
+
+	plugins.config = vmhost.PluginsConfig{
+		Permissions: map[string]vmhost.PluginPermission{
+			"oracle": {
+				Addresses:  [][]byte{[]byte("sc_by_address")},
+				CodeHashes: [][]byte{[]byte("allowed_hash")},
+			},
+		},
+	}
+	require.Nil(t, plugins.CheckCallAllowed(oracle, []byte("sc_by_address"), []byte("other_hash")))
+	require.Nil(t, plugins.CheckCallAllowed(oracle, []byte("sc_other"), []byte("allowed_hash")))
+	require.True(t, errors.Is(plugins.CheckCallAllowed(oracle, []byte("sc_other"), []byte("other_hash")), vmhost.ErrPluginCallNotAllowed))
+	require.True(t, errors.Is(plugins.CheckCallAllowed(oracle, []byte("sc_other"), nil), vmhost.ErrPluginCallNotAllowed))
+	require.True(t, errors.Is(plugins.CheckCallAllowed(random, []byte("sc_by_address"), []byte("allowed_hash")), vmhost.ErrPluginCallNotAllowed))
+
+	plugins.config = vmhost.PluginsConfig{DeterministicOnly: true}
+	require.Nil(t, plugins.CheckCallAllowed(oracle, []byte("sc_other"), []byte("other_hash")))
+	require.True(t, errors.Is(plugins.CheckCallAllowed(random, []byte("sc_other"), []byte("other_hash")), vmhost.ErrPluginNotDeterministic))
+	require.True(t, errors.Is(plugins.CheckCallAllowed(&statelessPlugin{}, []byte("sc_other"), []byte("other_hash")), vmhost.ErrPluginNotDeterministic))
+}
+
+func TestPluginsContext_RegisterPlugin(t *testing.T) {
+	plugins := &pluginsContext{
+		plugins: []vmhost.Plugin{contextmock.NewPluginStub("oracle", "getPrice")},
+	}
+
+	require.Equal(t, vmhost.ErrNilPlugin, plugins.RegisterPlugin(nil))
+	require.True(t, errors.Is(plugins.RegisterPlugin(contextmock.NewPluginStub("oracle")), vmhost.ErrPluginAlreadyRegistered))
+
+	require.Nil(t, plugins.RegisterPlugin(contextmock.NewPluginStub("bridge", "send")))
+	plugin, _, err := plugins.GetPluginMethod("bridge", "send")
+	require.Nil(t, err)
+	require.Equal(t, "bridge", plugin.Name())
+
+	plugins.config = vmhost.PluginsConfig{Disabled: true}
+	require.Equal(t, vmhost.ErrPluginsDisabled, plugins.RegisterPlugin(contextmock.NewPluginStub("random")))
+}
+
+func TestPluginsContext_UnregisterPlugin(t *testing.T) {
+	plugins := &pluginsContext{
+		plugins: []vmhost.Plugin{contextmock.NewPluginStub("oracle", "getPrice"), contextmock.NewPluginStub("bridge", "send")},
+	}
+
+	require.Nil(t, plugins.UnregisterPlugin("oracle"))
+	_, _, err := plugins.GetPluginMethod("oracle", "getPrice")
+	require.True(t, errors.Is(err, vmhost.ErrPluginNotFound))
+	require.True(t, errors.Is(plugins.UnregisterPlugin("oracle"), vmhost.ErrPluginNotFound))
+
+	_, _, err = plugins.GetPluginMethod("bridge", "send")
+	require.Nil(t, err)
+	require.Nil(t, plugins.RegisterPlugin(contextmock.NewPluginStub("oracle", "getPrice")))
+}
+
+func TestPlugins_LoadPlugins(t *testing.T) {
+	emptyDir := t.TempDir()
+	oracle := contextmock.NewPluginStub("oracle")
+
+	plugins, err := loadPlugins(vmhost.PluginsConfig{
+		Paths:    []string{emptyDir},
+		Plugins:  []vmhost.Plugin{oracle, oracle},
+		Required: []string{"oracle"},
+	})
+	require.Nil(t, err)
+	require.Len(t, plugins, 1)
+	require.Equal(t, "oracle", plugins[0].Name())
+
+	_, err = loadPlugins(vmhost.PluginsConfig{
+		Paths:    []string{emptyDir},
+		Required: []string{"oracle"},
+	})
+	require.True(t, errors.Is(err, vmhost.ErrRequiredPluginMissing))
+
+	plugins, err = loadPlugins(vmhost.PluginsConfig{
+		Disabled: true,
+		Plugins:  []vmhost.Plugin{oracle},
+	})
+	require.Nil(t, err)
+	require.Empty(t, plugins)
+}
+
+func TestPlugins_FindPluginLibraries(t *testing.T) {
+	pluginsDir := t.TempDir()
+	require.Nil(t, os.WriteFile(path.Join(pluginsDir, "oracle.so"), []byte("lib"), 0644))
+	require.Nil(t, os.WriteFile(path.Join(pluginsDir, "README.md"), []byte("doc"), 0644))
+	require.Nil(t, os.Mkdir(path.Join(pluginsDir, "nested.so"), 0755))
+
+	singleLibrary := path.Join(t.TempDir(), "bridge.so")
+	require.Nil(t, os.WriteFile(singleLibrary, []byte("lib"), 0644))
+
+	libraries := findPluginLibraries([]string{pluginsDir, singleLibrary, path.Join(pluginsDir, "missing")})
+	require.Equal(t, []string{path.Join(pluginsDir, "oracle.so"), singleLibrary}, libraries)
+}
+
+func TestPlugins_VerifyPluginLibrary(t *testing.T) {
+	libraryPath := path.Join(t.TempDir(), "oracle.so")
+	require.Nil(t, os.WriteFile(libraryPath, []byte("lib"), 0644))
+	libraryHash := sha256.Sum256([]byte("lib"))
+
+	require.Nil(t, verifyPluginLibrary(libraryPath, nil))
+	require.Nil(t, verifyPluginLibrary(libraryPath, map[string]string{"oracle.so": hex.EncodeToString(libraryHash[:])}))
+	require.Equal(t, vmhost.ErrPluginLibraryHashMismatch, verifyPluginLibrary(libraryPath, map[string]string{"oracle.so": "00"}))
+	require.Equal(t, vmhost.ErrPluginLibraryNotInManifest, verifyPluginLibrary(libraryPath, map[string]string{}))
+}
+
+// statelessPlugin implements neither vmhost.StatefulPlugin nor vmhost.DeterministicPlugin
+type statelessPlugin struct {
+}
+
+func (plugin *statelessPlugin) Name() string {
+	return "stateless"
+}
+
+func (plugin *statelessPlugin) Methods() []vmhost.PluginMethod {
+	return nil
+}
+
+func (plugin *statelessPlugin) Call(_ *vmhost.PluginCallContext, _ string, _ []byte) ([]byte, error) {
+	return nil, nil
+}
@@ -301,6 +301,12 @@ func (context *runtimeContext) GetSCCode() ([]byte, error) {
 	return code, nil
 }
 
+// GetCodeHash returns the hash of the code running in the current instance, which is not the code of the
+// context address under ExecuteOnSameContext, and is not yet saved to the blockchain during a deployment
+func (context *runtimeContext) GetCodeHash() []byte {
+	return context.iTracker.CodeHash()
+}
+
 // GetSCCodeSize returns the size of the current SC code.
 func (context *runtimeContext) GetSCCodeSize() uint64 {
 	if context.host.EnableEpochsHandler().IsRuntimeCodeSizeFixEnabled() {
@@ -639,13 +645,22 @@ func (context *runtimeContext) VerifyContractCode() error {
 		return err
 	}
 
-	err = context.validator.verifyFunctions(context.iTracker.Instance())
+	enableEpochsHandler := context.host.EnableEpochsHandler()
+	pluginsEnabled := vmhost.IsVMPluginsFlagEnabled(enableEpochsHandler)
+	err = context.validator.verifyFunctions(context.iTracker.Instance(), pluginsEnabled)
 	if err != nil {
 		logRuntime.Trace("verify contract code", "error", err)
 		return err
 	}
 
-	enableEpochsHandler := context.host.EnableEpochsHandler()
+	if pluginsEnabled {
+		err = context.validator.verifyPluginAllocator(context.iTracker.Instance())
+		if err != nil {
+			logRuntime.Trace("verify contract code", "error", err)
+			return err
+		}
+	}
+
 	if !enableEpochsHandler.IsStorageAPICostOptimizationFlagEnabled() {
 		err = context.checkBackwardCompatibility()
 		if err != nil {
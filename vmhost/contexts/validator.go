@@ -11,6 +11,8 @@ import (
 
 const noArity = -1
 
+const pluginAllocatorName = "mx_alloc"
+
 // wasmValidator is a validator for WASM SmartContracts
 type wasmValidator struct {
 	reserved *reservedFunctions
@@ -31,19 +33,42 @@ func (validator *wasmValidator) verifyMemoryDeclaration(instance wasmer.Instance
 	return nil
 }
 
-func (validator *wasmValidator) verifyFunctions(instance wasmer.InstanceHandler) error {
+// verifyFunctions checks the names and the signatures of the exported functions; once the VM plugins are enabled,
+// the mx_alloc(size) -> offset allocator of the contracts importing mxPlugCall is exempted from being void
+func (validator *wasmValidator) verifyFunctions(instance wasmer.InstanceHandler, pluginsEnabled bool) error {
+	hasPluginAllocator := pluginsEnabled && instance.IsFunctionImported("mxPlugCall")
 	for functionName := range instance.GetExports() {
 		err := validator.verifyValidFunctionName(functionName)
 		if err != nil {
 			return err
 		}
 
-		if functionName != "mx_alloc" {
-			err = validator.verifyVoidFunction(instance, functionName)
-			if err != nil {
-				return err
-			}
+		if hasPluginAllocator && functionName == pluginAllocatorName {
+			continue
 		}
+
+		err = validator.verifyVoidFunction(instance, functionName)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// verifyPluginAllocator checks that a contract importing mxPlugCall exports the mx_alloc(size) -> offset
+// function, through which the VM hands over the plugin results; mxPlugCallManaged does not need it
+func (validator *wasmValidator) verifyPluginAllocator(instance wasmer.InstanceHandler) error {
+	if !instance.IsFunctionImported("mxPlugCall") {
+		return nil
+	}
+
+	signature, ok := instance.GetSignature(pluginAllocatorName)
+	if !ok {
+		return fmt.Errorf("%w: mx_alloc not exported", vmhost.ErrInvalidPluginAllocator)
+	}
+	if signature.InputArity != 1 || signature.OutputArity != 1 {
+		return fmt.Errorf("%w: mx_alloc must take the size and return the offset", vmhost.ErrInvalidPluginAllocator)
 	}
 
 	return nil
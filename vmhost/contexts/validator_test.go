@@ -117,3 +117,50 @@ func TestFunctionsProtected(t *testing.T) {
 	err := validator.verifyProtectedFunctions(instance)
 	require.NotNil(t, err)
 }
+
+func TestFunctionsGuard_PluginAllocator(t *testing.T) {
+	validator := newWASMValidator(MakeAPIImports().Names(), builtInFunctions.NewBuiltInFunctionContainer())
+
+	instance := contextmock.NewInstanceMock(nil)
+	require.Nil(t, validator.verifyPluginAllocator(instance))
+
+	instance.Exports["mxPlugCall"] = &wasmer.ExportedFunctionCallInfo{}
+	err := validator.verifyPluginAllocator(instance)
+	require.ErrorIs(t, err, vmhost.ErrInvalidPluginAllocator)
+
+	instance.Exports["mx_alloc"] = &wasmer.ExportedFunctionCallInfo{}
+	err = validator.verifyPluginAllocator(instance)
+	require.ErrorIs(t, err, vmhost.ErrInvalidPluginAllocator)
+
+	instance.Signatures["mx_alloc"] = &wasmer.ExportedFunctionSignature{InputArity: 1, OutputArity: 1}
+	require.Nil(t, validator.verifyPluginAllocator(instance))
+}
+
+type importingInstanceMock struct {
+	*contextmock.InstanceMock
+	imports map[string]bool
+}
+
+func (instance *importingInstanceMock) IsFunctionImported(name string) bool {
+	return instance.imports[name]
+}
+
+func TestFunctionsGuard_PluginAllocatorExemption(t *testing.T) {
+	validator := newWASMValidator(MakeAPIImports().Names(), builtInFunctions.NewBuiltInFunctionContainer())
+
+	instance := &importingInstanceMock{
+		InstanceMock: contextmock.NewInstanceMock(nil),
+		imports:      make(map[string]bool),
+	}
+	instance.Exports["mx_alloc"] = &wasmer.ExportedFunctionCallInfo{}
+	instance.Signatures["mx_alloc"] = &wasmer.ExportedFunctionSignature{InputArity: 1, OutputArity: 1}
+
+	err := validator.verifyFunctions(instance, true)
+	require.ErrorIs(t, err, vmhost.ErrFunctionNonvoidSignature)
+
+	instance.imports["mxPlugCall"] = true
+	err = validator.verifyFunctions(instance, false)
+	require.ErrorIs(t, err, vmhost.ErrFunctionNonvoidSignature)
+
+	require.Nil(t, validator.verifyFunctions(instance, true))
+}
@@ -245,3 +245,59 @@ var ErrInvalidBuiltInFunctionCall = errors.New("invalid built in function call")
 
 // ErrCannotWriteOnReadOnly signals that write operation on read only is not allowed
 var ErrCannotWriteOnReadOnly = errors.New("cannot write on read only mode")
+
+// ErrPluginNotFound signals that the called VM plugin is not loaded
+var ErrPluginNotFound = errors.New("plugin not found")
+
+// ErrPluginMethodNotFound signals that the called VM plugin does not expose the requested method
+var ErrPluginMethodNotFound = errors.New("plugin method not found")
+
+// ErrInvalidPluginArguments signals that the arguments buffer of a plugin call is malformed
+var ErrInvalidPluginArguments = errors.New("invalid plugin call arguments")
+
+// ErrPluginCallFailed signals that a VM plugin reported a failure
+var ErrPluginCallFailed = errors.New("plugin call failed")
+
+// ErrPluginResultTooLarge signals that a VM plugin returned more than MaxPluginResultLength bytes
+var ErrPluginResultTooLarge = errors.New("plugin result too large")
+
+// ErrPluginCallNotAllowed signals that the calling contract is not allowed to call the VM plugin
+var ErrPluginCallNotAllowed = errors.New("plugin call not allowed")
+
+// ErrPluginNotDeterministic signals that a non-deterministic VM plugin was called while only deterministic plugins are allowed
+var ErrPluginNotDeterministic = errors.New("plugin is not deterministic")
+
+// ErrInvalidPluginAllocator signals that a contract importing mxPlugCall does not export a valid mx_alloc function
+var ErrInvalidPluginAllocator = errors.New("invalid plugin result allocator")
+
+// ErrNilPlugin signals that a nil VM plugin was provided
+var ErrNilPlugin = errors.New("nil plugin")
+
+// ErrPluginAlreadyRegistered signals that a VM plugin with the same name is already registered
+var ErrPluginAlreadyRegistered = errors.New("plugin already registered")
+
+// ErrPluginsDisabled signals that a VM plugin was registered while plugins are disabled
+var ErrPluginsDisabled = errors.New("plugins are disabled")
+
+// ErrRequiredPluginMissing signals that a VM plugin required by the configuration could not be loaded
+var ErrRequiredPluginMissing = errors.New("required plugin missing")
+
+// ErrInvalidPluginLibrary signals that a library does not export the VM plugin ABI
+var ErrInvalidPluginLibrary = errors.New("library does not export mx_plug_init and mx_plug_call")
+
+// ErrPluginLibraryNotInManifest signals that a plugin library has no expected hash configured
+var ErrPluginLibraryNotInManifest = errors.New("plugin library not in manifest")
+
+// ErrPluginLibraryHashMismatch signals that a plugin library does not match its expected hash
+var ErrPluginLibraryHashMismatch = errors.New("plugin library hash mismatch")
+
+// PluginUserError is returned by a plugin call when the plugin signals a user error,
+// which ends the execution the same way signalError does
+type PluginUserError struct {
+	Message string
+}
+
+// Error returns the message signalled by the plugin
+func (err *PluginUserError) Error() string {
+	return err.Message
+}
@@ -10,6 +10,7 @@ import (
 	"unsafe"
 
 	logger "github.com/multiversx/mx-chain-logger-go"
+	vmcommon "github.com/multiversx/mx-chain-vm-common-go"
 	"github.com/multiversx/mx-chain-vm-v1_4-go/crypto"
 	"github.com/multiversx/mx-chain-vm-v1_4-go/math"
 	"github.com/multiversx/mx-chain-vm-v1_4-go/wasmer"
@@ -25,6 +26,13 @@ const logVMHookCalls = false
 
 var log = logger.GetOrCreate("vm/host")
 
+// IsVMPluginsFlagEnabled tells whether the given handler activates the contract validation rules of the VM plugins;
+// handlers that do not implement PluginsEnableEpochsHandler keep them disabled
+func IsVMPluginsFlagEnabled(enableEpochsHandler vmcommon.EnableEpochsHandler) bool {
+	pluginsEnableEpochsHandler, ok := enableEpochsHandler.(PluginsEnableEpochsHandler)
+	return ok && pluginsEnableEpochsHandler.IsVMPluginsFlagEnabled()
+}
+
 // CustomStorageKey appends the given key type to the given associated key
 func CustomStorageKey(keyType string, associatedKey []byte) []byte {
 	return append(associatedKey, []byte(keyType)...)
@@ -192,7 +200,8 @@ func GetStorageContext(vmHostPtr unsafe.Pointer) StorageContext {
 	return GetVMHost(vmHostPtr).Storage()
 }
 
-func GetPluginsContext(vmHostPtr unsafe.Pointer) *PluginsContext {
+// GetPluginsContext returns the plugins context
+func GetPluginsContext(vmHostPtr unsafe.Pointer) PluginsContext {
 	return GetVMHost(vmHostPtr).Plugins()
 }
 
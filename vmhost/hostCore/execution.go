@@ -29,6 +29,10 @@ func (host *vmHost) doRunSmartContractCreate(input *vmcommon.ContractCreateInput
 	_, blockchain, metering, output, runtime, storage := host.GetContexts()
 
 	var vmOutput *vmcommon.VMOutput
+	host.pluginsContext.PushState()
+	defer func() {
+		host.finishPluginsState(vmOutput)
+	}()
 	defer func() {
 		if vmOutput == nil || vmOutput.ReturnCode == vmcommon.ExecutionFailed {
 			runtime.CleanInstance()
@@ -127,6 +131,10 @@ func (host *vmHost) doRunSmartContractUpgrade(input *vmcommon.ContractCallInput)
 	_, _, metering, output, runtime, storage := host.GetContexts()
 
 	var vmOutput *vmcommon.VMOutput
+	host.pluginsContext.PushState()
+	defer func() {
+		host.finishPluginsState(vmOutput)
+	}()
 	defer func() {
 		if vmOutput == nil || vmOutput.ReturnCode == vmcommon.ExecutionFailed {
 			runtime.CleanInstance()
@@ -161,6 +169,16 @@ func (host *vmHost) doRunSmartContractUpgrade(input *vmcommon.ContractCallInput)
 	return vmOutput
 }
 
+// finishPluginsState keeps the plugin side effects of a successful execution and reverts them otherwise
+func (host *vmHost) finishPluginsState(vmOutput *vmcommon.VMOutput) {
+	if vmOutput == nil || vmOutput.ReturnCode != vmcommon.Ok {
+		host.pluginsContext.PopSetActiveState()
+		return
+	}
+
+	host.pluginsContext.PopDiscard()
+}
+
 func (host *vmHost) checkGasForGetCode(input *vmcommon.ContractCallInput, metering vmhost.MeteringContext) error {
 	getCodeBaseCost := metering.GasSchedule().BaseOperationCost.GetCode
 	if input.GasProvided < getCodeBaseCost {
@@ -182,6 +200,10 @@ func (host *vmHost) doRunSmartContractCall(input *vmcommon.ContractCallInput) *v
 	_, _, metering, output, runtime, storage := host.GetContexts()
 
 	var vmOutput *vmcommon.VMOutput
+	host.pluginsContext.PushState()
+	defer func() {
+		host.finishPluginsState(vmOutput)
+	}()
 	defer func() {
 		if vmOutput == nil || vmOutput.ReturnCode == vmcommon.ExecutionFailed {
 			host.Runtime().CleanInstance()
@@ -326,6 +348,8 @@ func (host *vmHost) executeOnDestContextNoBuiltinFunction(input *vmcommon.Contra
 	storage.PushState()
 	storage.SetAddress(runtime.GetContextAddress())
 
+	host.pluginsContext.PushState()
+
 	defer func() {
 		vmOutput = host.finishExecuteOnDestContext(err)
 
@@ -378,9 +402,11 @@ func (host *vmHost) finishExecuteOnDestContext(executeErr error) *vmcommon.VMOut
 	if vmOutput.ReturnCode == vmcommon.Ok {
 		metering.PopMergeActiveState()
 		output.PopMergeActiveState()
+		host.pluginsContext.PopDiscard()
 	} else {
 		metering.PopSetActiveState()
 		output.PopSetActiveState()
+		host.pluginsContext.PopSetActiveState()
 	}
 
 	// Return to the caller context completely
@@ -427,6 +453,7 @@ func (host *vmHost) ExecuteOnSameContext(input *vmcommon.ContractCallInput) (asy
 	metering.InitStateFromContractCallInput(&input.VMInput)
 
 	blockchain.PushState()
+	host.pluginsContext.PushState()
 
 	defer func() {
 		runtime.AddError(err, input.Function)
@@ -458,6 +485,7 @@ func (host *vmHost) finishExecuteOnSameContext(executeErr error) {
 		metering.PopSetActiveState()
 		output.PopSetActiveState()
 		blockchain.PopSetActiveState()
+		host.pluginsContext.PopSetActiveState()
 		runtime.PopSetActiveState()
 		return
 	}
@@ -470,6 +498,7 @@ func (host *vmHost) finishExecuteOnSameContext(executeErr error) {
 	metering.PopMergeActiveState()
 	output.PopDiscard()
 	blockchain.PopDiscard()
+	host.pluginsContext.PopDiscard()
 	managedTypes.PopSetActiveState()
 	runtime.PopSetActiveState()
 	// Restore remaining gas to the caller (parent) Wasmer instance
@@ -44,7 +44,7 @@ type vmHost struct {
 	meteringContext     vmhost.MeteringContext
 	storageContext      vmhost.StorageContext
 	managedTypesContext vmhost.ManagedTypesContext
-	pluginsContext      *vmhost.PluginsContext
+	pluginsContext      vmhost.PluginsContext
 
 	gasSchedule          config.GasScheduleMap
 	scAPIMethods         *wasmer.Imports
@@ -161,7 +161,10 @@ func NewVMHost(
 		return nil, err
 	}
 
-	host.pluginsContext = contexts.NewPluginsContext(host)
+	host.pluginsContext, err = contexts.NewPluginsContext(host, hostParameters.PluginsConfig)
+	if err != nil {
+		return nil, err
+	}
 
 	gasCostConfig, err := config.CreateGasConfig(host.gasSchedule)
 	if err != nil {
@@ -246,7 +249,8 @@ func (host *vmHost) ManagedTypes() vmhost.ManagedTypesContext {
 	return host.managedTypesContext
 }
 
-func (host *vmHost) Plugins() *vmhost.PluginsContext {
+// Plugins returns the PluginsContext instance of the host
+func (host *vmHost) Plugins() vmhost.PluginsContext {
 	return host.pluginsContext
 }
 
@@ -301,6 +305,7 @@ func (host *vmHost) initContexts() {
 	host.meteringContext.InitState()
 	host.runtimeContext.InitState()
 	host.storageContext.InitState()
+	host.pluginsContext.InitState()
 	host.ethInput = nil
 }
 
@@ -311,6 +316,7 @@ func (host *vmHost) ClearContextStateStack() {
 	host.meteringContext.ClearStateStack()
 	host.runtimeContext.ClearStateStack()
 	host.storageContext.ClearStateStack()
+	host.pluginsContext.ClearStateStack()
 }
 
 // GetAPIMethods returns the EEI as a set of imports for Wasmer
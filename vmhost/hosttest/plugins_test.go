@@ -0,0 +1,413 @@
+package hostCoretest
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	mock "github.com/multiversx/mx-chain-vm-v1_4-go/mock/context"
+	worldmock "github.com/multiversx/mx-chain-vm-v1_4-go/mock/world"
+	test "github.com/multiversx/mx-chain-vm-v1_4-go/testcommon"
+	"github.com/multiversx/mx-chain-vm-v1_4-go/vmhost"
+	"github.com/multiversx/mx-chain-vm-v1_4-go/vmhost/vmhooks"
+	"github.com/stretchr/testify/require"
+)
+
+func pluginCallerMockContract(pluginName string, methodName string, args []byte, failAfterCall bool) test.MockTestSmartContract {
+	return test.CreateMockContract(test.ParentAddress).
+		WithBalance(1000).
+		WithMethods(func(parentInstance *mock.InstanceMock, config interface{}) {
+			parentInstance.AddMockMethod("callPlugin", func() *mock.InstanceMock {
+				host := parentInstance.Host
+				result := vmhooks.MxPlugCallWithTypedArgs(host, pluginName, methodName, args)
+				if result == nil {
+					return parentInstance
+				}
+				host.Output().Finish(result)
+				if failAfterCall {
+					vmhost.WithFaultAndHost(host, vmhost.ErrSignalError, true)
+				}
+				return parentInstance
+			})
+		})
+}
+
+func managedPluginCallerMockContract(pluginName string, methodName string, args []byte) test.MockTestSmartContract {
+	return test.CreateMockContract(test.ParentAddress).
+		WithBalance(1000).
+		WithMethods(func(parentInstance *mock.InstanceMock, config interface{}) {
+			parentInstance.AddMockMethod("callPlugin", func() *mock.InstanceMock {
+				host := parentInstance.Host
+				managedType := host.ManagedTypes()
+				pluginNameHandle := managedType.NewManagedBufferFromBytes([]byte(pluginName))
+				methodNameHandle := managedType.NewManagedBufferFromBytes([]byte(methodName))
+				argsHandle := managedType.NewManagedBufferFromBytes(args)
+
+				resultHandle := vmhooks.MxPlugCallManagedWithHost(host, pluginNameHandle, methodNameHandle, argsHandle)
+				if resultHandle < 0 {
+					return parentInstance
+				}
+				result, err := managedType.GetBytes(resultHandle)
+				if vmhost.WithFaultAndHost(host, err, true) {
+					return parentInstance
+				}
+				host.Output().Finish(result)
+				return parentInstance
+			})
+		})
+}
+
+func pluginCallInput() *test.ContractCallInputBuilder {
+	return test.CreateTestContractCallInputBuilder().
+		WithRecipientAddr(test.ParentAddress).
+		WithCallerAddr(test.UserAddress).
+		WithGasProvided(1_000_000).
+		WithFunction("callPlugin")
+}
+
+func TestPlugins_CallGoPlugin(t *testing.T) {
+	var receivedCallCtx *vmhost.PluginCallContext
+	oracle := mock.NewPluginStub("oracle", "getPrice")
+	oracle.CallCalled = func(callCtx *vmhost.PluginCallContext, methodName string, args []byte) ([]byte, error) {
+		receivedCallCtx = callCtx
+		return append([]byte("price of "), args...), nil
+	}
+
+	test.BuildMockInstanceCallTest(t).
+		WithContracts(pluginCallerMockContract("oracle", "getPrice", []byte("EGLD"), false)).
+		WithPlugins(oracle).
+		WithInput(pluginCallInput().Build()).
+		AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+			verify.Ok().
+				ReturnData([]byte("price of EGLD"))
+		})
+
+	require.NotNil(t, receivedCallCtx)
+	require.Equal(t, uint32(vmhost.PluginCallContextVersion), receivedCallCtx.Version)
+	require.Equal(t, test.ParentAddress, receivedCallCtx.Address)
+	require.Equal(t, test.UserAddress, receivedCallCtx.Caller)
+}
+
+func TestPlugins_CallGoPlugin_UserError(t *testing.T) {
+	oracle := mock.NewPluginStub("oracle", "getPrice")
+	oracle.CallCalled = func(_ *vmhost.PluginCallContext, _ string, _ []byte) ([]byte, error) {
+		return nil, &vmhost.PluginUserError{Message: "price too old"}
+	}
+
+	test.BuildMockInstanceCallTest(t).
+		WithContracts(pluginCallerMockContract("oracle", "getPrice", []byte("EGLD"), false)).
+		WithPlugins(oracle).
+		WithInput(pluginCallInput().Build()).
+		AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+			verify.UserError().
+				ReturnMessage("price too old")
+		})
+}
+
+func TestPlugins_CallGoPlugin_MethodNotFound(t *testing.T) {
+	test.BuildMockInstanceCallTest(t).
+		WithContracts(pluginCallerMockContract("oracle", "setPrice", []byte("EGLD"), false)).
+		WithPlugins(mock.NewPluginStub("oracle", "getPrice")).
+		WithInput(pluginCallInput().Build()).
+		AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+			verify.ExecutionFailed().
+				HasRuntimeErrors(vmhost.ErrPluginMethodNotFound.Error())
+		})
+}
+
+func TestPlugins_CallGoPlugin_RevertedWithFailedExecution(t *testing.T) {
+	calls := make([]string, 0)
+	oracle := mock.NewPluginStub("oracle", "setPrice")
+	oracle.SnapshotCalled = func() { calls = append(calls, "snapshot") }
+	oracle.RevertCalled = func() { calls = append(calls, "revert") }
+	oracle.CommitCalled = func() { calls = append(calls, "commit") }
+
+	test.BuildMockInstanceCallTest(t).
+		WithContracts(pluginCallerMockContract("oracle", "setPrice", []byte("EGLD"), true)).
+		WithPlugins(oracle).
+		WithInput(pluginCallInput().Build()).
+		AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+			verify.ExecutionFailed()
+		})
+
+	require.Equal(t, []string{"snapshot", "revert"}, calls)
+}
+
+func TestPlugins_CallGoPlugin_Managed(t *testing.T) {
+	oracle := mock.NewPluginStub("oracle", "getPrice")
+	oracle.CallCalled = func(_ *vmhost.PluginCallContext, _ string, args []byte) ([]byte, error) {
+		return append([]byte("price of "), args...), nil
+	}
+
+	test.BuildMockInstanceCallTest(t).
+		WithContracts(managedPluginCallerMockContract("oracle", "getPrice", []byte("EGLD"))).
+		WithPlugins(oracle).
+		WithInput(pluginCallInput().Build()).
+		AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+			verify.Ok().
+				ReturnData([]byte("price of EGLD"))
+		})
+}
+
+func TestPlugins_CallGoPlugin_Managed_UserError(t *testing.T) {
+	oracle := mock.NewPluginStub("oracle", "getPrice")
+	oracle.CallCalled = func(_ *vmhost.PluginCallContext, _ string, _ []byte) ([]byte, error) {
+		return nil, &vmhost.PluginUserError{Message: "price too old"}
+	}
+
+	test.BuildMockInstanceCallTest(t).
+		WithContracts(managedPluginCallerMockContract("oracle", "getPrice", []byte("EGLD"))).
+		WithPlugins(oracle).
+		WithInput(pluginCallInput().Build()).
+		AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+			verify.UserError().
+				ReturnMessage("price too old")
+		})
+}
+
+func TestPlugins_CallGoPlugin_GasCharged(t *testing.T) {
+	oracle := mock.NewPluginStub("oracle")
+	oracle.PluginMethods = []vmhost.PluginMethod{{Name: "getPrice", GasCost: 1000}}
+	oracle.CallCalled = func(_ *vmhost.PluginCallContext, _ string, args []byte) ([]byte, error) {
+		return append([]byte("price of "), args...), nil
+	}
+
+	args := []byte("EGLD")
+	var gasUsedByCall, expectedGasUsed uint64
+	contract := test.CreateMockContract(test.ParentAddress).
+		WithBalance(1000).
+		WithMethods(func(parentInstance *mock.InstanceMock, config interface{}) {
+			parentInstance.AddMockMethod("callPlugin", func() *mock.InstanceMock {
+				host := parentInstance.Host
+				gasBefore := host.Metering().GasLeft()
+				result := vmhooks.MxPlugCallWithTypedArgs(host, "oracle", "getPrice", args)
+				gasUsedByCall = gasBefore - host.Metering().GasLeft()
+
+				pluginCosts := host.Metering().GasSchedule().PluginAPICost
+				expectedGasUsed = pluginCosts.PluginCall + 1000 +
+					pluginCosts.PluginDataCopyPerByte*uint64(len(args)+len(result))
+				return parentInstance
+			})
+		})
+
+	test.BuildMockInstanceCallTest(t).
+		WithContracts(contract).
+		WithPlugins(oracle).
+		WithInput(pluginCallInput().Build()).
+		AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+			verify.Ok()
+		})
+
+	require.NotZero(t, expectedGasUsed)
+	require.Equal(t, expectedGasUsed, gasUsedByCall)
+}
+
+func TestPlugins_CallGoPlugin_OutOfGas(t *testing.T) {
+	called := false
+	oracle := mock.NewPluginStub("oracle")
+	oracle.PluginMethods = []vmhost.PluginMethod{{Name: "getPrice", GasCost: 10_000_000}}
+	oracle.CallCalled = func(_ *vmhost.PluginCallContext, _ string, _ []byte) ([]byte, error) {
+		called = true
+		return []byte("price"), nil
+	}
+
+	test.BuildMockInstanceCallTest(t).
+		WithContracts(pluginCallerMockContract("oracle", "getPrice", []byte("EGLD"), false)).
+		WithPlugins(oracle).
+		WithInput(pluginCallInput().Build()).
+		AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+			verify.OutOfGas()
+		})
+
+	require.False(t, called)
+}
+
+func TestPlugins_CallGoPlugin_ResultTooLarge(t *testing.T) {
+	oracle := mock.NewPluginStub("oracle", "getPrice")
+	oracle.CallCalled = func(_ *vmhost.PluginCallContext, _ string, _ []byte) ([]byte, error) {
+		return make([]byte, vmhost.MaxPluginResultLength+1), nil
+	}
+
+	test.BuildMockInstanceCallTest(t).
+		WithContracts(pluginCallerMockContract("oracle", "getPrice", []byte("EGLD"), false)).
+		WithPlugins(oracle).
+		WithInput(pluginCallInput().Build()).
+		AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+			verify.ExecutionFailed().
+				HasRuntimeErrors(vmhost.ErrPluginResultTooLarge.Error())
+		})
+}
+
+func TestPlugins_CallGoPlugin_CallContext(t *testing.T) {
+	callContexts := make([]*vmhost.PluginCallContext, 0)
+	oracle := mock.NewPluginStub("oracle", "getPrice")
+	oracle.CallCalled = func(callCtx *vmhost.PluginCallContext, _ string, _ []byte) ([]byte, error) {
+		callContexts = append(callContexts, callCtx)
+		return []byte("price"), nil
+	}
+
+	contract := test.CreateMockContract(test.ParentAddress).
+		WithBalance(1000).
+		WithMethods(func(parentInstance *mock.InstanceMock, config interface{}) {
+			parentInstance.AddMockMethod("callPlugin", func() *mock.InstanceMock {
+				host := parentInstance.Host
+				vmhooks.MxPlugCallWithTypedArgs(host, "oracle", "getPrice", nil)
+
+				host.Runtime().SetReadOnly(true)
+				vmhooks.MxPlugCallWithTypedArgs(host, "oracle", "getPrice", nil)
+				host.Runtime().SetReadOnly(false)
+				return parentInstance
+			})
+		})
+
+	input := pluginCallInput().
+		WithCallValue(42).
+		WithCurrentTxHash([]byte("currentTxHash")).
+		WithESDTTokenName([]byte("TOKEN-abcdef")).
+		WithESDTValue(big.NewInt(7)).
+		Build()
+	input.OriginalTxHash = []byte("originalTxHash")
+	input.ESDTTransfers[0].ESDTTokenNonce = 3
+	input.ESDTTransfers[0].ESDTTokenType = uint32(core.NonFungible)
+
+	test.BuildMockInstanceCallTest(t).
+		WithContracts(contract).
+		WithPlugins(oracle).
+		WithSetup(func(host vmhost.VMHost, world *worldmock.MockWorld) {
+			world.CurrentBlockInfo = &worldmock.BlockInfo{
+				BlockNonce:     10,
+				BlockRound:     11,
+				BlockEpoch:     12,
+				BlockTimestamp: 13,
+			}
+		}).
+		WithInput(input).
+		AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+			verify.Ok()
+		})
+
+	require.Len(t, callContexts, 2)
+	callCtx := callContexts[0]
+	require.Equal(t, []byte("originalTxHash"), callCtx.OriginalTxHash)
+	require.Equal(t, []byte("currentTxHash"), callCtx.CurrentTxHash)
+	require.Equal(t, "42", callCtx.CallValue)
+	require.Equal(t, []vmhost.PluginESDTTransfer{
+		{
+			TokenIdentifier: "TOKEN-abcdef",
+			Nonce:           3,
+			Value:           "7",
+			TokenType:       uint32(core.NonFungible),
+		},
+	}, callCtx.ESDTTransfers)
+	require.Equal(t, uint64(10), callCtx.BlockNonce)
+	require.Equal(t, uint64(11), callCtx.BlockRound)
+	require.Equal(t, uint32(12), callCtx.BlockEpoch)
+	require.Equal(t, uint64(13), callCtx.BlockTimestamp)
+	require.False(t, callCtx.ReadOnly)
+	require.True(t, callContexts[1].ReadOnly)
+}
+
+func sameContextPluginCallerMockContracts() []test.MockTestSmartContract {
+	parent := test.CreateMockContract(test.ParentAddress).
+		WithBalance(1000).
+		WithMethods(func(parentInstance *mock.InstanceMock, config interface{}) {
+			parentInstance.AddMockMethod("callPlugin", func() *mock.InstanceMock {
+				host := parentInstance.Host
+				vmhooks.ExecuteOnSameContextWithTypedArgs(host, 100_000, big.NewInt(0), []byte("callPlugin"), test.ChildAddress, nil)
+				return parentInstance
+			})
+		})
+
+	return []test.MockTestSmartContract{
+		parent,
+		pluginCallerMockContractAt(test.ChildAddress),
+	}
+}
+
+func pluginCallerMockContractAt(address []byte) test.MockTestSmartContract {
+	return test.CreateMockContract(address).
+		WithBalance(1000).
+		WithMethods(func(instance *mock.InstanceMock, config interface{}) {
+			instance.AddMockMethod("callPlugin", func() *mock.InstanceMock {
+				host := instance.Host
+				result := vmhooks.MxPlugCallWithTypedArgs(host, "oracle", "getPrice", []byte("EGLD"))
+				if result != nil {
+					host.Output().Finish(result)
+				}
+				return instance
+			})
+		})
+}
+
+// pluginPermissionsForCode allows calling the oracle plugin only from the given code; the code of
+// the mock contracts is their address
+func pluginPermissionsForCode(code []byte) vmhost.PluginsConfig {
+	return vmhost.PluginsConfig{
+		Permissions: map[string]vmhost.PluginPermission{
+			"oracle": {CodeHashes: [][]byte{worldmock.DefaultHasher.Compute(string(code))}},
+		},
+	}
+}
+
+func TestPlugins_CallGoPlugin_ExecuteOnSameContext_AllowedByRunningCode(t *testing.T) {
+	oracle := mock.NewPluginStub("oracle", "getPrice")
+	oracle.CallCalled = func(_ *vmhost.PluginCallContext, _ string, _ []byte) ([]byte, error) {
+		return []byte("price"), nil
+	}
+
+	test.BuildMockInstanceCallTest(t).
+		WithContracts(sameContextPluginCallerMockContracts()...).
+		WithPlugins(oracle).
+		WithPluginsConfig(pluginPermissionsForCode(test.ChildAddress)).
+		WithInput(pluginCallInput().Build()).
+		AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+			verify.Ok().
+				ReturnData([]byte("price"))
+		})
+}
+
+func TestPlugins_CallGoPlugin_ExecuteOnSameContext_NotAllowedByCallerCode(t *testing.T) {
+	test.BuildMockInstanceCallTest(t).
+		WithContracts(sameContextPluginCallerMockContracts()...).
+		WithPlugins(mock.NewPluginStub("oracle", "getPrice")).
+		WithPluginsConfig(pluginPermissionsForCode(test.ParentAddress)).
+		WithInput(pluginCallInput().Build()).
+		AndAssertResults(func(world *worldmock.MockWorld, verify *test.VMOutputVerifier) {
+			verify.ExecutionFailed().
+				HasRuntimeErrors(vmhost.ErrPluginCallNotAllowed.Error())
+		})
+}
+
+func TestPlugins_CallGoPlugin_DeploymentInit_AllowedByDeployedCode(t *testing.T) {
+	deployedCode := []byte("pluginCallerCode")
+	oracle := mock.NewPluginStub("oracle", "getPrice")
+	oracle.CallCalled = func(_ *vmhost.PluginCallContext, _ string, _ []byte) ([]byte, error) {
+		return []byte("price"), nil
+	}
+	pluginsConfig := pluginPermissionsForCode(deployedCode)
+
+	host, world, imb := test.DefaultTestVMForCallWithInstanceMocksAndPlugins(t, pluginsConfig)
+	defer host.Reset()
+	world.AcctMap.CreateAccount(test.UserAddress, world)
+	require.Nil(t, host.Plugins().RegisterPlugin(oracle))
+
+	instance := imb.CreateAndStoreInstanceMock(t, host, deployedCode, nil, nil, nil, 0, 0)
+	instance.AddMockMethod("init", func() *mock.InstanceMock {
+		result := vmhooks.MxPlugCallWithTypedArgs(host, "oracle", "getPrice", []byte("EGLD"))
+		if result != nil {
+			host.Output().Finish(result)
+		}
+		return instance
+	})
+
+	input := test.CreateTestContractCreateInputBuilder().
+		WithCallerAddr(test.UserAddress).
+		WithContractCode(deployedCode).
+		WithGasProvided(1_000_000).
+		Build()
+
+	vmOutput, err := host.RunSmartContractCreate(input)
+	verify := test.NewVMOutputVerifierWithAllErrors(t, vmOutput, err, host.Runtime().GetAllErrors())
+	verify.Ok().
+		ReturnData([]byte("price"))
+}
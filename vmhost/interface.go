@@ -4,7 +4,6 @@ import (
 	"crypto/elliptic"
 	"io"
 	"math/big"
-	"unsafe"
 
 	"github.com/multiversx/mx-chain-core-go/data/esdt"
 	"github.com/multiversx/mx-chain-core-go/data/vm"
@@ -39,7 +38,7 @@ type VMHost interface {
 	Output() OutputContext
 	Metering() MeteringContext
 	Storage() StorageContext
-	Plugins() *PluginsContext
+	Plugins() PluginsContext
 	EnableEpochsHandler() vmcommon.EnableEpochsHandler
 
 	ExecuteESDTTransfer(destination []byte, sender []byte, esdtTransfers []*vmcommon.ESDTTransfer, callType vm.CallType) (*vmcommon.VMOutput, uint64, error)
@@ -118,6 +117,7 @@ type RuntimeContext interface {
 	SetCodeAddress(scAddress []byte)
 	GetSCCode() ([]byte, error)
 	GetSCCodeSize() uint64
+	GetCodeHash() []byte
 	GetVMType() []byte
 	Function() string
 	Arguments() [][]byte
@@ -280,19 +280,47 @@ type MeteringContext interface {
 	GetGasTrace() map[string]map[string][]uint64
 }
 
-type VmPluginMethod struct {
-	Name string
+// PluginMethod describes a method exposed by a VM plugin, with the gas it costs on top of PluginAPICost.PluginCall
+type PluginMethod struct {
+	Name    string
+	GasCost uint64
 }
 
-type VmPlugin struct {
-	Name    string
-	Methods []VmPluginMethod
-	CallFn  func(callCtx string, methodName string, args []byte) unsafe.Pointer
+// Plugin defines a VM plugin, an extension of the VM API called by contracts through mxPlugCall;
+// Call returns a PluginUserError when the plugin rejects the call because of the contract input
+type Plugin interface {
+	Name() string
+	Methods() []PluginMethod
+	Call(callCtx *PluginCallContext, methodName string, args []byte) ([]byte, error)
+}
+
+// DeterministicPlugin is implemented by the plugins that produce the same results on every node
+type DeterministicPlugin interface {
+	IsDeterministic() bool
+}
+
+// StatefulPlugin is implemented by the plugins whose state follows the VM state stack
+type StatefulPlugin interface {
+	Snapshot()
+	Revert()
+	Commit()
+}
+
+// PluginsEnableEpochsHandler is implemented by the EnableEpochsHandlers knowing the flag that activates the
+// contract validation rules of the VM plugins, which the EnableEpochsHandler of vmcommon does not declare
+type PluginsEnableEpochsHandler interface {
+	IsVMPluginsFlagEnabled() bool
 }
 
-type PluginsContext struct {
-	Plugins []VmPlugin
-	Host    VMHost
+// PluginsContext defines the functionality needed for calling VM plugins; its state stack
+// keeps the plugin side effects in step with the output and storage of the running contracts
+type PluginsContext interface {
+	StateStack
+
+	RegisterPlugin(plugin Plugin) error
+	UnregisterPlugin(pluginName string) error
+	GetPluginMethod(pluginName string, methodName string) (Plugin, *PluginMethod, error)
+	CheckCallAllowed(plugin Plugin, contractAddress []byte, codeHash []byte) error
 }
 
 // StorageStatus defines the states the storage can be in
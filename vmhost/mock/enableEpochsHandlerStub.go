@@ -40,6 +40,7 @@ type EnableEpochsHandlerStub struct {
 	IsAlwaysSaveTokenMetaDataEnabledField                bool
 	IsGuardAccountEnabledField                           bool
 	IsSetGuardianEnabledField                            bool
+	IsVMPluginsFlagEnabledField                          bool
 	MultiESDTTransferAsyncCallBackEnableEpochField       uint32
 	FixOOGReturnCodeEnableEpochField                     uint32
 	RemoveNonUpdatedStorageEnableEpochField              uint32
@@ -62,6 +63,11 @@ func (stub *EnableEpochsHandlerStub) IsSetGuardianEnabled() bool {
 	return stub.IsSetGuardianEnabledField
 }
 
+// IsVMPluginsFlagEnabled -
+func (stub *EnableEpochsHandlerStub) IsVMPluginsFlagEnabled() bool {
+	return stub.IsVMPluginsFlagEnabledField
+}
+
 // IsFixOldTokenLiquidityEnabled -
 func (stub *EnableEpochsHandlerStub) IsFixOldTokenLiquidityEnabled() bool {
 	return stub.IsFixOldTokenLiquidityEnabledField
@@ -89,15 +89,16 @@ package vmhooks
 // extern void		v1_4_getOriginalTxHash(void *context, int32_t resultOffset);
 //
 // extern int32_t	v1_4_mxPlugCall(void *context, int32_t pluginNameOffset, int32_t pluginNameLen, int32_t methodNameOffset, int32_t methodNameLen, int32_t argsOffset);
+// extern int32_t	v1_4_mxPlugCallManaged(void *context, int32_t pluginNameHandle, int32_t methodNameHandle, int32_t argsHandle);
 //
 import "C"
 
 import (
 	"encoding/binary"
 	"encoding/hex"
-	"encoding/json"
 	"errors"
 	"fmt"
+	builtinMath "math"
 	"math/big"
 	"unsafe"
 
@@ -189,7 +190,7 @@ const (
 	getPrevBlockEpochName            = "getPrevBlockEpoch"
 	getPrevBlockRandomSeedName       = "getPrevBlockRandomSeed"
 	getOriginalTxHashName            = "getOriginalTxHash"
-	mxPlugCall                       = "mxPlugCall"
+	mxPlugCallName                   = "mxPlugCall"
 )
 
 var logEEI = logger.GetOrCreate("vm/eei")
@@ -600,6 +601,11 @@ func BaseOpsAPIImports(imports vmhooksmeta.EIFunctionReceiver) error {
 		return err
 	}
 
+	err = imports.Append("mxPlugCallManaged", v1_4_mxPlugCallManaged, C.v1_4_mxPlugCallManaged)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -3310,76 +3316,230 @@ func v1_4_getReturnData(context unsafe.Pointer, resultID int32, dataOffset int32
 	return int32(len(result))
 }
 
-type plugCallContext struct {
-	Address []byte
-}
+func newPluginCallContext(host vmhost.VMHost) *vmhost.PluginCallContext {
+	runtime := host.Runtime()
+	blockchain := host.Blockchain()
+	vmInput := runtime.GetVMInput()
+
+	callValue := "0"
+	if vmInput.CallValue != nil {
+		callValue = vmInput.CallValue.String()
+	}
 
-func encodeCallCtx(callCtx plugCallContext) string {
-	encodedCallCtx, _ := json.Marshal(callCtx)
-	return string(encodedCallCtx)
+	esdtTransfers := make([]vmhost.PluginESDTTransfer, len(vmInput.ESDTTransfers))
+	for i, transfer := range vmInput.ESDTTransfers {
+		esdtTransfers[i] = vmhost.PluginESDTTransfer{
+			TokenIdentifier: string(transfer.ESDTTokenName),
+			Nonce:           transfer.ESDTTokenNonce,
+			Value:           transfer.ESDTValue.String(),
+			TokenType:       transfer.ESDTTokenType,
+		}
+	}
+
+	return &vmhost.PluginCallContext{
+		Version:        vmhost.PluginCallContextVersion,
+		Address:        runtime.GetContextAddress(),
+		Caller:         vmInput.CallerAddr,
+		OriginalTxHash: runtime.GetOriginalTxHash(),
+		CurrentTxHash:  runtime.GetCurrentTxHash(),
+		CallValue:      callValue,
+		ESDTTransfers:  esdtTransfers,
+		BlockNonce:     blockchain.CurrentNonce(),
+		BlockRound:     blockchain.CurrentRound(),
+		BlockEpoch:     blockchain.CurrentEpoch(),
+		BlockTimestamp: blockchain.CurrentTimeStamp(),
+		ReadOnly:       runtime.ReadOnly(),
+	}
 }
 
 //export v1_4_mxPlugCall
 func v1_4_mxPlugCall(context unsafe.Pointer, pluginNameOffset int32, pluginNameLen int32, methodNameOffset int32, methodNameLen int32, argsOffset int32) int32 {
-	runtime := vmhost.GetRuntimeContext(context)
+	host := vmhost.GetVMHost(context)
+	return MxPlugCallWithHost(
+		host,
+		pluginNameOffset,
+		pluginNameLen,
+		methodNameOffset,
+		methodNameLen,
+		argsOffset,
+	)
+}
+
+// MxPlugCallWithHost - mxPlugCall with host instead of pointer context
+func MxPlugCallWithHost(host vmhost.VMHost, pluginNameOffset int32, pluginNameLen int32, methodNameOffset int32, methodNameLen int32, argsOffset int32) int32 {
+	runtime := host.Runtime()
+
+	pluginName, err := runtime.MemLoad(pluginNameOffset, pluginNameLen)
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return -1
+	}
+
+	methodName, err := runtime.MemLoad(methodNameOffset, methodNameLen)
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return -1
+	}
 
-	pluginNameBytes, _ := runtime.MemLoad(pluginNameOffset, pluginNameLen)
-	pluginName := string(pluginNameBytes)
+	args, err := loadPluginArguments(runtime, argsOffset)
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return -1
+	}
 
-	methodNameBytes, _ := runtime.MemLoad(methodNameOffset, methodNameLen)
-	methodName := string(methodNameBytes)
+	result := MxPlugCallWithTypedArgs(host, string(pluginName), string(methodName), args)
+	if result == nil {
+		return -1
+	}
 
-	argsLenBytes, _ := runtime.MemLoad(argsOffset, 8)
-	argsLen := uint64(binary.LittleEndian.Uint64(argsLenBytes))
+	prefixedResult := make([]byte, vmhost.PluginLengthPrefixSize+len(result))
+	binary.LittleEndian.PutUint64(prefixedResult, uint64(len(result)))
+	copy(prefixedResult[vmhost.PluginLengthPrefixSize:], result)
 
-	args, _ := runtime.MemLoad(argsOffset, int32(argsLen+8))
+	allocOffset, err := pluginMemAlloc(runtime, int32(len(prefixedResult)))
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return -1
+	}
 
-	callCtx := plugCallContext{
-		Address: runtime.GetContextAddress(),
+	err = runtime.MemStore(allocOffset, prefixedResult)
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return -1
 	}
 
-	plugins := vmhost.GetPluginsContext(context)
+	return allocOffset
+}
 
-	result := CallPlugin(plugins, callCtx, pluginName, methodName, args)
+//export v1_4_mxPlugCallManaged
+func v1_4_mxPlugCallManaged(context unsafe.Pointer, pluginNameHandle int32, methodNameHandle int32, argsHandle int32) int32 {
+	host := vmhost.GetVMHost(context)
+	return MxPlugCallManagedWithHost(host, pluginNameHandle, methodNameHandle, argsHandle)
+}
+
+// MxPlugCallManagedWithHost - mxPlugCallManaged with host instead of pointer context; returns the
+// handle of a new managed buffer holding the plugin result, so the contract needs no mx_alloc
+func MxPlugCallManagedWithHost(host vmhost.VMHost, pluginNameHandle int32, methodNameHandle int32, argsHandle int32) int32 {
+	runtime := host.Runtime()
+	managedType := host.ManagedTypes()
 
-	result_len_bytes := make([]byte, 8)
-	for i := 0; i < 8; i++ {
-		result_len_bytes[i] = *(*byte)(unsafe.Pointer(uintptr(result) + uintptr(i)))
+	pluginName, err := managedType.GetBytes(pluginNameHandle)
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return -1
 	}
-	result_len := uint64(binary.LittleEndian.Uint64(result_len_bytes))
 
-	result_data := make([]byte, 8+result_len)
-	for i := 0; i < len(result_data); i++ {
-		result_data[i] = *(*byte)(unsafe.Pointer(uintptr(result) + uintptr(i)))
+	methodName, err := managedType.GetBytes(methodNameHandle)
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return -1
 	}
 
-	alloc_offset := pluginMemAlloc(runtime, int32(result_len+8))
-	runtime.MemStore(alloc_offset, result_data)
+	args, err := managedType.GetBytes(argsHandle)
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return -1
+	}
 
-	return alloc_offset
+	result := MxPlugCallWithTypedArgs(host, string(pluginName), string(methodName), args)
+	if result == nil {
+		return -1
+	}
+
+	return managedType.NewManagedBufferFromBytes(result)
 }
 
-func pluginMemAlloc(runtime vmhost.RuntimeContext, len int32) int32 {
+// MxPlugCallWithTypedArgs - mxPlugCall with args already read from memory; returns the
+// plugin result, or nil if the call failed
+func MxPlugCallWithTypedArgs(host vmhost.VMHost, pluginName string, methodName string, args []byte) []byte {
+	runtime := host.Runtime()
+	metering := host.Metering()
+	plugins := host.Plugins()
+
+	metering.StartGasTracing(pluginGasTraceName(pluginName, methodName))
+
+	plugin, method, err := plugins.GetPluginMethod(pluginName, methodName)
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return nil
+	}
+
+	err = plugins.CheckCallAllowed(plugin, runtime.GetContextAddress(), runtime.GetCodeHash())
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return nil
+	}
+
+	gasSchedule := metering.GasSchedule().PluginAPICost
+	gasToUse := math.AddUint64(gasSchedule.PluginCall, method.GasCost)
+	gasToUse = math.AddUint64(gasToUse, math.MulUint64(gasSchedule.PluginDataCopyPerByte, uint64(len(args))))
+	err = metering.UseGasBounded(gasToUse)
+	if err != nil {
+		_ = vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution())
+		return nil
+	}
+
+	result, err := CallPlugin(plugin, newPluginCallContext(host), methodName, args)
+	var userErr *vmhost.PluginUserError
+	if errors.As(err, &userErr) {
+		runtime.SignalUserError(userErr.Message)
+		return nil
+	}
+	if vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution()) {
+		return nil
+	}
+
+	gasToUse = math.MulUint64(gasSchedule.PluginDataCopyPerByte, uint64(len(result)))
+	err = metering.UseGasBounded(gasToUse)
+	if err != nil {
+		_ = vmhost.WithFaultAndHost(host, err, runtime.BaseOpsErrorShouldFailExecution())
+		return nil
+	}
+
+	return result
+}
+
+// pluginGasTraceName is the name under which the gas used by a plugin method shows up in the gas trace
+func pluginGasTraceName(pluginName string, methodName string) string {
+	return mxPlugCallName + ":" + pluginName + "/" + methodName
+}
+
+// loadPluginArguments reads the length-prefixed arguments buffer of a plugin call, without the prefix
+func loadPluginArguments(runtime vmhost.RuntimeContext, argsOffset int32) ([]byte, error) {
+	argsLenBytes, err := runtime.MemLoad(argsOffset, vmhost.PluginLengthPrefixSize)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", vmhost.ErrInvalidPluginArguments, err)
+	}
+
+	argsLen := binary.LittleEndian.Uint64(argsLenBytes)
+	if argsLen > uint64(builtinMath.MaxInt32-vmhost.PluginLengthPrefixSize) {
+		return nil, vmhost.ErrInvalidPluginArguments
+	}
+
+	prefixedArgs, err := runtime.MemLoad(argsOffset, int32(argsLen)+vmhost.PluginLengthPrefixSize)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", vmhost.ErrInvalidPluginArguments, err)
+	}
+
+	return prefixedArgs[vmhost.PluginLengthPrefixSize:], nil
+}
+
+func pluginMemAlloc(runtime vmhost.RuntimeContext, len int32) (int32, error) {
 	instance := runtime.GetInstance()
 
-	alloc_result, _ := instance.CallFunctionWithArgs("mx_alloc", len)
-	alloc_offset := alloc_result.ToI32()
+	allocResult, err := instance.CallFunctionWithArgs("mx_alloc", len)
+	if err != nil {
+		return 0, err
+	}
 
-	return alloc_offset
+	return allocResult.ToI32(), nil
 }
 
-func CallPlugin(ctx *vmhost.PluginsContext, callCtx plugCallContext, pluginName string, methodName string, args []byte) unsafe.Pointer {
-	for _, plugin := range ctx.Plugins {
-		if plugin.Name == pluginName {
-			for _, method := range plugin.Methods {
-				if method.Name == methodName {
-					result := plugin.CallFn(encodeCallCtx(callCtx), methodName, args)
-					return result
-				}
-			}
-		}
+// CallPlugin calls the given plugin method, refusing results longer than vmhost.MaxPluginResultLength;
+// a successful call always yields a non-nil result
+func CallPlugin(plugin vmhost.Plugin, callCtx *vmhost.PluginCallContext, methodName string, args []byte) ([]byte, error) {
+	result, err := plugin.Call(callCtx, methodName, args)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+	if len(result) > vmhost.MaxPluginResultLength {
+		return nil, fmt.Errorf("%w: %d bytes", vmhost.ErrPluginResultTooLarge, len(result))
+	}
+	if result == nil {
+		result = make([]byte, 0)
+	}
+
+	return result, nil
 }
 
 func GetReturnDataWithHostAndTypedArgs(host vmhost.VMHost, resultID int32) []byte {
@@ -1,16 +1,29 @@
 package vmserver
 
 import (
+	"bytes"
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"math/big"
+	"strings"
+
+	worldmock "github.com/multiversx/mx-chain-vm-v1_4-go/mock/world"
+	ei "github.com/multiversx/mx-chain-vm-v1_4-go/scenarios/expression/interpreter"
+	er "github.com/multiversx/mx-chain-vm-v1_4-go/scenarios/expression/reconstructor"
+	fr "github.com/multiversx/mx-chain-vm-v1_4-go/scenarios/fileresolver"
 )
 
+const bech32AddressPrefix = "erd1"
+
 func decodeArguments(arguments []string) ([][]byte, error) {
 	result := make([][]byte, len(arguments))
 
 	for i := 0; i < len(arguments); i++ {
-		decoded, err := fromHex(arguments[i])
+		decoded, err := decodeExpression(arguments[i])
+		if err != nil && isScenarioExpression(arguments[i]) {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidArgumentEncoding, arguments[i], err)
+		}
 		if err != nil {
 			return nil, ErrInvalidArgumentEncoding
 		}
@@ -21,6 +34,93 @@ func decodeArguments(arguments []string) ([][]byte, error) {
 	return result, nil
 }
 
+// decodeExpression accepts bech32 addresses, scenario expressions (e.g. "str:abc", "u64:5", "biguint:1000",
+// "address:alice", "sc:counter") and, as before, plain hex
+func decodeExpression(encoded string) ([]byte, error) {
+	if strings.HasPrefix(encoded, bech32AddressPrefix) {
+		address, err := decodeBech32Address(encoded)
+		if err == nil {
+			return address, nil
+		}
+	}
+
+	if isScenarioExpression(encoded) {
+		exprInterpreter := ei.ExprInterpreter{FileResolver: &noFileResolver{}}
+		return exprInterpreter.InterpretString(encoded)
+	}
+
+	return fromHex(encoded)
+}
+
+// noFileResolver rejects the "file:" expressions: the requests come from clients that may run elsewhere,
+// so their paths cannot be read by the server
+type noFileResolver struct {
+}
+
+// Clone returns a new resolver
+func (resolver *noFileResolver) Clone() fr.FileResolver {
+	return &noFileResolver{}
+}
+
+// SetContext does nothing
+func (resolver *noFileResolver) SetContext(_ string) {
+}
+
+// ResolveAbsolutePath returns the path as it is
+func (resolver *noFileResolver) ResolveAbsolutePath(value string) string {
+	return value
+}
+
+// ResolveFileValue returns ErrFileExpression
+func (resolver *noFileResolver) ResolveFileValue(value string) ([]byte, error) {
+	return nil, fmt.Errorf("%w: file:%s", ErrFileExpression, value)
+}
+
+// isScenarioExpression tells the expressions apart from plain hex, which could also be read as decimal numbers
+func isScenarioExpression(encoded string) bool {
+	return strings.Contains(encoded, ":") ||
+		strings.HasPrefix(encoded, "0x") ||
+		strings.HasPrefix(encoded, "0X") ||
+		strings.HasPrefix(encoded, "''") ||
+		strings.HasPrefix(encoded, "``")
+}
+
+// prettyReturnData reconstructs the return data as scenario expressions, for reading
+func prettyReturnData(returnData [][]byte, accounts worldmock.AccountMap) []string {
+	exprReconstructor := er.ExprReconstructor{}
+	pretty := make([]string, len(returnData))
+	for i, value := range returnData {
+		hint := er.NoHint
+		if isReadableAddress(value, accounts) {
+			hint = er.AddressHint
+		}
+
+		pretty[i] = exprReconstructor.Reconstruct(value, hint)
+	}
+
+	return pretty
+}
+
+// isReadableAddress tells whether the value is an account of the world whose address is written as "address:..."
+// or "sc:...", as in the scenarios; other values of the same length are left as they are
+func isReadableAddress(value []byte, accounts worldmock.AccountMap) bool {
+	if len(value) != addressLength || accounts.GetAccount(value) == nil {
+		return false
+	}
+
+	exprReconstructor := er.ExprReconstructor{}
+	expression := exprReconstructor.Reconstruct(value, er.AddressHint)
+	for _, character := range expression {
+		if character < ' ' || character > '~' {
+			return false
+		}
+	}
+
+	exprInterpreter := ei.ExprInterpreter{}
+	interpreted, err := exprInterpreter.InterpretString(expression)
+	return err == nil && bytes.Equal(interpreted, value)
+}
+
 func parseValue(value string) (*big.Int, error) {
 	valueAsBigInt := big.NewInt(0)
 
@@ -50,3 +150,22 @@ func toHex(bytes []byte) string {
 func fromHex(encoded string) ([]byte, error) {
 	return hex.DecodeString(encoded)
 }
+
+// decodeStorageKey accepts hex or scenario expressions; plain text needs the "str:" prefix,
+// since a text key such as "cafe" would also read as hex
+func decodeStorageKey(key string) ([]byte, error) {
+	if len(key) == 0 {
+		return nil, NewRequestError("empty key")
+	}
+
+	if isScenarioExpression(key) {
+		return decodeExpression(key)
+	}
+
+	decoded, err := fromHex(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s, use str: for text keys", ErrInvalidStorageKey, key)
+	}
+
+	return decoded, nil
+}
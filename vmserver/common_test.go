@@ -1,8 +1,11 @@
 package vmserver
 
 import (
+	"bytes"
+	"errors"
 	"testing"
 
+	worldmock "github.com/multiversx/mx-chain-vm-v1_4-go/mock/world"
 	"github.com/stretchr/testify/require"
 )
 
@@ -17,4 +20,88 @@ func Test_DecodeArguments(t *testing.T) {
 
 	_, err = decodeArguments([]string{"foo"})
 	require.Equal(t, ErrInvalidArgumentEncoding, err)
+
+	decoded, err = decodeArguments([]string{"str:test", "u64:5", "biguint:1000", "0x64", ""})
+	require.Nil(t, err)
+	require.Equal(t, []byte("test"), decoded[0])
+	require.Equal(t, []byte{0, 0, 0, 0, 0, 0, 0, 5}, decoded[1])
+	require.Equal(t, []byte{0, 0, 0, 2, 0x03, 0xe8}, decoded[2])
+	require.Equal(t, []byte{100}, decoded[3])
+	require.Equal(t, []byte{}, decoded[4])
+
+	_, err = decodeArguments([]string{"u64:foo"})
+	require.ErrorIs(t, err, ErrInvalidArgumentEncoding)
+}
+
+func Test_DecodeExpression(t *testing.T) {
+	alice, err := decodeExpression("address:alice")
+	require.Nil(t, err)
+	require.Equal(t, []byte("alice___________________________"), alice)
+
+	decoded, err := decodeExpression(encodeBech32Address(alice))
+	require.Nil(t, err)
+	require.Equal(t, alice, decoded)
+
+	decoded, err = decodeExpression(toHex(alice))
+	require.Nil(t, err)
+	require.Equal(t, alice, decoded)
+
+	decoded, err = decodeExpression("sc:counter")
+	require.Nil(t, err)
+	require.Len(t, decoded, addressLength)
+
+	_, err = decodeExpression("erd1foo")
+	require.NotNil(t, err)
+
+	_, err = decodeExpression("file:../test/contracts/counter/output/counter.wasm")
+	require.True(t, errors.Is(err, ErrFileExpression))
+
+	_, err = decodeExpression("keccak256:file:../test/contracts/counter/output/counter.wasm")
+	require.True(t, errors.Is(err, ErrFileExpression))
+}
+
+func Test_PrettyReturnData(t *testing.T) {
+	contract, err := decodeExpression("sc:counter")
+	require.Nil(t, err)
+	random := bytes.Repeat([]byte{0xab}, addressLength)
+	text := []byte("the text is as long as an address")[:addressLength]
+
+	accounts := worldmock.NewAccountMap()
+	accounts.PutAccount(&worldmock.Account{Address: contract})
+	accounts.PutAccount(&worldmock.Account{Address: random})
+
+	pretty := prettyReturnData([][]byte{{0x03, 0xe8}, []byte("test"), contract, random, {}, text}, accounts)
+	require.Equal(t, "0x03e8 (1000)", pretty[0])
+	require.Equal(t, "0x74657374 (str:test)", pretty[1])
+	require.Equal(t, "sc:counter", pretty[2])
+	require.NotContains(t, pretty[3], "address:")
+	require.Equal(t, "", pretty[4])
+	require.NotContains(t, pretty[5], "address:")
+}
+
+func Test_DecodeStorageKey(t *testing.T) {
+	decoded, err := decodeStorageKey("0x74657374")
+	require.Nil(t, err)
+	require.Equal(t, []byte("test"), decoded)
+
+	decoded, err = decodeStorageKey("74657374")
+	require.Nil(t, err)
+	require.Equal(t, []byte("test"), decoded)
+
+	decoded, err = decodeStorageKey("str:74657374")
+	require.Nil(t, err)
+	require.Equal(t, []byte("74657374"), decoded)
+
+	decoded, err = decodeStorageKey("str:COUNTER|u8:1")
+	require.Nil(t, err)
+	require.Equal(t, []byte("COUNTER\x01"), decoded)
+
+	_, err = decodeStorageKey("COUNTER")
+	require.True(t, errors.Is(err, ErrInvalidStorageKey))
+
+	_, err = decodeStorageKey("0xfoo")
+	require.NotNil(t, err)
+
+	_, err = decodeStorageKey("")
+	require.NotNil(t, err)
 }
@@ -1,21 +1,30 @@
 package vmserver
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path"
+	"sort"
+
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+	worldmock "github.com/multiversx/mx-chain-vm-v1_4-go/mock/world"
 )
 
 type database struct {
 	rootPath string
+	store    WorldStore
+	cache    *worldCache
+	events   *worldEvents
 }
 
-// newDatabase creates a new debugging database (basically, a folder with JSON files)
+// newDatabase creates a new debugging database (basically, a folder with JSON files), keeping the worlds in JSON files
 func newDatabase(rootPath string) *database {
 	db := &database{rootPath: rootPath}
 	db.initFolders()
+	db.store = newJSONWorldStore(rootPath)
 	return db
 }
 
@@ -32,27 +41,227 @@ func (db *database) initFolders() {
 }
 
 func (db *database) loadWorld(worldID string) (*world, error) {
-	var err error
-	dataModel := newWorldDataModel(worldID)
+	dataModel, err := db.loadWorldDataModel(worldID)
+	if err != nil {
+		return nil, err
+	}
+
+	world, err := newWorld(dataModel)
+	if err != nil {
+		return nil, err
+	}
+
+	return world, nil
+}
+
+// loadWorldDataModel reads the stored world, or returns an empty one if the world was never stored
+func (db *database) loadWorldDataModel(worldID string) (*worldDataModel, error) {
 	filePath := db.getWorldFile(worldID)
+	if db.cache != nil {
+		dataModel, ok := db.cache.get(filePath)
+		if ok {
+			return dataModel, nil
+		}
+	}
+
+	dataModel, err := db.store.LoadWorld(worldID)
+	if err != nil {
+		return nil, err
+	}
+	if dataModel == nil {
+		return newWorldDataModel(worldID), nil
+	}
 
-	if fileExists(filePath) {
-		dataModel, err = db.readWorldDataModel(filePath)
+	return dataModel, nil
+}
+
+// storeWorldDataModel writes the world to the store, or hands it to the cache, which writes it later
+func (db *database) storeWorldDataModel(dataModel *worldDataModel) error {
+	filePath := db.getWorldFile(dataModel.ID)
+	if db.cache != nil {
+		log.Trace("Database.storeWorldDataModel(), cached", "file", filePath)
+		db.cache.put(filePath, db.store, dataModel)
+		return nil
+	}
+
+	log.Trace("Database.storeWorldDataModel()", "file", filePath)
+	return db.store.StoreWorld(dataModel)
+}
+
+func (db *database) getWorldFile(worldID string) string {
+	return worldFilePath(db.rootPath, worldID)
+}
+
+// snapshotWorld saves the current state of a world under the given snapshot name, replacing any older snapshot with the same name
+func (db *database) snapshotWorld(worldID string, snapshot string) error {
+	dataModel, err := db.loadWorldDataModel(worldID)
+	if err != nil {
+		return err
+	}
+
+	log.Trace("Database.snapshotWorld()", "world", worldID, "snapshot", snapshot)
+	return db.store.StoreSnapshot(snapshot, dataModel)
+}
+
+// revertWorld replaces the state of a world with the one saved in the given snapshot; the snapshot is kept
+func (db *database) revertWorld(worldID string, snapshot string) error {
+	dataModel, err := db.store.LoadSnapshot(worldID, snapshot)
+	if err != nil {
+		return err
+	}
+	if dataModel == nil {
+		return fmt.Errorf("%w: %s of world %s", ErrSnapshotNotFound, snapshot, worldID)
+	}
+
+	dataModel.ID = worldID
+	log.Trace("Database.revertWorld()", "world", worldID, "snapshot", snapshot)
+	return db.storeWorldDataModel(dataModel)
+}
+
+// forkWorld copies the state of a world into a new world, overwriting the new world if it already exists
+func (db *database) forkWorld(worldID string, newWorldID string) error {
+	dataModel, err := db.loadWorldDataModel(worldID)
+	if err != nil {
+		return err
+	}
+
+	// the changes of the world are not those of the new world, which is written as a whole
+	dataModel.ID = newWorldID
+	dataModel.changes = nil
+	log.Trace("Database.forkWorld()", "world", newWorldID)
+	return db.storeWorldDataModel(dataModel)
+}
+
+// getTransactionsFolder returns the folder holding the transactions sent to a world through the gateway
+func (db *database) getTransactionsFolder(worldID string) string {
+	return path.Join(db.rootPath, "worlds", fmt.Sprintf("%s.transactions", worldID))
+}
+
+func (db *database) storeTransaction(worldID string, tx *transaction.ApiTransactionResult) error {
+	err := os.MkdirAll(db.getTransactionsFolder(worldID), os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	filePath := path.Join(db.getTransactionsFolder(worldID), fmt.Sprintf("%s.json", tx.Hash))
+	log.Trace("Database.storeTransaction()", "file", filePath)
+	return db.marshalDataModel(filePath, tx)
+}
+
+func (db *database) loadTransaction(worldID string, txHash string) (*transaction.ApiTransactionResult, error) {
+	filePath := path.Join(db.getTransactionsFolder(worldID), fmt.Sprintf("%s.json", txHash))
+	if !fileExists(filePath) {
+		return nil, fmt.Errorf("%w: %s", ErrTransactionNotFound, txHash)
+	}
+
+	tx := &transaction.ApiTransactionResult{}
+	err := db.unmarshalDataModel(filePath, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+func (db *database) getTransactionLogFile(worldID string) string {
+	return path.Join(db.rootPath, "worlds", fmt.Sprintf("%s.txlog.jsonl", worldID))
+}
+
+// appendTransactionLog adds an entry to the transaction log of a world, a file with one JSON entry per line
+func (db *database) appendTransactionLog(worldID string, entry *TransactionLogEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	filePath := db.getTransactionLogFile(worldID)
+	log.Trace("Database.appendTransactionLog()", "file", filePath, "txHash", entry.TxHash)
+
+	file, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(append(data, '\n'))
+	closeErr := file.Close()
+	if err != nil {
+		return err
+	}
+	if closeErr != nil {
+		return closeErr
+	}
+
+	if db.events != nil {
+		db.events.publish(db.getWorldFile(worldID), entry)
+	}
+
+	return nil
+}
+
+// readTransactionLog returns the entries of the transaction log of a world, in execution order
+func (db *database) readTransactionLog(worldID string) ([]*TransactionLogEntry, error) {
+	entries := make([]*TransactionLogEntry, 0)
+	filePath := db.getTransactionLogFile(worldID)
+	if !fileExists(filePath) {
+		return entries, nil
+	}
+
+	data, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		entry := &TransactionLogEntry{}
+		err = json.Unmarshal(line, entry)
 		if err != nil {
 			return nil, err
 		}
+		entries = append(entries, entry)
 	}
 
-	world, err := newWorld(dataModel)
+	return entries, nil
+}
+
+func (db *database) getRecordingFile(worldID string) string {
+	return path.Join(db.rootPath, "worlds", fmt.Sprintf("%s.recording.json", worldID))
+}
+
+// startRecording saves the current state of a world and the length of its transaction log, replacing any older recording
+func (db *database) startRecording(worldID string) (*worldRecording, error) {
+	dataModel, err := db.loadWorldDataModel(worldID)
 	if err != nil {
 		return nil, err
 	}
 
-	return world, nil
+	entries, err := db.readTransactionLog(worldID)
+	if err != nil {
+		return nil, err
+	}
+
+	recording := &worldRecording{InitialState: dataModel, FirstEntry: len(entries)}
+	filePath := db.getRecordingFile(worldID)
+	log.Trace("Database.startRecording()", "file", filePath)
+	return recording, db.marshalDataModel(filePath, recording)
 }
 
-func (db *database) getWorldFile(worldID string) string {
-	return path.Join(db.rootPath, "worlds", fmt.Sprintf("%s.json", worldID))
+func (db *database) loadRecording(worldID string) (*worldRecording, error) {
+	filePath := db.getRecordingFile(worldID)
+	if !fileExists(filePath) {
+		return nil, fmt.Errorf("%w: world %s", ErrRecordingNotFound, worldID)
+	}
+
+	recording := &worldRecording{}
+	err := db.unmarshalDataModel(filePath, recording)
+	if err != nil {
+		return nil, err
+	}
+
+	return recording, nil
 }
 
 func fileExists(filePath string) bool {
@@ -60,22 +269,88 @@ func fileExists(filePath string) bool {
 	return err == nil
 }
 
-func (db *database) readWorldDataModel(filePath string) (*worldDataModel, error) {
-	dataModel := &worldDataModel{}
-	err := db.unmarshalDataModel(filePath, dataModel)
+// accountDataModel is an account as stored in the world files; storage keys are binary, so they are hex encoded
+type accountDataModel struct {
+	*worldmock.Account
+	Storage map[string][]byte
+}
+
+// worldDataModelJSON is the stored form of a world; accounts are a list, since addresses are not valid JSON keys
+type worldDataModelJSON struct {
+	worldDataModelFields
+	Accounts json.RawMessage
+}
+
+type worldDataModelFields worldDataModel
+
+// MarshalJSON stores the accounts as a list, with hex encoded storage keys
+func (dataModel *worldDataModel) MarshalJSON() ([]byte, error) {
+	accounts := make([]*accountDataModel, 0, len(dataModel.Accounts))
+	for _, account := range dataModel.Accounts {
+		storage := make(map[string][]byte, len(account.Storage))
+		for key, value := range account.Storage {
+			storage[toHex([]byte(key))] = value
+		}
+		accounts = append(accounts, &accountDataModel{Account: account, Storage: storage})
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return bytes.Compare(accounts[i].Address, accounts[j].Address) < 0
+	})
+
+	accountsJSON, err := json.Marshal(accounts)
 	if err != nil {
 		return nil, err
 	}
 
-	return dataModel, nil
+	return json.Marshal(&worldDataModelJSON{
+		worldDataModelFields: worldDataModelFields(*dataModel),
+		Accounts:             accountsJSON,
+	})
 }
 
-func (db *database) storeWorld(world *world) error {
-	filePath := db.getWorldFile(world.id)
-	log.Trace("Database.storeWorld()", "file", filePath)
+// UnmarshalJSON reads the accounts as stored by MarshalJSON, or as the map of older world files
+func (dataModel *worldDataModel) UnmarshalJSON(data []byte) error {
+	stored := &worldDataModelJSON{worldDataModelFields: worldDataModelFields(*dataModel)}
+	err := json.Unmarshal(data, stored)
+	if err != nil {
+		return err
+	}
+
+	*dataModel = worldDataModel(stored.worldDataModelFields)
+	dataModel.Accounts = worldmock.NewAccountMap()
+
+	accountsJSON := bytes.TrimSpace(stored.Accounts)
+	if len(accountsJSON) == 0 || bytes.Equal(accountsJSON, []byte("null")) {
+		return nil
+	}
+	if accountsJSON[0] == '{' {
+		return json.Unmarshal(accountsJSON, &dataModel.Accounts)
+	}
+
+	var accounts []*accountDataModel
+	err = json.Unmarshal(accountsJSON, &accounts)
+	if err != nil {
+		return err
+	}
+
+	for _, account := range accounts {
+		account.Account.Storage = make(map[string][]byte, len(account.Storage))
+		for key, value := range account.Storage {
+			decodedKey, err := fromHex(key)
+			if err != nil {
+				return err
+			}
+			account.Account.Storage[string(decodedKey)] = value
+		}
+		dataModel.Accounts.PutAccount(account.Account)
+	}
 
-	dataModel := world.toDataModel()
-	return db.marshalDataModel(filePath, dataModel)
+	return nil
+}
+
+func (db *database) storeWorld(world *world) error {
+	log.Trace("Database.storeWorld()", "world", world.id)
+	return db.storeWorldDataModel(world.toDataModel())
 }
 
 func (db *database) storeOutcome(key string, outcome interface{}) error {
@@ -94,6 +369,10 @@ func (db *database) getOutcomeFile(uniqueID string) string {
 }
 
 func (db *database) unmarshalDataModel(filePath string, dataModel interface{}) error {
+	return unmarshalFromFile(filePath, dataModel)
+}
+
+func unmarshalFromFile(filePath string, dataModel interface{}) error {
 	data, err := ioutil.ReadFile(filePath)
 	if err != nil {
 		return err
@@ -103,10 +382,48 @@ func (db *database) unmarshalDataModel(filePath string, dataModel interface{}) e
 }
 
 func (db *database) marshalDataModel(filePath string, dataModel interface{}) error {
+	return marshalToFile(filePath, dataModel)
+}
+
+func marshalToFile(filePath string, dataModel interface{}) error {
 	data, err := json.MarshalIndent(dataModel, "", "\t")
 	if err != nil {
 		return err
 	}
 
-	return ioutil.WriteFile(filePath, data, 0644)
+	return writeFileAtomically(filePath, data)
+}
+
+// writeFileAtomically writes to a temporary file next to the target, then renames it over the target,
+// so that readers never see a partially written file
+func writeFileAtomically(filePath string, data []byte) error {
+	tempFile, err := ioutil.TempFile(path.Dir(filePath), path.Base(filePath)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	tempFilePath := tempFile.Name()
+	defer func() {
+		// no-op once renamed
+		_ = os.Remove(tempFilePath)
+	}()
+
+	_, err = tempFile.Write(data)
+	if err == nil {
+		err = tempFile.Sync()
+	}
+	closeErr := tempFile.Close()
+	if err != nil {
+		return err
+	}
+	if closeErr != nil {
+		return closeErr
+	}
+
+	err = os.Chmod(tempFilePath, 0644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tempFilePath, filePath)
 }
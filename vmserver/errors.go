@@ -36,3 +36,39 @@ func (err *RequestError) Unwrap() error {
 
 // ErrInvalidArgumentEncoding signals an error
 var ErrInvalidArgumentEncoding = errors.New("invalid contract argument encoding")
+
+// ErrInvalidStorageKey signals an error
+var ErrInvalidStorageKey = errors.New("storage key is neither hex nor a scenario expression")
+
+// ErrFileExpression signals an error
+var ErrFileExpression = errors.New("file expressions are not supported by the server")
+
+// ErrSnapshotNotFound signals an error
+var ErrSnapshotNotFound = errors.New("world snapshot not found")
+
+// ErrAccountNotFound signals an error
+var ErrAccountNotFound = errors.New("account not found")
+
+// ErrTokenNotFound signals an error
+var ErrTokenNotFound = errors.New("token not found")
+
+// ErrUnknownGasSchedule signals an error
+var ErrUnknownGasSchedule = errors.New("unknown gas schedule")
+
+// ErrUnknownEnabledFlag signals an error
+var ErrUnknownEnabledFlag = errors.New("unknown enabled flag")
+
+// ErrInvalidTransactionNonce signals an error
+var ErrInvalidTransactionNonce = errors.New("invalid transaction nonce")
+
+// ErrTransactionNotFound signals an error
+var ErrTransactionNotFound = errors.New("transaction not found")
+
+// ErrRecordingNotFound signals an error
+var ErrRecordingNotFound = errors.New("no recording was started")
+
+// ErrRecordingNotExportable signals an error
+var ErrRecordingNotExportable = errors.New("the recording cannot be exported as a scenario")
+
+// ErrUnknownWorldStore signals an error
+var ErrUnknownWorldStore = errors.New("unknown world store")
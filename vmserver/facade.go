@@ -4,123 +4,604 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"sync"
+	"time"
 
 	logger "github.com/multiversx/mx-chain-logger-go"
+	mjwrite "github.com/multiversx/mx-chain-vm-v1_4-go/scenarios/json/write"
+	"github.com/multiversx/mx-chain-vm-v1_4-go/vmhost"
 )
 
 var log = logger.GetOrCreate("vmserver")
 
 // DebugFacade is the debug facade
 type DebugFacade struct {
+	locks       *worldLocks
+	cache       *worldCache
+	events      *worldEvents
+	worldStore  string
+	storesMutex sync.Mutex
+	stores      map[string]WorldStore
 }
 
-// NewDebugFacade creates a new debug facade
+// ArgsNewDebugFacade holds the arguments of NewDebugFacadeWithArgs
+type ArgsNewDebugFacade struct {
+	// CacheWorlds keeps the worlds in memory between requests, instead of reading them from their files each time
+	CacheWorlds bool
+	// FlushInterval is how often the cached worlds are written to their files; zero writes them only on Close
+	FlushInterval time.Duration
+	// WorldStore is the backend of the worlds, WorldStoreJSON (the default) or WorldStoreBolt
+	WorldStore string
+}
+
+// NewDebugFacade creates a new debug facade, which reads and writes the world files on each request
 func NewDebugFacade() *DebugFacade {
-	return &DebugFacade{}
+	return NewDebugFacadeWithArgs(ArgsNewDebugFacade{})
+}
+
+// NewDebugFacadeWithArgs creates a new debug facade, optionally caching the worlds in memory
+func NewDebugFacadeWithArgs(args ArgsNewDebugFacade) *DebugFacade {
+	facade := &DebugFacade{
+		locks:      newWorldLocks(),
+		events:     newWorldEvents(),
+		worldStore: args.WorldStore,
+		stores:     make(map[string]WorldStore),
+	}
+
+	if args.CacheWorlds {
+		facade.cache = newWorldCache(args.FlushInterval)
+	}
+
+	return facade
+}
+
+// Close ends the event subscriptions, writes the cached worlds to their stores, stops the background writing
+// and closes the stores
+func (f *DebugFacade) Close() error {
+	f.events.close()
+
+	var err error
+	if f.cache != nil {
+		err = f.cache.close()
+	}
+
+	f.storesMutex.Lock()
+	defer f.storesMutex.Unlock()
+
+	for key, store := range f.stores {
+		closeErr := store.Close()
+		if closeErr != nil {
+			err = closeErr
+		}
+		delete(f.stores, key)
+	}
+
+	return err
 }
 
 // DeploySmartContract deploys a smart contract
 func (f *DebugFacade) DeploySmartContract(request DeployRequest) (*DeployResponse, error) {
 	log.Debug("Debugf.DeploySmartContract()")
 
+	var response *DeployResponse
+	err := f.withWorld(&request, func(database *database, world *world) error {
+		response = world.deploySmartContract(request)
+
+		err := database.storeWorld(world)
+		if err != nil {
+			return err
+		}
+
+		executed := &executedRequest{
+			kind:         TransactionKindDeploy,
+			contract:     response.ContractAddress,
+			function:     vmhost.InitFunctionName,
+			gasLimit:     request.GasLimit,
+			code:         request.Code,
+			codeMetadata: request.CodeMetadataBytes,
+			codePath:     request.CodePath,
+		}
+		f.logContractRequest(database, world, executed, &response.ContractResponseBase)
+
+		return database.storeOutcome(request.Outcome, response)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dumpOutcome(&response)
+	return response, nil
+}
+
+func (f *DebugFacade) loadDatabase(rootPath string) (*database, error) {
+	database := newDatabase(rootPath)
+	database.cache = f.cache
+	database.events = f.events
+
+	switch f.worldStore {
+	case "", WorldStoreJSON:
+	case WorldStoreBolt:
+		store, err := f.getBoltStore(rootPath)
+		if err != nil {
+			return nil, err
+		}
+		database.store = store
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownWorldStore, f.worldStore)
+	}
+
+	return database, nil
+}
+
+// getBoltStore opens the key-value store of a database once, and keeps it open until Close,
+// since the file cannot be opened twice
+func (f *DebugFacade) getBoltStore(rootPath string) (WorldStore, error) {
+	f.storesMutex.Lock()
+	defer f.storesMutex.Unlock()
+
+	key := worldKey(rootPath)
+	store, ok := f.stores[key]
+	if ok {
+		return store, nil
+	}
+
+	store, err := newBoltWorldStore(rootPath)
+	if err != nil {
+		return nil, err
+	}
+
+	f.stores[key] = store
+	return store, nil
+}
+
+// logContractRequest adds an executed contract request to the transaction log of the world, and returns its hash in the response;
+// the request is already committed to the world, so failing to log it does not fail the request
+func (f *DebugFacade) logContractRequest(database *database, world *world, request *executedRequest, response *ContractResponseBase) {
+	request.input = response.Input
+	request.output = response.Output
+	request.err = response.Error
+
+	entry := world.newTransactionLogEntry(request)
+	response.TxHash = entry.TxHash
+	logTransaction(database, world.id, entry)
+}
+
+// logTransaction appends an entry to the transaction log of a world, warning if it cannot
+func logTransaction(database *database, worldID string, entry *TransactionLogEntry) {
+	err := database.appendTransactionLog(worldID, entry)
+	if err != nil {
+		log.Warn("cannot append to the transaction log", "world", worldID, "txHash", entry.TxHash, "err", err)
+	}
+}
+
+// lockWorlds holds the given worlds of the database until the returned function is called
+func (f *DebugFacade) lockWorlds(database *database, worldIDs ...string) func() {
+	filePaths := make([]string, 0, len(worldIDs))
+	for _, worldID := range worldIDs {
+		filePaths = append(filePaths, database.getWorldFile(worldID))
+	}
+
+	return f.locks.lock(filePaths...)
+}
+
+// withWorld digests the request and runs the action on the requested world, holding the world, and the VM host
+// of the process, until the action ends
+func (f *DebugFacade) withWorld(request worldRequest, action func(database *database, world *world) error) error {
+	return f.withDatabase(request, func(database *database, worldID string) error {
+		vmHostMutex.Lock()
+		defer vmHostMutex.Unlock()
+
+		world, err := database.loadWorld(worldID)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			vmAsClose := world.vm.(io.Closer)
+			_ = vmAsClose.Close()
+		}()
+
+		return action(database, world)
+	})
+}
+
+// withWorldDataModel is withWorld for the actions that only read the world, without building its VM host
+func (f *DebugFacade) withWorldDataModel(request worldRequest, action func(database *database, dataModel *worldDataModel) error) error {
+	return f.withDatabase(request, func(database *database, worldID string) error {
+		dataModel, err := database.loadWorldDataModel(worldID)
+		if err != nil {
+			return err
+		}
+
+		return action(database, dataModel)
+	})
+}
+
+func (f *DebugFacade) withDatabase(request worldRequest, action func(database *database, worldID string) error) error {
 	err := request.digest()
+	if err != nil {
+		return err
+	}
+
+	base := request.getRequestBase()
+	database, err := f.loadDatabase(base.DatabasePath)
+	if err != nil {
+		return err
+	}
+	unlock := f.lockWorlds(database, base.World)
+	defer unlock()
+
+	return action(database, base.World)
+}
+
+// UpgradeSmartContract upgrades a smart contract
+func (f *DebugFacade) UpgradeSmartContract(request UpgradeRequest) (*UpgradeResponse, error) {
+	log.Debug("Debugf.UpgradeSmartContract()")
+
+	var response *UpgradeResponse
+	err := f.withWorld(&request, func(database *database, world *world) error {
+		response = world.upgradeSmartContract(request)
+
+		err := database.storeWorld(world)
+		if err != nil {
+			return err
+		}
+
+		executed := &executedRequest{
+			kind:     TransactionKindUpgrade,
+			contract: request.ContractAddress,
+			function: vmhost.UpgradeFunctionName,
+			gasLimit: request.GasLimit,
+			codePath: request.CodePath,
+		}
+		f.logContractRequest(database, world, executed, &response.ContractResponseBase)
+
+		return database.storeOutcome(request.Outcome, response)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	database := f.loadDatabase(request.DatabasePath)
-	world, err := database.loadWorld(request.World)
+	dumpOutcome(&response)
+	return response, nil
+}
+
+// RunSmartContract executes a smart contract function
+func (f *DebugFacade) RunSmartContract(request RunRequest) (*RunResponse, error) {
+	log.Debug("Debugf.RunSmartContract()")
+
+	var response *RunResponse
+	err := f.withWorld(&request, func(database *database, world *world) error {
+		response = world.runSmartContract(request)
+
+		err := database.storeWorld(world)
+		if err != nil {
+			return err
+		}
+
+		executed := &executedRequest{
+			kind:     TransactionKindRun,
+			contract: request.ContractAddress,
+			function: request.Function,
+			gasLimit: request.GasLimit,
+		}
+		f.logContractRequest(database, world, executed, &response.ContractResponseBase)
+
+		return database.storeOutcome(request.Outcome, response)
+	})
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		vmAsClose := world.vm.(io.Closer)
-		_ = vmAsClose.Close()
-	}()
 
-	response := world.deploySmartContract(request)
+	dumpOutcome(&response)
+	return response, nil
+}
+
+// QuerySmartContract queries a pure function of the smart contract
+func (f *DebugFacade) QuerySmartContract(request QueryRequest) (*QueryResponse, error) {
+	log.Debug("Debugf.QuerySmartContracts()")
+
+	var response *QueryResponse
+	err := f.withWorld(&request, func(database *database, world *world) error {
+		response = world.querySmartContract(request)
 
-	err = database.storeWorld(world)
+		executed := &executedRequest{
+			kind:     TransactionKindQuery,
+			contract: request.ContractAddress,
+			function: request.Function,
+			gasLimit: request.GasLimit,
+		}
+		f.logContractRequest(database, world, executed, &response.ContractResponseBase)
+
+		return database.storeOutcome(request.Outcome, response)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	err = database.storeOutcome(request.Outcome, response)
+	dumpOutcome(&response)
+	return response, nil
+}
+
+// CreateAccount creates a test account
+func (f *DebugFacade) CreateAccount(request CreateAccountRequest) (*CreateAccountResponse, error) {
+	log.Debug("Debugf.CreateAccount()")
+
+	var response *CreateAccountResponse
+	err := f.withWorld(&request, func(database *database, world *world) error {
+		response = world.createAccount(request)
+
+		err := database.storeWorld(world)
+		if err != nil {
+			return err
+		}
+		if response.Account != nil {
+			logTransaction(database, world.id, world.newAccountLogEntry(response.Account))
+		}
+
+		return database.storeOutcome(request.Outcome, response)
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	dumpOutcome(&response)
-	return response, err
+	return response, nil
 }
 
-func (f *DebugFacade) loadDatabase(rootPath string) *database {
-	database := newDatabase(rootPath)
-	return database
+// SetBlock changes the block infos of a world, or advances it a number of blocks
+func (f *DebugFacade) SetBlock(request BlockRequest) (*BlockResponse, error) {
+	log.Debug("Debugf.SetBlock()")
+
+	var response *BlockResponse
+	err := f.withWorld(&request, func(database *database, world *world) error {
+		response = world.setBlock(request)
+
+		err := database.storeWorld(world)
+		if err != nil {
+			return err
+		}
+
+		return database.storeOutcome(request.Outcome, response)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dumpOutcome(&response)
+	return response, nil
 }
 
-// UpgradeSmartContract upgrades a smart contract
-func (f *DebugFacade) UpgradeSmartContract(request UpgradeRequest) (*UpgradeResponse, error) {
-	log.Debug("Debugf.UpgradeSmartContract()")
+// SetWorldConfig changes the gas schedule and the enabled flags of a world, used from the next load of the world on
+func (f *DebugFacade) SetWorldConfig(request WorldConfigRequest) (*WorldConfigResponse, error) {
+	log.Debug("Debugf.SetWorldConfig()")
+
+	var response *WorldConfigResponse
+	err := f.withWorld(&request, func(database *database, world *world) error {
+		var err error
+		response, err = world.setConfig(request)
+		if err != nil {
+			return err
+		}
+
+		err = database.storeWorld(world)
+		if err != nil {
+			return err
+		}
+		logTransaction(database, world.id, world.newWorldConfigLogEntry(response))
+
+		return database.storeOutcome(request.Outcome, response)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dumpOutcome(&response)
+	return response, nil
+}
+
+// SnapshotWorld saves the current state of a world under a snapshot name
+func (f *DebugFacade) SnapshotWorld(request SnapshotWorldRequest) (*SnapshotWorldResponse, error) {
+	log.Debug("Debugf.SnapshotWorld()")
 
 	err := request.digest()
 	if err != nil {
 		return nil, err
 	}
 
-	database := f.loadDatabase(request.DatabasePath)
-	world, err := database.loadWorld(request.World)
+	database, err := f.loadDatabase(request.DatabasePath)
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		vmAsClose := world.vm.(io.Closer)
-		_ = vmAsClose.Close()
-	}()
-
-	response := world.upgradeSmartContract(request)
+	unlock := f.lockWorlds(database, request.World)
+	defer unlock()
 
-	err = database.storeWorld(world)
+	err = database.snapshotWorld(request.World, request.Snapshot)
 	if err != nil {
 		return nil, err
 	}
 
+	response := &SnapshotWorldResponse{World: request.World, Snapshot: request.Snapshot}
 	err = database.storeOutcome(request.Outcome, response)
 	if err != nil {
 		return nil, err
 	}
 
 	dumpOutcome(&response)
+	return response, err
+}
+
+// ForkWorld copies the state of a world into a new world
+func (f *DebugFacade) ForkWorld(request ForkWorldRequest) (*ForkWorldResponse, error) {
+	log.Debug("Debugf.ForkWorld()")
+
+	err := request.digest()
+	if err != nil {
+		return nil, err
+	}
 
+	database, err := f.loadDatabase(request.DatabasePath)
+	if err != nil {
+		return nil, err
+	}
+	unlock := f.lockWorlds(database, request.World, request.NewWorld)
+	defer unlock()
+
+	err = database.forkWorld(request.World, request.NewWorld)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ForkWorldResponse{World: request.World, NewWorld: request.NewWorld}
+	err = database.storeOutcome(request.Outcome, response)
+	if err != nil {
+		return nil, err
+	}
+
+	dumpOutcome(&response)
 	return response, err
 }
 
-// RunSmartContract executes a smart contract function
-func (f *DebugFacade) RunSmartContract(request RunRequest) (*RunResponse, error) {
-	log.Debug("Debugf.RunSmartContract()")
+// RevertWorld brings a world back to the state saved in a snapshot
+func (f *DebugFacade) RevertWorld(request RevertWorldRequest) (*RevertWorldResponse, error) {
+	log.Debug("Debugf.RevertWorld()")
 
 	err := request.digest()
 	if err != nil {
 		return nil, err
 	}
 
-	database := f.loadDatabase(request.DatabasePath)
-	world, err := database.loadWorld(request.World)
+	database, err := f.loadDatabase(request.DatabasePath)
+	if err != nil {
+		return nil, err
+	}
+	unlock := f.lockWorlds(database, request.World)
+	defer unlock()
+
+	err = database.revertWorld(request.World, request.Snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &RevertWorldResponse{World: request.World, Snapshot: request.Snapshot}
+	err = database.storeOutcome(request.Outcome, response)
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		vmAsClose := world.vm.(io.Closer)
-		_ = vmAsClose.Close()
-	}()
 
-	response := world.runSmartContract(request)
+	dumpOutcome(&response)
+	return response, err
+}
+
+// GetAccounts returns all the accounts of a world
+func (f *DebugFacade) GetAccounts(request AccountsRequest) (*AccountsResponse, error) {
+	log.Debug("Debugf.GetAccounts()")
+
+	var response *AccountsResponse
+	err := f.withWorldDataModel(&request, func(database *database, dataModel *worldDataModel) error {
+		var err error
+		response, err = dataModel.getAccounts()
+		if err != nil {
+			return err
+		}
 
-	err = database.storeWorld(world)
+		return database.storeOutcome(request.Outcome, response)
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	dumpOutcome(&response)
+	return response, nil
+}
+
+// GetAccount returns an account of a world
+func (f *DebugFacade) GetAccount(request AccountRequest) (*AccountResponse, error) {
+	log.Debug("Debugf.GetAccount()")
+
+	var response *AccountResponse
+	err := f.withWorldDataModel(&request, func(database *database, dataModel *worldDataModel) error {
+		var err error
+		response, err = dataModel.getAccount(request)
+		if err != nil {
+			return err
+		}
+
+		return database.storeOutcome(request.Outcome, response)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dumpOutcome(&response)
+	return response, nil
+}
+
+// GetStorage returns a storage value of an account
+func (f *DebugFacade) GetStorage(request StorageRequest) (*StorageResponse, error) {
+	log.Debug("Debugf.GetStorage()")
+
+	var response *StorageResponse
+	err := f.withWorldDataModel(&request, func(database *database, dataModel *worldDataModel) error {
+		var err error
+		response, err = dataModel.getStorage(request)
+		if err != nil {
+			return err
+		}
+
+		return database.storeOutcome(request.Outcome, response)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dumpOutcome(&response)
+	return response, nil
+}
+
+// GetESDT returns an ESDT token held by an account
+func (f *DebugFacade) GetESDT(request ESDTRequest) (*ESDTResponse, error) {
+	log.Debug("Debugf.GetESDT()")
+
+	var response *ESDTResponse
+	err := f.withWorldDataModel(&request, func(database *database, dataModel *worldDataModel) error {
+		var err error
+		response, err = dataModel.getESDT(request)
+		if err != nil {
+			return err
+		}
+
+		return database.storeOutcome(request.Outcome, response)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dumpOutcome(&response)
+	return response, nil
+}
+
+// GetTransactionLog returns the executed requests of a world, optionally filtered by contract, function or event
+func (f *DebugFacade) GetTransactionLog(request TransactionLogRequest) (*TransactionLogResponse, error) {
+	log.Debug("Debugf.GetTransactionLog()")
+
+	err := request.digest()
+	if err != nil {
+		return nil, err
+	}
+
+	database, err := f.loadDatabase(request.DatabasePath)
+	if err != nil {
+		return nil, err
+	}
+	unlock := f.lockWorlds(database, request.World)
+	defer unlock()
+
+	entries, err := database.readTransactionLog(request.World)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &TransactionLogResponse{Entries: filterTransactionLog(entries, request)}
 	err = database.storeOutcome(request.Outcome, response)
 	if err != nil {
 		return nil, err
@@ -130,26 +611,37 @@ func (f *DebugFacade) RunSmartContract(request RunRequest) (*RunResponse, error)
 	return response, err
 }
 
-// QuerySmartContract queries a pure function of the smart contract
-func (f *DebugFacade) QuerySmartContract(request QueryRequest) (*QueryResponse, error) {
-	log.Debug("Debugf.QuerySmartContracts()")
+// GetTransactionLogEntry returns an executed request of a world, by its hash
+func (f *DebugFacade) GetTransactionLogEntry(request TransactionLogEntryRequest) (*TransactionLogEntryResponse, error) {
+	log.Debug("Debugf.GetTransactionLogEntry()")
 
 	err := request.digest()
 	if err != nil {
 		return nil, err
 	}
 
-	database := f.loadDatabase(request.DatabasePath)
-	world, err := database.loadWorld(request.World)
+	database, err := f.loadDatabase(request.DatabasePath)
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		vmAsClose := world.vm.(io.Closer)
-		_ = vmAsClose.Close()
-	}()
+	unlock := f.lockWorlds(database, request.World)
+	defer unlock()
 
-	response := world.querySmartContract(request)
+	entries, err := database.readTransactionLog(request.World)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &TransactionLogEntryResponse{}
+	for _, entry := range entries {
+		if entry.TxHash == request.TxHash {
+			response.Entry = entry
+			break
+		}
+	}
+	if response.Entry == nil {
+		return nil, fmt.Errorf("%w: %s", ErrTransactionNotFound, request.TxHash)
+	}
 
 	err = database.storeOutcome(request.Outcome, response)
 	if err != nil {
@@ -160,39 +652,100 @@ func (f *DebugFacade) QuerySmartContract(request QueryRequest) (*QueryResponse,
 	return response, err
 }
 
-// CreateAccount creates a test account
-func (f *DebugFacade) CreateAccount(request CreateAccountRequest) (*CreateAccountResponse, error) {
-	log.Debug("Debugf.CreateAccount()")
+// SubscribeEvents follows the requests executed on a world from now on, with the filters of the transaction log;
+// the caller must close the subscription
+func (f *DebugFacade) SubscribeEvents(request TransactionLogRequest) (*EventSubscription, error) {
+	log.Debug("Debugf.SubscribeEvents()")
 
 	err := request.digest()
 	if err != nil {
 		return nil, err
 	}
 
-	database := f.loadDatabase(request.DatabasePath)
-	world, err := database.loadWorld(request.World)
+	database, err := f.loadDatabase(request.DatabasePath)
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		vmAsClose := world.vm.(io.Closer)
-		_ = vmAsClose.Close()
-	}()
+	return f.events.subscribe(database.getWorldFile(request.World), request), nil
+}
 
-	response := world.createAccount(request)
+// StartRecording starts recording the requests executed on a world, from its current state
+func (f *DebugFacade) StartRecording(request RecordingRequest) (*RecordingResponse, error) {
+	log.Debug("Debugf.StartRecording()")
+
+	err := request.digest()
+	if err != nil {
+		return nil, err
+	}
 
-	err = database.storeWorld(world)
+	database, err := f.loadDatabase(request.DatabasePath)
 	if err != nil {
 		return nil, err
 	}
+	unlock := f.lockWorlds(database, request.World)
+	defer unlock()
 
+	recording, err := database.startRecording(request.World)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &RecordingResponse{World: request.World, FirstEntry: recording.FirstEntry}
 	err = database.storeOutcome(request.Outcome, response)
 	if err != nil {
 		return nil, err
 	}
 
 	dumpOutcome(&response)
+	return response, err
+}
+
+// ExportScenario converts the recording of a world to a scenario
+func (f *DebugFacade) ExportScenario(request ScenarioRequest) (*ScenarioResponse, error) {
+	log.Debug("Debugf.ExportScenario()")
+
+	err := request.digest()
+	if err != nil {
+		return nil, err
+	}
+
+	database, err := f.loadDatabase(request.DatabasePath)
+	if err != nil {
+		return nil, err
+	}
+	unlock := f.lockWorlds(database, request.World)
+	defer unlock()
 
+	recording, err := database.loadRecording(request.World)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := database.readTransactionLog(request.World)
+	if err != nil {
+		return nil, err
+	}
+	if recording.FirstEntry > len(entries) {
+		return nil, fmt.Errorf("%w: the transaction log of world %s was truncated", ErrRecordingNotFound, request.World)
+	}
+
+	finalState, err := database.loadWorldDataModel(request.World)
+	if err != nil {
+		return nil, err
+	}
+
+	scenario, err := newRecordedScenario(request, recording, entries[recording.FirstEntry:], finalState)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ScenarioResponse{Scenario: ojToRawJSON(mjwrite.ScenarioToOrderedJSON(scenario))}
+	err = database.storeOutcome(request.Outcome, response)
+	if err != nil {
+		return nil, err
+	}
+
+	dumpOutcome(&response)
 	return response, err
 }
 
@@ -0,0 +1,106 @@
+package vmserver
+
+// GatewayGetAccount returns an account of a world, in the format of the gateway
+func (f *DebugFacade) GatewayGetAccount(request GatewayAccountRequest) (*GatewayAccountResponse, error) {
+	log.Debug("Debugf.GatewayGetAccount()")
+
+	var response *GatewayAccountResponse
+	err := f.withWorld(&request, func(database *database, world *world) error {
+		response = &GatewayAccountResponse{Account: world.getGatewayAccount(request.Address)}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// GatewayGetStorage returns a storage value of an account, in the format of the gateway
+func (f *DebugFacade) GatewayGetStorage(request GatewayStorageRequest) (*GatewayStorageResponse, error) {
+	log.Debug("Debugf.GatewayGetStorage()")
+
+	var response *GatewayStorageResponse
+	err := f.withWorld(&request, func(database *database, world *world) error {
+		response = world.getGatewayStorage(request)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// GatewaySendTransaction executes a transaction sent through the gateway and keeps its result for GatewayGetTransaction
+func (f *DebugFacade) GatewaySendTransaction(request GatewaySendTransactionRequest) (*GatewaySendTransactionResponse, error) {
+	log.Debug("Debugf.GatewaySendTransaction()")
+
+	var txHash string
+	err := f.withWorld(&request, func(database *database, world *world) error {
+		var err error
+		txHash, err = computeTransactionHash(request.parsed)
+		if err != nil {
+			return err
+		}
+
+		tx, entry, err := world.executeTransaction(request.parsed, txHash)
+		if err != nil {
+			return err
+		}
+
+		err = database.storeWorld(world)
+		if err != nil {
+			return err
+		}
+
+		logTransaction(database, request.World, entry)
+
+		return database.storeTransaction(request.World, tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &GatewaySendTransactionResponse{TxHash: txHash}, nil
+}
+
+// GatewayGetTransaction returns a transaction sent through the gateway, with its results and logs
+func (f *DebugFacade) GatewayGetTransaction(request GatewayTransactionRequest) (*GatewayTransactionResponse, error) {
+	log.Debug("Debugf.GatewayGetTransaction()")
+
+	err := request.digest()
+	if err != nil {
+		return nil, err
+	}
+
+	database, err := f.loadDatabase(request.DatabasePath)
+	if err != nil {
+		return nil, err
+	}
+	unlock := f.lockWorlds(database, request.World)
+	defer unlock()
+
+	tx, err := database.loadTransaction(request.World, request.TxHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GatewayTransactionResponse{Transaction: tx}, nil
+}
+
+// GatewayQuery runs a query, in the format of the gateway
+func (f *DebugFacade) GatewayQuery(request GatewayQueryRequest) (*GatewayQueryResponse, error) {
+	log.Debug("Debugf.GatewayQuery()")
+
+	var response *GatewayQueryResponse
+	err := f.withWorld(&request, func(database *database, world *world) error {
+		response = world.gatewayQuery(request)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
@@ -0,0 +1,326 @@
+package vmserver
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+	"github.com/multiversx/mx-chain-core-go/hashing/keccak"
+	"github.com/multiversx/mx-chain-core-go/marshal"
+	vmcommon "github.com/multiversx/mx-chain-vm-common-go"
+	"github.com/stretchr/testify/require"
+)
+
+func (context *testContext) sendTransaction(tx transaction.FrontendTransaction) (*transaction.ApiTransactionResult, error) {
+	response, err := context.facade.GatewaySendTransaction(GatewaySendTransactionRequest{
+		RequestBase: context.createRequestBase(),
+		Transaction: tx,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	txResponse, err := context.facade.GatewayGetTransaction(GatewayTransactionRequest{
+		RequestBase: context.createRequestBase(),
+		TxHash:      response.TxHash,
+	})
+	require.Nil(context.t, err)
+	require.Equal(context.t, response.TxHash, txResponse.Transaction.Hash)
+
+	return txResponse.Transaction, nil
+}
+
+func (context *testContext) getGatewayAccount(address []byte) *GatewayAccount {
+	response, err := context.facade.GatewayGetAccount(GatewayAccountRequest{
+		RequestBase:   context.createRequestBase(),
+		Bech32Address: encodeBech32Address(address),
+	})
+	require.Nil(context.t, err)
+
+	return response.Account
+}
+
+func newFrontendTransaction(sender []byte, receiver []byte, nonce uint64, value string, data string) transaction.FrontendTransaction {
+	return transaction.FrontendTransaction{
+		Nonce:    nonce,
+		Value:    value,
+		Receiver: encodeBech32Address(receiver),
+		Sender:   encodeBech32Address(sender),
+		GasPrice: 1000000000,
+		GasLimit: gasLimit,
+		Data:     []byte(data),
+		ChainID:  DefaultGatewayChainID,
+		Version:  1,
+	}
+}
+
+func TestFacade_Gateway_Transfer(t *testing.T) {
+	context := newTestContext(t)
+
+	alice := newDummyAddress("alice")
+	bob := newDummyAddress("bob")
+	context.createAccount(alice.hex, "100")
+
+	tx, err := context.sendTransaction(newFrontendTransaction(alice.raw, bob.raw, 0, "30", "a note"))
+	require.Nil(t, err)
+	require.Equal(t, transaction.TxStatusSuccess, tx.Status)
+	require.Equal(t, "30", tx.Value)
+	require.Equal(t, []byte("a note"), tx.Data)
+
+	aliceAccount := context.getGatewayAccount(alice.raw)
+	require.Equal(t, "70", aliceAccount.Balance)
+	require.Equal(t, uint64(1), aliceAccount.Nonce)
+	require.Equal(t, "30", context.getGatewayAccount(bob.raw).Balance)
+
+	_, err = context.sendTransaction(newFrontendTransaction(alice.raw, bob.raw, 0, "30", ""))
+	require.ErrorIs(t, err, ErrInvalidTransactionNonce)
+
+	tx, err = context.sendTransaction(newFrontendTransaction(alice.raw, bob.raw, 1, "1000", ""))
+	require.Nil(t, err)
+	require.Equal(t, transaction.TxStatusFail, tx.Status)
+	require.Equal(t, errorEventIdentifier, tx.Logs.Events[0].Identifier)
+
+	aliceAccount = context.getGatewayAccount(alice.raw)
+	require.Equal(t, "70", aliceAccount.Balance)
+	require.Equal(t, uint64(2), aliceAccount.Nonce)
+
+	_, err = context.sendTransaction(newFrontendTransaction(bob.raw, alice.raw, 1, "1", ""))
+	require.ErrorIs(t, err, ErrInvalidTransactionNonce)
+}
+
+func TestFacade_Gateway_ESDTTransfer(t *testing.T) {
+	context := newTestContext(t)
+
+	alice := newDummyAddress("alice")
+	bob := newDummyAddress("bob")
+	context.createAccountWithESDT(alice.hex, "100", &AccountESDT{TokenIdentifier: "TOKEN-abcdef", Value: "50"})
+
+	data := core.BuiltInFunctionESDTTransfer + "@" + hex.EncodeToString([]byte("TOKEN-abcdef")) + "@14"
+	tx, err := context.sendTransaction(newFrontendTransaction(alice.raw, bob.raw, 0, "0", data))
+	require.Nil(t, err)
+	require.Equal(t, transaction.TxStatusSuccess, tx.Status)
+
+	require.Equal(t, int64(30), context.getTokenBalance(alice.raw, "TOKEN-abcdef", 0))
+	require.Equal(t, int64(20), context.getTokenBalance(bob.raw, "TOKEN-abcdef", 0))
+}
+
+func TestFacade_Gateway_Signature(t *testing.T) {
+	context := newTestContext(t)
+
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	require.Nil(t, err)
+	sender := []byte(publicKey)
+	context.createAccount(toHex(sender), "100")
+
+	tx := newFrontendTransaction(sender, newDummyAddress("bob").raw, 0, "1", "")
+	parsedTx, err := fromFrontendTransaction(&tx)
+	require.Nil(t, err)
+	dataForSigning, err := parsedTx.GetDataForSigning(addressConverter, &marshal.TxJsonMarshalizer{}, keccak.NewKeccak())
+	require.Nil(t, err)
+	tx.Signature = hex.EncodeToString(ed25519.Sign(privateKey, dataForSigning))
+
+	request := GatewaySendTransactionRequest{
+		RequestBase:     context.createRequestBase(),
+		Transaction:     tx,
+		CheckSignatures: true,
+		ChainID:         "other",
+	}
+	_, err = context.facade.GatewaySendTransaction(request)
+	require.NotNil(t, err)
+
+	request.ChainID = DefaultGatewayChainID
+	request.Transaction.Value = "2"
+	_, err = context.facade.GatewaySendTransaction(request)
+	require.NotNil(t, err)
+
+	request.Transaction.Value = "1"
+	_, err = context.facade.GatewaySendTransaction(request)
+	require.Nil(t, err)
+}
+
+func TestFacade_TransactionLog(t *testing.T) {
+	context := newTestContext(t)
+
+	alice := newDummyAddress("alice")
+	bob := newDummyAddress("bob")
+	carol := newDummyAddress("carol")
+	context.createAccountWithESDT(alice.hex, "100", &AccountESDT{TokenIdentifier: "TOKEN-abcdef", Value: "50"})
+
+	first, err := context.sendTransaction(newFrontendTransaction(alice.raw, bob.raw, 0, "30", ""))
+	require.Nil(t, err)
+	data := core.BuiltInFunctionESDTTransfer + "@" + hex.EncodeToString([]byte("TOKEN-abcdef")) + "@14"
+	_, err = context.sendTransaction(newFrontendTransaction(alice.raw, carol.raw, 1, "0", data))
+	require.Nil(t, err)
+
+	run, err := context.facade.RunSmartContract(RunRequest{
+		ContractRequestBase: ContractRequestBase{
+			RequestBase:     context.createRequestBase(),
+			ImpersonatedHex: alice.hex,
+			GasLimit:        gasLimit,
+		},
+		ContractAddressHex: bob.hex,
+		Function:           "missing",
+	})
+	require.Nil(t, err)
+	require.NotEmpty(t, run.TxHash)
+
+	transactionLog, err := context.facade.GetTransactionLog(TransactionLogRequest{RequestBase: context.createRequestBase()})
+	require.Nil(t, err)
+	require.Len(t, transactionLog.Entries, 4)
+	require.Equal(t, TransactionKindCreateAccount, transactionLog.Entries[0].Kind)
+	require.Equal(t, alice.hex, transactionLog.Entries[0].Account.Address)
+	require.Equal(t, "100", transactionLog.Entries[0].Account.Balance)
+	require.Equal(t, first.Hash, transactionLog.Entries[1].TxHash)
+	require.Equal(t, TransactionKindGateway, transactionLog.Entries[1].Kind)
+	require.Equal(t, TransactionKindRun, transactionLog.Entries[3].Kind)
+	require.NotEqual(t, vmcommon.Ok.String(), transactionLog.Entries[3].Output.ReturnCode)
+
+	transactionLog, err = context.facade.GetTransactionLog(TransactionLogRequest{
+		RequestBase: context.createRequestBase(),
+		ContractHex: bob.hex,
+	})
+	require.Nil(t, err)
+	require.Len(t, transactionLog.Entries, 2)
+
+	transactionLog, err = context.facade.GetTransactionLog(TransactionLogRequest{
+		RequestBase: context.createRequestBase(),
+		Function:    core.BuiltInFunctionESDTTransfer,
+	})
+	require.Nil(t, err)
+	require.Len(t, transactionLog.Entries, 1)
+	require.Equal(t, "TOKEN-abcdef", transactionLog.Entries[0].Input.ESDTTransfers[0].TokenIdentifier)
+
+	entry, err := context.facade.GetTransactionLogEntry(TransactionLogEntryRequest{
+		RequestBase: context.createRequestBase(),
+		TxHash:      run.TxHash,
+	})
+	require.Nil(t, err)
+	require.Equal(t, "missing", entry.Entry.Input.Function)
+
+	_, err = context.facade.GetTransactionLogEntry(TransactionLogEntryRequest{
+		RequestBase: context.createRequestBase(),
+		TxHash:      "abba",
+	})
+	require.ErrorIs(t, err, ErrTransactionNotFound)
+}
+
+func TestFacade_TransactionLog_WriteFailureKeepsCommittedRequests(t *testing.T) {
+	context := newTestContext(t)
+
+	alice := newDummyAddress("alice")
+	bob := newDummyAddress("bob")
+	// a directory in place of the log file makes every append fail
+	err := os.MkdirAll(newDatabase(databasePath).getTransactionLogFile(context.worldID), os.ModePerm)
+	require.Nil(t, err)
+
+	context.createAccount(alice.hex, "100")
+
+	_, err = context.sendTransaction(newFrontendTransaction(alice.raw, bob.raw, 0, "30", ""))
+	require.Nil(t, err)
+	require.Equal(t, "30", context.getGatewayAccount(bob.raw).Balance)
+
+	run, err := context.facade.RunSmartContract(RunRequest{
+		ContractRequestBase: ContractRequestBase{
+			RequestBase:     context.createRequestBase(),
+			ImpersonatedHex: alice.hex,
+			GasLimit:        gasLimit,
+		},
+		ContractAddressHex: bob.hex,
+		Function:           "missing",
+	})
+	require.Nil(t, err)
+	require.NotEmpty(t, run.TxHash)
+}
+
+func TestFacade_SubscribeEvents(t *testing.T) {
+	context := newTestContext(t)
+	otherContext := newTestContext(t)
+
+	alice := newDummyAddress("alice")
+	bob := newDummyAddress("bob")
+	carol := newDummyAddress("carol")
+	context.createAccountWithESDT(alice.hex, "100", &AccountESDT{TokenIdentifier: "TOKEN-abcdef", Value: "50"})
+
+	all, err := context.facade.SubscribeEvents(TransactionLogRequest{RequestBase: context.createRequestBase()})
+	require.Nil(t, err)
+	toCarol, err := context.facade.SubscribeEvents(TransactionLogRequest{
+		RequestBase: context.createRequestBase(),
+		ContractHex: carol.hex,
+	})
+	require.Nil(t, err)
+	otherWorld, err := context.facade.SubscribeEvents(TransactionLogRequest{RequestBase: otherContext.createRequestBase()})
+	require.Nil(t, err)
+
+	_, err = context.facade.SubscribeEvents(TransactionLogRequest{
+		RequestBase: context.createRequestBase(),
+		ContractHex: "not hex",
+	})
+	require.NotNil(t, err)
+
+	first, err := context.sendTransaction(newFrontendTransaction(alice.raw, bob.raw, 0, "30", ""))
+	require.Nil(t, err)
+	data := core.BuiltInFunctionESDTTransfer + "@" + hex.EncodeToString([]byte("TOKEN-abcdef")) + "@14"
+	second, err := context.sendTransaction(newFrontendTransaction(alice.raw, carol.raw, 1, "0", data))
+	require.Nil(t, err)
+
+	require.Len(t, all.Entries(), 2)
+	require.Equal(t, first.Hash, (<-all.Entries()).TxHash)
+	require.Equal(t, second.Hash, (<-all.Entries()).TxHash)
+	require.Len(t, toCarol.Entries(), 1)
+	require.Equal(t, second.Hash, (<-toCarol.Entries()).TxHash)
+	require.Len(t, otherWorld.Entries(), 0)
+
+	all.Close()
+	_, ok := <-all.Entries()
+	require.False(t, ok)
+
+	err = context.facade.Close()
+	require.Nil(t, err)
+	_, ok = <-toCarol.Entries()
+	require.False(t, ok)
+}
+
+func TestFacade_Gateway_Counter(t *testing.T) {
+	context := newTestContext(t)
+
+	alice := newDummyAddress("alice")
+	context.createAccount(alice.hex, "100")
+
+	code, err := ioutil.ReadFile(wasmCounterPath)
+	require.Nil(t, err)
+	deployData := strings.Join([]string{hex.EncodeToString(code), toHex(vmType), "0100"}, "@")
+	tx, err := context.sendTransaction(newFrontendTransaction(alice.raw, make([]byte, addressLength), 0, "0", deployData))
+	require.Nil(t, err)
+	require.Equal(t, transaction.TxStatusSuccess, tx.Status, tx.ReturnMessage)
+	require.Equal(t, deployEventIdentifier, tx.Logs.Events[0].Identifier)
+	contractAddress := tx.Logs.Events[0].Topics[0]
+
+	tx, err = context.sendTransaction(newFrontendTransaction(alice.raw, contractAddress, 1, "0", "increment"))
+	require.Nil(t, err)
+	require.Equal(t, transaction.TxStatusSuccess, tx.Status, tx.ReturnMessage)
+	require.Equal(t, "@6f6b@02", tx.SmartContractResults[0].Data)
+
+	storage, err := context.facade.GatewayGetStorage(GatewayStorageRequest{
+		GatewayAccountRequest: GatewayAccountRequest{
+			RequestBase:   context.createRequestBase(),
+			Bech32Address: encodeBech32Address(contractAddress),
+		},
+		KeyHex: hex.EncodeToString([]byte("COUNTER")),
+	})
+	require.Nil(t, err)
+	require.Equal(t, "02", storage.Value)
+
+	query, err := context.facade.GatewayQuery(GatewayQueryRequest{
+		RequestBase: context.createRequestBase(),
+		ScAddress:   encodeBech32Address(contractAddress),
+		FuncName:    "get",
+	})
+	require.Nil(t, err)
+	require.Equal(t, "ok", query.Data.ReturnCode)
+	require.Equal(t, [][]byte{{2}}, query.Data.ReturnData)
+}
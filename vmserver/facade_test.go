@@ -1,11 +1,28 @@
 package vmserver
 
 import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
 	"os"
+	"path"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/multiversx/mx-chain-core-go/core"
+	vmcommon "github.com/multiversx/mx-chain-vm-common-go"
 	worldmock "github.com/multiversx/mx-chain-vm-v1_4-go/mock/world"
+	"github.com/multiversx/mx-chain-vm-v1_4-go/scenarioexec"
+	mc "github.com/multiversx/mx-chain-vm-v1_4-go/scenarios/controller"
+	"github.com/multiversx/mx-chain-vm-v1_4-go/scenarios/esdtconvert"
+	"github.com/multiversx/mx-chain-vm-v1_4-go/vmhost"
 	"github.com/stretchr/testify/require"
+	bolt "go.etcd.io/bbolt"
 )
 
 var databasePath = "./testdata/db"
@@ -23,6 +40,911 @@ func TestFacade_CreateAccount(t *testing.T) {
 	require.True(t, context.accountExists(newDummyAddress("alice").raw))
 }
 
+func TestFacade_AddressesAndArgumentsAsExpressions(t *testing.T) {
+	context := newTestContext(t)
+	context.createAccount("address:alice", "42")
+
+	alice := []byte("alice___________________________")
+	require.True(t, context.accountExists(alice))
+
+	_, err := context.facade.GetAccount(AccountRequest{
+		RequestBase: context.createRequestBase(),
+		AddressHex:  encodeBech32Address(alice),
+	})
+	require.Nil(t, err)
+
+	run, err := context.facade.RunSmartContract(RunRequest{
+		ContractRequestBase: ContractRequestBase{
+			RequestBase:     context.createRequestBase(),
+			ImpersonatedHex: encodeBech32Address(alice),
+			GasLimit:        gasLimit,
+		},
+		ContractAddressHex: "sc:missing",
+		Function:           "missing",
+		ArgumentsHex:       []string{"str:abc", "u8:1", "address:alice"},
+	})
+	require.Nil(t, err)
+	require.Equal(t, alice, run.Input.CallerAddr)
+	require.Equal(t, [][]byte{[]byte("abc"), {1}, alice}, run.Input.Arguments)
+	require.NotNil(t, run.ReturnDataPretty)
+
+	transactionLog, err := context.facade.GetTransactionLog(TransactionLogRequest{
+		RequestBase: context.createRequestBase(),
+		ContractHex: "sc:missing",
+	})
+	require.Nil(t, err)
+	require.Len(t, transactionLog.Entries, 1)
+}
+
+func TestFacade_SnapshotForkRevertWorld(t *testing.T) {
+	context := newTestContext(t)
+
+	alice := newDummyAddress("alice")
+	bob := newDummyAddress("bob")
+	context.createAccount(alice.hex, "42")
+	context.snapshotWorld("withAlice")
+	context.createAccount(bob.hex, "42")
+	forked := context.forkWorld()
+
+	err := context.revertWorld("withAlice")
+	require.Nil(t, err)
+	require.True(t, context.accountExists(alice.raw))
+	require.False(t, context.accountExists(bob.raw))
+
+	require.True(t, forked.accountExists(alice.raw))
+	require.True(t, forked.accountExists(bob.raw))
+
+	err = context.revertWorld("missing")
+	require.ErrorIs(t, err, ErrSnapshotNotFound)
+}
+
+func TestFacade_CreateAccount_WithESDT(t *testing.T) {
+	context := newTestContext(t)
+
+	alice := newDummyAddress("alice")
+	context.createAccountWithESDT(alice.hex, "42",
+		&AccountESDT{TokenIdentifier: "TOKEN-123456", Value: "100", Roles: []string{"ESDTRoleLocalMint"}},
+		&AccountESDT{TokenIdentifier: "NFT-123456", Nonce: 3, Value: "1", AttributesHex: "616263"},
+	)
+
+	require.Equal(t, int64(100), context.getTokenBalance(alice.raw, "TOKEN-123456", 0))
+	require.Equal(t, int64(1), context.getTokenBalance(alice.raw, "NFT-123456", 3))
+
+	testWorld := context.loadWorld()
+	tokenData, err := testWorld.blockchainHook.BuiltinFuncs.GetTokenData(alice.raw, []byte("NFT-123456"), 3)
+	require.Nil(t, err)
+	require.Equal(t, []byte("abc"), tokenData.TokenMetaData.Attributes)
+
+	account := testWorld.blockchainHook.AcctMap.GetAccount(alice.raw)
+	roles, err := esdtconvert.GetTokenRoles([]byte("TOKEN-123456"), account.Storage)
+	require.Nil(t, err)
+	require.Equal(t, [][]byte{[]byte("ESDTRoleLocalMint")}, roles)
+}
+
+func TestFacade_RunContract_ESDTTransfersRevertedOnFailure(t *testing.T) {
+	context := newTestContext(t)
+
+	alice := newDummyAddress("alice")
+	bob := newDummyAddress("bob")
+	context.createAccountWithESDT(alice.hex, "42",
+		&AccountESDT{TokenIdentifier: "TOKEN-123456", Value: "100"},
+		&AccountESDT{TokenIdentifier: "NFT-123456", Nonce: 3, Value: "1"},
+	)
+	context.createAccount(bob.hex, "0")
+
+	request := RunRequest{
+		ContractRequestBase: ContractRequestBase{
+			RequestBase:     context.createRequestBase(),
+			ImpersonatedHex: alice.hex,
+			GasLimit:        gasLimit,
+			ESDTTransfers: []*ESDTTransfer{
+				{TokenIdentifier: "TOKEN-123456", Value: "10"},
+				{TokenIdentifier: "NFT-123456", Nonce: 3, Value: "1"},
+			},
+		},
+		ContractAddressHex: bob.hex,
+		Function:           "deposit",
+	}
+
+	response, err := context.facade.RunSmartContract(request)
+	require.Nil(t, err)
+	require.NotEqual(t, vmcommon.Ok.String(), response.ReturnCodeString)
+	require.Len(t, response.Input.ESDTTransfers, 2)
+
+	require.Equal(t, int64(100), context.getTokenBalance(alice.raw, "TOKEN-123456", 0))
+	require.Equal(t, int64(1), context.getTokenBalance(alice.raw, "NFT-123456", 3))
+}
+
+func TestWorld_PerformESDTTransfers(t *testing.T) {
+	context := newTestContext(t)
+
+	alice := newDummyAddress("alice")
+	bob := newDummyAddress("bob")
+	context.createAccountWithESDT(alice.hex, "42",
+		&AccountESDT{TokenIdentifier: "TOKEN-123456", Value: "100"},
+		&AccountESDT{TokenIdentifier: "NFT-123456", Nonce: 3, Value: "1"},
+	)
+	context.createAccount(bob.hex, "0")
+
+	request := &ContractRequestBase{
+		ImpersonatedHex: alice.hex,
+		GasLimit:        gasLimit,
+		ESDTTransfers: []*ESDTTransfer{
+			{TokenIdentifier: "TOKEN-123456", Value: "10"},
+			{TokenIdentifier: "NFT-123456", Nonce: 3, Value: "1"},
+		},
+	}
+	require.Nil(t, request.digest())
+
+	testWorld := context.loadWorld()
+	_, err := testWorld.performESDTTransfers(bob.raw, request)
+	require.Nil(t, err)
+	require.Nil(t, newDatabase(databasePath).storeWorld(testWorld))
+
+	require.Equal(t, int64(90), context.getTokenBalance(alice.raw, "TOKEN-123456", 0))
+	require.Equal(t, int64(10), context.getTokenBalance(bob.raw, "TOKEN-123456", 0))
+	require.Equal(t, int64(1), context.getTokenBalance(bob.raw, "NFT-123456", 3))
+}
+
+func TestFacade_SetBlock(t *testing.T) {
+	context := newTestContext(t)
+
+	nonce := uint64(10)
+	timestamp := uint64(100)
+	epoch := uint32(3)
+	response, err := context.facade.SetBlock(BlockRequest{
+		RequestBase: context.createRequestBase(),
+		Current:     &BlockInfoRequest{Nonce: &nonce, Timestamp: &timestamp, Epoch: &epoch},
+		Advance:     2,
+	})
+	require.Nil(t, err)
+	require.Equal(t, uint64(12), response.Current.Nonce)
+	require.Equal(t, uint64(112), response.Current.Timestamp)
+	require.Equal(t, uint32(3), response.Current.Epoch)
+	require.Equal(t, uint64(11), response.Previous.Nonce)
+	require.NotEqual(t, response.Previous.RandomSeedHex, response.Current.RandomSeedHex)
+
+	testWorld := context.loadWorld()
+	require.Equal(t, uint64(12), testWorld.blockchainHook.CurrentBlockInfo.BlockNonce)
+	require.Equal(t, uint64(11), testWorld.blockchainHook.PreviousBlockInfo.BlockNonce)
+}
+
+func TestFacade_SetBlock_AutoAdvanceOnRun(t *testing.T) {
+	context := newTestContext(t)
+
+	alice := newDummyAddress("alice")
+	context.createAccount(alice.hex, "42")
+
+	autoAdvance := true
+	secondsPerBlock := uint64(1)
+	_, err := context.facade.SetBlock(BlockRequest{
+		RequestBase:     context.createRequestBase(),
+		AutoAdvance:     &autoAdvance,
+		SecondsPerBlock: &secondsPerBlock,
+	})
+	require.Nil(t, err)
+
+	_, err = context.facade.RunSmartContract(RunRequest{
+		ContractRequestBase: ContractRequestBase{
+			RequestBase:     context.createRequestBase(),
+			ImpersonatedHex: alice.hex,
+			GasLimit:        gasLimit,
+		},
+		ContractAddressHex: alice.hex,
+		Function:           "missing",
+	})
+	require.Nil(t, err)
+
+	testWorld := context.loadWorld()
+	require.Equal(t, uint64(1), testWorld.blockchainHook.CurrentBlockInfo.BlockNonce)
+	require.Equal(t, uint64(1), testWorld.blockchainHook.CurrentBlockInfo.BlockTimestamp)
+}
+
+func TestFacade_SetWorldConfig(t *testing.T) {
+	context := newTestContext(t)
+
+	testWorld := context.loadWorld()
+	host := testWorld.vm.(vmhost.VMHost)
+	require.Equal(t, uint64(1), host.GetGasScheduleMap()["BaseOperationCost"]["StorePerByte"])
+	require.False(t, host.EnableEpochsHandler().IsStorageAPICostOptimizationFlagEnabled())
+
+	enabledFlags := []string{"IsStorageAPICostOptimizationFlagEnabled"}
+	response, err := context.facade.SetWorldConfig(WorldConfigRequest{
+		RequestBase:  context.createRequestBase(),
+		GasSchedule:  GasScheduleV4,
+		EnabledFlags: &enabledFlags,
+	})
+	require.Nil(t, err)
+	require.Equal(t, GasScheduleV4, response.GasSchedule)
+	require.Equal(t, enabledFlags, response.EnabledFlags)
+
+	expectedGasSchedule, err := loadGasSchedule(GasScheduleV4, "")
+	require.Nil(t, err)
+
+	testWorld = context.loadWorld()
+	host = testWorld.vm.(vmhost.VMHost)
+	require.Equal(t, expectedGasSchedule, host.GetGasScheduleMap())
+	require.True(t, host.EnableEpochsHandler().IsStorageAPICostOptimizationFlagEnabled())
+	require.False(t, host.EnableEpochsHandler().IsFixOOGReturnCodeFlagEnabled())
+
+	enabledFlags = []string{AllEnabledFlags}
+	_, err = context.facade.SetWorldConfig(WorldConfigRequest{
+		RequestBase:  context.createRequestBase(),
+		EnabledFlags: &enabledFlags,
+	})
+	require.Nil(t, err)
+
+	testWorld = context.loadWorld()
+	host = testWorld.vm.(vmhost.VMHost)
+	require.Equal(t, expectedGasSchedule, host.GetGasScheduleMap())
+	require.True(t, host.EnableEpochsHandler().IsFixOOGReturnCodeFlagEnabled())
+}
+
+func TestFacade_SetWorldConfig_Invalid(t *testing.T) {
+	context := newTestContext(t)
+
+	_, err := context.facade.SetWorldConfig(WorldConfigRequest{
+		RequestBase: context.createRequestBase(),
+		GasSchedule: "v0",
+	})
+	require.ErrorIs(t, err, ErrUnknownGasSchedule)
+
+	enabledFlags := []string{"IsMissingFlagEnabled"}
+	_, err = context.facade.SetWorldConfig(WorldConfigRequest{
+		RequestBase:  context.createRequestBase(),
+		EnabledFlags: &enabledFlags,
+	})
+	require.ErrorIs(t, err, ErrUnknownEnabledFlag)
+
+	_, err = context.facade.SetWorldConfig(WorldConfigRequest{
+		RequestBase:     context.createRequestBase(),
+		GasSchedulePath: "./testdata/missing.toml",
+	})
+	require.NotNil(t, err)
+}
+
+func TestFacade_ConcurrentRequestsOnSameWorld(t *testing.T) {
+	context := newTestContext(t)
+
+	numAccounts := 20
+	var wg sync.WaitGroup
+	wg.Add(numAccounts)
+	for i := 0; i < numAccounts; i++ {
+		go func(index int) {
+			defer wg.Done()
+			context.createAccount(newDummyAddress(fmt.Sprintf("account%d", index)).hex, "42")
+		}(i)
+	}
+	wg.Wait()
+
+	testWorld := context.loadWorld()
+	require.Len(t, testWorld.blockchainHook.AcctMap, numAccounts)
+}
+
+func TestFacade_ConcurrentRequestsOnWorldsWithDifferentGasSchedules(t *testing.T) {
+	facade := NewDebugFacade()
+	alice := newDummyAddress("alice")
+	bob := newDummyAddress("bob")
+
+	schedules := []string{GasScheduleV3, GasScheduleV4}
+	contexts := make([]*testContext, len(schedules))
+	for i, schedule := range schedules {
+		contexts[i] = newTestContext(t)
+		contexts[i].facade = facade
+		contexts[i].createAccountWithESDT(alice.hex, "42", &AccountESDT{TokenIdentifier: "TOKEN-123456", Value: "100"})
+		contexts[i].createAccount(bob.hex, "0")
+
+		_, err := facade.SetWorldConfig(WorldConfigRequest{
+			RequestBase: contexts[i].createRequestBase(),
+			GasSchedule: schedule,
+		})
+		require.Nil(t, err)
+	}
+
+	// the transfer fails on bob, who has no code, so it is rolled back and costs the same each time
+	transferGasLeft := func(context *testContext) uint64 {
+		response, err := facade.RunSmartContract(RunRequest{
+			ContractRequestBase: ContractRequestBase{
+				RequestBase:     context.createRequestBase(),
+				ImpersonatedHex: alice.hex,
+				GasLimit:        gasLimit,
+				ESDTTransfers:   []*ESDTTransfer{{TokenIdentifier: "TOKEN-123456", Value: "10"}},
+			},
+			ContractAddressHex: bob.hex,
+		})
+		if err != nil || response.Input == nil {
+			return 0
+		}
+		return response.Input.GasProvided
+	}
+
+	expectedGasLeft := make([]uint64, len(schedules))
+	for i, context := range contexts {
+		expectedGasLeft[i] = transferGasLeft(context)
+	}
+	require.NotEqual(t, expectedGasLeft[0], expectedGasLeft[1])
+
+	numRequests := 10
+	gasLeft := make([][]uint64, len(schedules))
+	var numDone uint64
+	var wg sync.WaitGroup
+
+	vmHostMutex.Lock()
+	for i, context := range contexts {
+		gasLeft[i] = make([]uint64, numRequests)
+		for j := 0; j < numRequests; j++ {
+			wg.Add(1)
+			go func(i int, j int, context *testContext) {
+				defer wg.Done()
+				gasLeft[i][j] = transferGasLeft(context)
+				atomic.AddUint64(&numDone, 1)
+			}(i, j, context)
+		}
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, uint64(0), atomic.LoadUint64(&numDone))
+	vmHostMutex.Unlock()
+	wg.Wait()
+
+	for i := range schedules {
+		for j := 0; j < numRequests; j++ {
+			require.Equal(t, expectedGasLeft[i], gasLeft[i][j])
+		}
+	}
+}
+
+func TestFacade_CacheWorlds(t *testing.T) {
+	context := newTestContext(t)
+	context.facade = NewDebugFacadeWithArgs(ArgsNewDebugFacade{CacheWorlds: true})
+
+	alice := newDummyAddress("alice")
+	bob := newDummyAddress("bob")
+	context.createAccount(alice.hex, "42")
+	context.createAccount(bob.hex, "43")
+
+	response, err := context.facade.GetAccounts(AccountsRequest{RequestBase: context.createRequestBase()})
+	require.Nil(t, err)
+	require.Contains(t, string(response.Accounts), `"balance": "43"`)
+
+	worldFile := newDatabase(databasePath).getWorldFile(context.worldID)
+	require.False(t, fileExists(worldFile))
+
+	err = context.facade.Close()
+	require.Nil(t, err)
+	require.True(t, fileExists(worldFile))
+
+	testWorld := context.loadWorld()
+	require.Len(t, testWorld.blockchainHook.AcctMap, 2)
+}
+
+func TestFacade_CacheWorlds_FlushInterval(t *testing.T) {
+	context := newTestContext(t)
+	context.facade = NewDebugFacadeWithArgs(ArgsNewDebugFacade{CacheWorlds: true, FlushInterval: 10 * time.Millisecond})
+	defer func() {
+		_ = context.facade.Close()
+	}()
+
+	context.createAccount(newDummyAddress("alice").hex, "42")
+
+	worldFile := newDatabase(databasePath).getWorldFile(context.worldID)
+	require.Eventually(t, func() bool {
+		return fileExists(worldFile)
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestDatabase_WriteFileAtomically(t *testing.T) {
+	folder := t.TempDir()
+	filePath := path.Join(folder, "world.json")
+
+	err := writeFileAtomically(filePath, []byte("first"))
+	require.Nil(t, err)
+	err = writeFileAtomically(filePath, []byte("second"))
+	require.Nil(t, err)
+
+	data, err := ioutil.ReadFile(filePath)
+	require.Nil(t, err)
+	require.Equal(t, "second", string(data))
+
+	files, err := ioutil.ReadDir(folder)
+	require.Nil(t, err)
+	require.Len(t, files, 1)
+}
+
+// legacyWorldFile is a world file as written before accounts became a list: a map by address, with raw storage keys
+const legacyWorldFile = `{
+	"ID": "legacy",
+	"Accounts": {
+		"alice": {
+			"Exists": true, "Address": "YWxpY2U=", "Nonce": 3, "Balance": 7, "BalanceDelta": 0,
+			"Storage": {"key": "AQ=="}, "DeveloperReward": 0, "ShardID": 0, "IsSmartContract": false
+		},
+		"bob": {
+			"Exists": true, "Address": "Ym9i", "Nonce": 0, "Balance": 11, "BalanceDelta": 0,
+			"Storage": {}, "DeveloperReward": 0, "ShardID": 0, "IsSmartContract": false
+		}
+	},
+	"CurrentBlockInfo": {"BlockTimestamp": 6, "BlockNonce": 1, "BlockRound": 1, "BlockEpoch": 0},
+	"AutoAdvanceBlock": true,
+	"SecondsPerBlock": 6
+}`
+
+func TestDatabase_LoadLegacyWorldFile(t *testing.T) {
+	db := newDatabase(t.TempDir())
+	worldFile := db.getWorldFile("legacy")
+	err := ioutil.WriteFile(worldFile, []byte(legacyWorldFile), 0644)
+	require.Nil(t, err)
+
+	legacyWorld, err := db.loadWorld("legacy")
+	require.Nil(t, err)
+	require.Len(t, legacyWorld.blockchainHook.AcctMap, 2)
+	alice := legacyWorld.blockchainHook.AcctMap.GetAccount([]byte("alice"))
+	require.NotNil(t, alice)
+	require.Equal(t, uint64(3), alice.Nonce)
+	require.Equal(t, big.NewInt(7), alice.Balance)
+	require.Equal(t, []byte{1}, alice.Storage["key"])
+	require.Equal(t, big.NewInt(11), legacyWorld.blockchainHook.AcctMap.GetAccount([]byte("bob")).Balance)
+	require.Equal(t, uint64(1), legacyWorld.blockchainHook.CurrentBlockInfo.BlockNonce)
+
+	// the next store rewrites the file as a list of accounts, with hex storage keys
+	err = db.storeWorld(legacyWorld)
+	require.Nil(t, err)
+	data, err := ioutil.ReadFile(worldFile)
+	require.Nil(t, err)
+	stored := &worldDataModelJSON{}
+	err = json.Unmarshal(data, stored)
+	require.Nil(t, err)
+	require.Equal(t, byte('['), stored.Accounts[0])
+	require.Contains(t, string(stored.Accounts), `"6b6579"`)
+
+	rewrittenWorld, err := db.loadWorld("legacy")
+	require.Nil(t, err)
+	require.Equal(t, []byte{1}, rewrittenWorld.blockchainHook.AcctMap.GetAccount([]byte("alice")).Storage["key"])
+}
+
+func TestWorldDataModel_JSON(t *testing.T) {
+	address := []byte{0xff, 0xfe, 0x00, 0x80}
+	storageKey := string([]byte{0xc3, 0x28})
+
+	dataModel := newWorldDataModel("binary")
+	dataModel.Accounts.PutAccount(&worldmock.Account{
+		Address:         address,
+		Balance:         big.NewInt(42),
+		BalanceDelta:    big.NewInt(0),
+		DeveloperReward: big.NewInt(0),
+		Storage:         map[string][]byte{storageKey: {1}},
+	})
+
+	data, err := json.Marshal(dataModel)
+	require.Nil(t, err)
+
+	readDataModel := &worldDataModel{}
+	err = json.Unmarshal(data, readDataModel)
+	require.Nil(t, err)
+	require.Equal(t, "binary", readDataModel.ID)
+	require.Equal(t, DefaultSecondsPerBlock, int(readDataModel.SecondsPerBlock))
+	account := readDataModel.Accounts.GetAccount(address)
+	require.NotNil(t, account)
+	require.Equal(t, big.NewInt(42), account.Balance)
+	require.Equal(t, []byte{1}, account.Storage[storageKey])
+
+	legacyData := []byte(`{"ID": "legacy", "Accounts": {"alice": {"Address": "YWxpY2U=", "Balance": 7, "Storage": {"key": "AQ=="}}}}`)
+	readDataModel = &worldDataModel{}
+	err = json.Unmarshal(legacyData, readDataModel)
+	require.Nil(t, err)
+	account = readDataModel.Accounts.GetAccount([]byte("alice"))
+	require.NotNil(t, account)
+	require.Equal(t, big.NewInt(7), account.Balance)
+	require.Equal(t, []byte{1}, account.Storage["key"])
+}
+
+func TestBoltWorldStore_WritesOnlyChangedAccounts(t *testing.T) {
+	store, err := newBoltWorldStore(t.TempDir())
+	require.Nil(t, err)
+	defer func() {
+		_ = store.Close()
+	}()
+
+	dataModel := newWorldDataModel("bolt")
+	dataModel.SecondsPerBlock = 3
+	for _, name := range []string{"alice", "bob", "carol"} {
+		dataModel.Accounts.PutAccount(&worldmock.Account{
+			Address:         newDummyAddress(name).raw,
+			Balance:         big.NewInt(42),
+			BalanceDelta:    big.NewInt(0),
+			DeveloperReward: big.NewInt(0),
+			Storage:         map[string][]byte{"first": {1}, "second": {2}},
+		})
+	}
+
+	storeAndCount := func(dataModel *worldDataModel) int {
+		var numChanged int
+		err := store.db.Update(func(tx *bolt.Tx) error {
+			worldBucket, err := createNestedBucket(tx, boltWorldsBucket, []byte(dataModel.ID))
+			if err != nil {
+				return err
+			}
+
+			numChanged, err = writeWorldBucket(worldBucket, dataModel)
+			return err
+		})
+		require.Nil(t, err)
+		return numChanged
+	}
+
+	require.Equal(t, 3, storeAndCount(dataModel))
+	require.Equal(t, 0, storeAndCount(dataModel))
+
+	bob := dataModel.Accounts.GetAccount(newDummyAddress("bob").raw)
+	bob.Storage["first"] = []byte{3}
+	delete(bob.Storage, "second")
+	require.Equal(t, 1, storeAndCount(dataModel))
+
+	delete(dataModel.Accounts, string(newDummyAddress("carol").raw))
+	require.Equal(t, 1, storeAndCount(dataModel))
+
+	loaded, err := store.LoadWorld("bolt")
+	require.Nil(t, err)
+	require.Equal(t, uint64(3), loaded.SecondsPerBlock)
+	require.Len(t, loaded.Accounts, 2)
+	require.Equal(t, map[string][]byte{"first": {3}}, loaded.Accounts.GetAccount(newDummyAddress("bob").raw).Storage)
+	require.Equal(t, big.NewInt(42), loaded.Accounts.GetAccount(newDummyAddress("alice").raw).Balance)
+
+	err = store.StoreSnapshot("before", loaded)
+	require.Nil(t, err)
+	snapshot, err := store.LoadSnapshot("bolt", "before")
+	require.Nil(t, err)
+	require.Len(t, snapshot.Accounts, 2)
+
+	missing, err := store.LoadSnapshot("bolt", "missing")
+	require.Nil(t, err)
+	require.Nil(t, missing)
+	missing, err = store.LoadWorld("missing")
+	require.Nil(t, err)
+	require.Nil(t, missing)
+}
+
+func TestBoltWorldStore_WritesOnlyTrackedChanges(t *testing.T) {
+	folder := t.TempDir()
+	store, err := newBoltWorldStore(folder)
+	require.Nil(t, err)
+
+	tokenKey := core.ProtectedKeyPrefix + "esdtTOKEN-abcdef"
+	dataModel := newWorldDataModel("bolt")
+	for _, name := range []string{"alice", "bob", "carol"} {
+		dataModel.Accounts.PutAccount(&worldmock.Account{
+			Address:         newDummyAddress(name).raw,
+			Balance:         big.NewInt(42),
+			BalanceDelta:    big.NewInt(0),
+			DeveloperReward: big.NewInt(0),
+			Storage:         map[string][]byte{"first": {1}, "second": {2}, tokenKey: {5}},
+		})
+	}
+	err = store.StoreWorld(dataModel)
+	require.Nil(t, err)
+
+	loaded, err := store.LoadWorld("bolt")
+	require.Nil(t, err)
+	require.NotNil(t, loaded.changes)
+	require.Len(t, loaded.changes.accounts, 0)
+
+	// changed without being recorded, so not written
+	loaded.Accounts.GetAccount(newDummyAddress("alice").raw).Balance = big.NewInt(1)
+
+	bob := loaded.Accounts.GetAccount(newDummyAddress("bob").raw)
+	bob.Storage["first"] = []byte{3}
+	bob.Storage["second"] = []byte{4}
+	delete(bob.Storage, tokenKey)
+	bobChanges := loaded.changes.account(bob.Address)
+	bobChanges.storageKeys["first"] = struct{}{}
+	bobChanges.protectedKeys = true
+
+	delete(loaded.Accounts, string(newDummyAddress("carol").raw))
+	loaded.changes.account(newDummyAddress("carol").raw)
+
+	err = store.StoreWorld(loaded)
+	require.Nil(t, err)
+	err = store.Close()
+	require.Nil(t, err)
+
+	store, err = newBoltWorldStore(folder)
+	require.Nil(t, err)
+	defer func() {
+		_ = store.Close()
+	}()
+
+	stored, err := store.LoadWorld("bolt")
+	require.Nil(t, err)
+	require.Len(t, stored.Accounts, 2)
+	require.Equal(t, big.NewInt(42), stored.Accounts.GetAccount(newDummyAddress("alice").raw).Balance)
+	require.Equal(t, map[string][]byte{"first": {3}, "second": {2}}, stored.Accounts.GetAccount(bob.Address).Storage)
+}
+
+func BenchmarkBoltWorldStore_LoadWorld(b *testing.B) {
+	for _, numAccounts := range []int{100, 1000, 10000} {
+		b.Run(fmt.Sprintf("%d accounts", numAccounts), func(b *testing.B) {
+			store, err := newBoltWorldStore(b.TempDir())
+			require.Nil(b, err)
+			defer func() {
+				_ = store.Close()
+			}()
+
+			dataModel := newWorldDataModel("bolt")
+			for i := 0; i < numAccounts; i++ {
+				storage := make(map[string][]byte)
+				for j := 0; j < 10; j++ {
+					storage[fmt.Sprintf("key%d", j)] = bytes.Repeat([]byte{byte(j)}, 32)
+				}
+
+				dataModel.Accounts.PutAccount(&worldmock.Account{
+					Address:         newDummyAddress(fmt.Sprintf("account%d", i)).raw,
+					Balance:         big.NewInt(42),
+					BalanceDelta:    big.NewInt(0),
+					DeveloperReward: big.NewInt(0),
+					Storage:         storage,
+				})
+			}
+			err = store.StoreWorld(dataModel)
+			require.Nil(b, err)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, err = store.LoadWorld("bolt")
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestFacade_WorldStoreBolt_ChangedAccounts(t *testing.T) {
+	t.Run("stored on each request", func(t *testing.T) {
+		testWorldStoreBoltChangedAccounts(t, ArgsNewDebugFacade{WorldStore: WorldStoreBolt})
+	})
+	t.Run("stored from the cache", func(t *testing.T) {
+		testWorldStoreBoltChangedAccounts(t, ArgsNewDebugFacade{WorldStore: WorldStoreBolt, CacheWorlds: true})
+	})
+}
+
+func testWorldStoreBoltChangedAccounts(t *testing.T, args ArgsNewDebugFacade) {
+	context := newTestContext(t)
+	context.facade = NewDebugFacadeWithArgs(args)
+
+	alice := newDummyAddress("alice")
+	bob := newDummyAddress("bob")
+	context.createAccountWithESDT(alice.hex, "100", &AccountESDT{TokenIdentifier: "TOKEN-abcdef", Value: "50"})
+
+	_, err := context.sendTransaction(newFrontendTransaction(alice.raw, bob.raw, 0, "30", ""))
+	require.Nil(t, err)
+	data := core.BuiltInFunctionESDTTransfer + "@" + hex.EncodeToString([]byte("TOKEN-abcdef")) + "@14"
+	_, err = context.sendTransaction(newFrontendTransaction(alice.raw, bob.raw, 1, "0", data))
+	require.Nil(t, err)
+
+	err = context.facade.Close()
+	require.Nil(t, err)
+
+	context.facade = NewDebugFacadeWithArgs(args)
+	defer func() {
+		_ = context.facade.Close()
+	}()
+
+	aliceAccount := context.getGatewayAccount(alice.raw)
+	require.Equal(t, "70", aliceAccount.Balance)
+	require.Equal(t, uint64(2), aliceAccount.Nonce)
+	require.Equal(t, "30", context.getGatewayAccount(bob.raw).Balance)
+
+	tokenBalance := func(address *dummyAddress) string {
+		response, err := context.facade.GetESDT(ESDTRequest{
+			AccountRequest:  AccountRequest{RequestBase: context.createRequestBase(), AddressHex: address.hex},
+			TokenIdentifier: "TOKEN-abcdef",
+		})
+		require.Nil(t, err)
+		return string(response.ESDT)
+	}
+	require.Contains(t, tokenBalance(alice), `"30"`)
+	require.Contains(t, tokenBalance(bob), `"20"`)
+}
+
+func TestFacade_WorldStoreBolt(t *testing.T) {
+	context := newTestContext(t)
+	context.facade = NewDebugFacadeWithArgs(ArgsNewDebugFacade{WorldStore: WorldStoreBolt})
+
+	// the world is not in a JSON file, so it is read through the facade
+	hasAccount := func(address *dummyAddress) bool {
+		_, err := context.facade.GetAccount(AccountRequest{RequestBase: context.createRequestBase(), AddressHex: address.hex})
+		return err == nil
+	}
+
+	alice := newDummyAddress("alice")
+	bob := newDummyAddress("bob")
+	context.createAccount(alice.hex, "42")
+	context.snapshotWorld("afterAlice")
+	context.createAccount(bob.hex, "43")
+
+	worldFile := newDatabase(databasePath).getWorldFile(context.worldID)
+	require.False(t, fileExists(worldFile))
+	require.True(t, hasAccount(bob))
+
+	err := context.revertWorld("afterAlice")
+	require.Nil(t, err)
+	require.False(t, hasAccount(bob))
+
+	err = context.facade.Close()
+	require.Nil(t, err)
+
+	context.facade = NewDebugFacadeWithArgs(ArgsNewDebugFacade{WorldStore: WorldStoreBolt})
+	defer func() {
+		_ = context.facade.Close()
+	}()
+	require.True(t, hasAccount(alice))
+
+	_, err = NewDebugFacadeWithArgs(ArgsNewDebugFacade{WorldStore: "sql"}).GetAccounts(AccountsRequest{RequestBase: context.createRequestBase()})
+	require.ErrorIs(t, err, ErrUnknownWorldStore)
+}
+
+func TestFacade_ExportScenario(t *testing.T) {
+	context := newTestContext(t)
+
+	alice := newDummyAddress("alice")
+	bob := newDummyAddress("bob")
+	context.createAccountWithESDT(alice.hex, "100", &AccountESDT{TokenIdentifier: "TOKEN-abcdef", Value: "50"})
+
+	_, err := context.facade.ExportScenario(ScenarioRequest{RequestBase: context.createRequestBase()})
+	require.ErrorIs(t, err, ErrRecordingNotFound)
+
+	// sent before the recording, part of the initial state
+	_, err = context.sendTransaction(newFrontendTransaction(alice.raw, bob.raw, 0, "10", ""))
+	require.Nil(t, err)
+
+	recording, err := context.facade.StartRecording(RecordingRequest{RequestBase: context.createRequestBase()})
+	require.Nil(t, err)
+	require.Equal(t, 2, recording.FirstEntry)
+
+	carol := newDummyAddress("carol")
+	context.createAccountWithESDT(carol.hex, "7", &AccountESDT{TokenIdentifier: "TOKEN-abcdef", Value: "3"})
+
+	_, err = context.sendTransaction(newFrontendTransaction(alice.raw, bob.raw, 1, "30", ""))
+	require.Nil(t, err)
+	data := core.BuiltInFunctionESDTTransfer + "@" + hex.EncodeToString([]byte("TOKEN-abcdef")) + "@14"
+	_, err = context.sendTransaction(newFrontendTransaction(alice.raw, bob.raw, 2, "0", data))
+	require.Nil(t, err)
+
+	nonce := uint64(5)
+	_, err = context.facade.SetBlock(BlockRequest{
+		RequestBase: context.createRequestBase(),
+		Current:     &BlockInfoRequest{Nonce: &nonce},
+	})
+	require.Nil(t, err)
+
+	run, err := context.facade.RunSmartContract(RunRequest{
+		ContractRequestBase: ContractRequestBase{
+			RequestBase:     context.createRequestBase(),
+			ImpersonatedHex: alice.hex,
+			GasLimit:        gasLimit,
+		},
+		ContractAddressHex: bob.hex,
+		Function:           "missing",
+	})
+	require.Nil(t, err)
+	require.NotEqual(t, vmcommon.Ok.String(), run.ReturnCodeString)
+
+	_, err = context.sendTransaction(newFrontendTransaction(bob.raw, alice.raw, 0, "5", ""))
+	require.Nil(t, err)
+	_, err = context.sendTransaction(newFrontendTransaction(carol.raw, alice.raw, 0, "2", ""))
+	require.Nil(t, err)
+
+	response, err := context.facade.ExportScenario(ScenarioRequest{
+		RequestBase: context.createRequestBase(),
+		Name:        "recorded",
+		CheckState:  true,
+	})
+	require.Nil(t, err)
+
+	scenario := struct {
+		Name  string
+		Steps []struct {
+			Step string
+		}
+	}{}
+	err = json.Unmarshal(response.Scenario, &scenario)
+	require.Nil(t, err)
+	require.Equal(t, "recorded", scenario.Name)
+	stepNames := make([]string, 0, len(scenario.Steps))
+	for _, step := range scenario.Steps {
+		stepNames = append(stepNames, step.Step)
+	}
+	// the call to an account without code cannot be replayed, it is left out
+	require.Equal(t, []string{"setState", "setState", "transfer", "transfer", "setState", "transfer", "transfer", "checkState"}, stepNames)
+
+	scenarioPath := path.Join(t.TempDir(), "recorded.scen.json")
+	err = ioutil.WriteFile(scenarioPath, response.Scenario, 0644)
+	require.Nil(t, err)
+
+	executor, err := scenarioexec.NewVMTestExecutor()
+	require.Nil(t, err)
+	defer executor.Close()
+
+	runner := mc.NewScenarioRunner(executor, mc.NewDefaultFileResolver())
+	err = runner.RunSingleJSONScenario(scenarioPath, mc.DefaultRunScenarioOptions())
+	require.Nil(t, err)
+}
+
+func TestFacade_ExportScenario_WorldConfigChanged(t *testing.T) {
+	context := newTestContext(t)
+
+	_, err := context.facade.StartRecording(RecordingRequest{RequestBase: context.createRequestBase()})
+	require.Nil(t, err)
+
+	_, err = context.facade.SetWorldConfig(WorldConfigRequest{
+		RequestBase: context.createRequestBase(),
+		GasSchedule: GasScheduleV4,
+	})
+	require.Nil(t, err)
+
+	_, err = context.facade.ExportScenario(ScenarioRequest{RequestBase: context.createRequestBase()})
+	require.ErrorIs(t, err, ErrRecordingNotExportable)
+}
+
+func TestFacade_InspectWorld(t *testing.T) {
+	context := newTestContext(t)
+
+	alice := newDummyAddress("alice")
+	context.createAccount(alice.hex, "42")
+
+	testWorld := context.loadWorld()
+	account := testWorld.blockchainHook.AcctMap.GetAccount(alice.raw)
+	account.Storage["COUNTER"] = []byte{2}
+	require.Nil(t, account.SetTokenBalanceUint64([]byte("TOKEN-123456"), 0, 100))
+	testWorld.blockchainHook.AcctMap.CreateAccount(vmcommon.SystemAccountAddress, testWorld.blockchainHook)
+	require.Nil(t, newDatabase(databasePath).storeWorld(testWorld))
+
+	accountsResponse, err := context.facade.GetAccounts(AccountsRequest{RequestBase: context.createRequestBase()})
+	require.Nil(t, err)
+	require.Contains(t, string(accountsResponse.Accounts), `"balance": "42"`)
+
+	// the system account is left out, as in the exported scenarios
+	var accounts map[string]json.RawMessage
+	require.Nil(t, json.Unmarshal(accountsResponse.Accounts, &accounts))
+	require.Len(t, accounts, 1)
+
+	accountResponse, err := context.facade.GetAccount(AccountRequest{
+		RequestBase: context.createRequestBase(),
+		AddressHex:  alice.hex,
+	})
+	require.Nil(t, err)
+	require.Contains(t, string(accountResponse.Account), `"0x434f554e544552 (str:COUNTER)": "0x02 (2)"`)
+
+	storageResponse, err := context.facade.GetStorage(StorageRequest{
+		AccountRequest: AccountRequest{RequestBase: context.createRequestBase(), AddressHex: alice.hex},
+		Key:            "str:COUNTER",
+	})
+	require.Nil(t, err)
+	require.Equal(t, "0x02 (2)", storageResponse.Value)
+	require.Equal(t, "02", storageResponse.ValueHex)
+
+	esdtResponse, err := context.facade.GetESDT(ESDTRequest{
+		AccountRequest:  AccountRequest{RequestBase: context.createRequestBase(), AddressHex: alice.hex},
+		TokenIdentifier: "TOKEN-123456",
+	})
+	require.Nil(t, err)
+	require.Contains(t, string(esdtResponse.ESDT), `"str:TOKEN-123456"`)
+	require.Contains(t, string(esdtResponse.ESDT), `"balance": "100"`)
+
+	_, err = context.facade.GetESDT(ESDTRequest{
+		AccountRequest:  AccountRequest{RequestBase: context.createRequestBase(), AddressHex: alice.hex},
+		TokenIdentifier: "OTHER-123456",
+	})
+	require.ErrorIs(t, err, ErrTokenNotFound)
+
+	_, err = context.facade.GetAccount(AccountRequest{
+		RequestBase: context.createRequestBase(),
+		AddressHex:  newDummyAddress("bob").hex,
+	})
+	require.ErrorIs(t, err, ErrAccountNotFound)
+}
+
 func TestFacade_RunContract_Counter(t *testing.T) {
 	context := newTestContext(t)
 
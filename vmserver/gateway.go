@@ -0,0 +1,125 @@
+package vmserver
+
+import (
+	"encoding/hex"
+	"math/big"
+
+	"github.com/multiversx/mx-chain-core-go/core/pubkeyConverter"
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+	"github.com/multiversx/mx-chain-core-go/hashing/keccak"
+	"github.com/multiversx/mx-chain-core-go/marshal"
+	"github.com/multiversx/mx-chain-vm-v1_4-go/crypto/signing/ed25519"
+	worldmock "github.com/multiversx/mx-chain-vm-v1_4-go/mock/world"
+)
+
+const addressLength = 32
+
+// DefaultGatewayChainID is the chain ID advertised by the gateway, unless configured otherwise
+const DefaultGatewayChainID = "localnet"
+
+const gatewayQueryGasLimit = 1500000000
+
+var addressConverter, _ = pubkeyConverter.NewBech32PubkeyConverter(addressLength, log)
+
+func decodeBech32Address(address string) ([]byte, error) {
+	return addressConverter.Decode(address)
+}
+
+func encodeBech32Address(address []byte) string {
+	if len(address) != addressLength {
+		return ""
+	}
+
+	return addressConverter.Encode(address)
+}
+
+// fromFrontendTransaction converts a transaction, as sent by the SDKs, to the protocol format
+func fromFrontendTransaction(frontendTx *transaction.FrontendTransaction) (*transaction.Transaction, error) {
+	sender, err := decodeBech32Address(frontendTx.Sender)
+	if err != nil {
+		return nil, NewRequestErrorMessageInner("invalid sender address", err)
+	}
+
+	receiver, err := decodeBech32Address(frontendTx.Receiver)
+	if err != nil {
+		return nil, NewRequestErrorMessageInner("invalid receiver address", err)
+	}
+
+	value, ok := big.NewInt(0).SetString(frontendTx.Value, 10)
+	if !ok || value.Sign() < 0 {
+		return nil, NewRequestError("invalid transaction value")
+	}
+
+	signature, err := hex.DecodeString(frontendTx.Signature)
+	if err != nil {
+		return nil, NewRequestErrorMessageInner("invalid transaction signature", err)
+	}
+
+	if frontendTx.GasLimit == 0 {
+		return nil, NewRequestError("invalid gas limit")
+	}
+
+	return &transaction.Transaction{
+		Nonce:       frontendTx.Nonce,
+		Value:       value,
+		RcvAddr:     receiver,
+		RcvUserName: frontendTx.ReceiverUsername,
+		SndAddr:     sender,
+		SndUserName: frontendTx.SenderUsername,
+		GasPrice:    frontendTx.GasPrice,
+		GasLimit:    frontendTx.GasLimit,
+		Data:        frontendTx.Data,
+		ChainID:     []byte(frontendTx.ChainID),
+		Version:     frontendTx.Version,
+		Signature:   signature,
+		Options:     frontendTx.Options,
+	}, nil
+}
+
+// verifyTransactionSignature checks the signature of the sender, over the same bytes the protocol signs
+func verifyTransactionSignature(tx *transaction.Transaction) error {
+	dataForSigning, err := tx.GetDataForSigning(addressConverter, &marshal.TxJsonMarshalizer{}, keccak.NewKeccak())
+	if err != nil {
+		return err
+	}
+
+	err = ed25519.NewEd25519Signer().VerifyEd25519(tx.SndAddr, dataForSigning, tx.Signature)
+	if err != nil {
+		return NewRequestErrorMessageInner("invalid transaction signature", err)
+	}
+
+	return nil
+}
+
+// computeTransactionHash hashes the transaction the same way the protocol does
+func computeTransactionHash(tx *transaction.Transaction) (string, error) {
+	txBytes, err := (&marshal.GogoProtoMarshalizer{}).Marshal(tx)
+	if err != nil {
+		return "", err
+	}
+
+	return toHex(worldmock.DefaultHasher.Compute(string(txBytes))), nil
+}
+
+// gatewayNetworkConfig mirrors the network configuration of a single-shard chain
+func gatewayNetworkConfig(chainID string) map[string]interface{} {
+	return map[string]interface{}{
+		"erd_chain_id":                    chainID,
+		"erd_denomination":                18,
+		"erd_gas_per_data_byte":           1500,
+		"erd_gas_price_modifier":          "0.01",
+		"erd_latest_tag_software_version": "vmserver",
+		"erd_meta_consensus_group_size":   1,
+		"erd_min_gas_limit":               50000,
+		"erd_min_gas_price":               1000000000,
+		"erd_min_transaction_version":     1,
+		"erd_num_metachain_nodes":         1,
+		"erd_num_nodes_in_shard":          1,
+		"erd_num_shards_without_meta":     1,
+		"erd_round_duration":              DefaultSecondsPerBlock * 1000,
+		"erd_rounds_per_epoch":            14400,
+		"erd_shard_consensus_group_size":  1,
+		"erd_start_time":                  0,
+		"erd_extra_gas_limit_guarded_tx":  50000,
+	}
+}
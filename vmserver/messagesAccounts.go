@@ -14,6 +14,7 @@ type CreateAccountRequest struct {
 	Balance         string
 	BalanceAsBigInt *big.Int
 	Nonce           uint64
+	ESDT            []*AccountESDT
 }
 
 func (request *CreateAccountRequest) digest() error {
@@ -26,7 +27,7 @@ func (request *CreateAccountRequest) digest() error {
 		return NewRequestErrorMessageInner("empty account address", err)
 	}
 
-	request.Address, err = fromHex(request.AddressHex)
+	request.Address, err = decodeExpression(request.AddressHex)
 	if err != nil {
 		return NewRequestErrorMessageInner("invalid account address", err)
 	}
@@ -36,10 +37,18 @@ func (request *CreateAccountRequest) digest() error {
 		return err
 	}
 
+	for _, accountESDT := range request.ESDT {
+		err = accountESDT.digest()
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 // CreateAccountResponse is a CLI / REST response message
 type CreateAccountResponse struct {
+	ResponseBase
 	Account *worldmock.Account
 }
@@ -4,6 +4,7 @@ import (
 	"math/big"
 
 	"github.com/multiversx/mx-chain-vm-common-go"
+	worldmock "github.com/multiversx/mx-chain-vm-v1_4-go/mock/world"
 )
 
 // RequestBase is a CLI / REST request message
@@ -13,6 +14,16 @@ type RequestBase struct {
 	Outcome      string
 }
 
+// worldRequest is a request run on a world of a database
+type worldRequest interface {
+	digest() error
+	getRequestBase() *RequestBase
+}
+
+func (request *RequestBase) getRequestBase() *RequestBase {
+	return request
+}
+
 func (request *RequestBase) digest() error {
 	if request.DatabasePath == "" {
 		request.DatabasePath = "./db"
@@ -30,7 +41,8 @@ type ResponseBase struct {
 	Error error
 }
 
-// ContractRequestBase is a CLI / REST request message
+// ContractRequestBase is a CLI / REST request message; the addresses and the arguments of the contract requests
+// are hex, bech32 or scenario expressions, such as "address:alice", "sc:counter", "str:abc" or "biguint:1000"
 type ContractRequestBase struct {
 	RequestBase
 	ImpersonatedHex string
@@ -39,6 +51,7 @@ type ContractRequestBase struct {
 	ValueAsBigInt   *big.Int
 	GasPrice        uint64
 	GasLimit        uint64
+	ESDTTransfers   []*ESDTTransfer
 }
 
 func (request *ContractRequestBase) digest() error {
@@ -51,7 +64,7 @@ func (request *ContractRequestBase) digest() error {
 		return NewRequestError("empty impersonated address")
 	}
 
-	request.Impersonated, err = fromHex(request.ImpersonatedHex)
+	request.Impersonated, err = decodeExpression(request.ImpersonatedHex)
 	if err != nil {
 		return NewRequestErrorMessageInner("invalid impersonated address", err)
 	}
@@ -69,18 +82,27 @@ func (request *ContractRequestBase) digest() error {
 		return err
 	}
 
+	for _, transfer := range request.ESDTTransfers {
+		err = transfer.digest()
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 // ContractResponseBase is a CLI / REST response message
 type ContractResponseBase struct {
 	ResponseBase
+	TxHash           string
 	Input            *vmcommon.VMInput
 	Output           *vmcommon.VMOutput
 	ReturnCodeString string
+	ReturnDataPretty []string
 }
 
-func createContractResponseBase(input *vmcommon.VMInput, output *vmcommon.VMOutput) ContractResponseBase {
+func createContractResponseBase(input *vmcommon.VMInput, output *vmcommon.VMOutput, accounts worldmock.AccountMap) ContractResponseBase {
 	response := ContractResponseBase{
 		Input:  input,
 		Output: output,
@@ -88,6 +110,7 @@ func createContractResponseBase(input *vmcommon.VMInput, output *vmcommon.VMOutp
 
 	if output != nil {
 		response.ReturnCodeString = output.ReturnCode.String()
+		response.ReturnDataPretty = prettyReturnData(output.ReturnData, accounts)
 	}
 
 	return response
@@ -0,0 +1,101 @@
+package vmserver
+
+import (
+	worldmock "github.com/multiversx/mx-chain-vm-v1_4-go/mock/world"
+)
+
+// DefaultSecondsPerBlock is the timestamp increment used when advancing blocks, unless configured per world
+const DefaultSecondsPerBlock = 6
+
+// BlockInfoRequest holds the block fields to change; the fields left nil keep their value
+type BlockInfoRequest struct {
+	Timestamp     *uint64
+	Nonce         *uint64
+	Round         *uint64
+	Epoch         *uint32
+	RandomSeedHex *string
+	RandomSeed    *[48]byte
+}
+
+func (request *BlockInfoRequest) digest() error {
+	if request.RandomSeedHex == nil {
+		return nil
+	}
+
+	randomSeed, err := fromHex(*request.RandomSeedHex)
+	if err != nil {
+		return NewRequestErrorMessageInner("invalid random seed", err)
+	}
+
+	if len(randomSeed) != len(request.RandomSeed) {
+		return NewRequestError("invalid random seed length, expected 48 bytes")
+	}
+
+	request.RandomSeed = &[48]byte{}
+	copy(request.RandomSeed[:], randomSeed)
+	return nil
+}
+
+// BlockRequest is a CLI / REST request message; the block infos are changed first, then the world advances the given number of blocks
+type BlockRequest struct {
+	RequestBase
+	Current         *BlockInfoRequest
+	Previous        *BlockInfoRequest
+	Advance         uint64
+	AutoAdvance     *bool
+	SecondsPerBlock *uint64
+}
+
+func (request *BlockRequest) digest() error {
+	err := request.RequestBase.digest()
+	if err != nil {
+		return err
+	}
+
+	if request.Current != nil {
+		err = request.Current.digest()
+		if err != nil {
+			return err
+		}
+	}
+
+	if request.Previous != nil {
+		err = request.Previous.digest()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// BlockInfoResponse is the block info of a world, as returned by BlockResponse
+type BlockInfoResponse struct {
+	Timestamp     uint64
+	Nonce         uint64
+	Round         uint64
+	Epoch         uint32
+	RandomSeedHex string
+}
+
+func newBlockInfoResponse(blockInfo *worldmock.BlockInfo) *BlockInfoResponse {
+	if blockInfo == nil {
+		return nil
+	}
+
+	return &BlockInfoResponse{
+		Timestamp:     blockInfo.BlockTimestamp,
+		Nonce:         blockInfo.BlockNonce,
+		Round:         blockInfo.BlockRound,
+		Epoch:         blockInfo.BlockEpoch,
+		RandomSeedHex: toHex(blockInfo.GetRandomSeedSlice()),
+	}
+}
+
+// BlockResponse is a CLI / REST response message
+type BlockResponse struct {
+	Current         *BlockInfoResponse
+	Previous        *BlockInfoResponse
+	AutoAdvance     bool
+	SecondsPerBlock uint64
+}
@@ -0,0 +1,37 @@
+package vmserver
+
+// WorldConfigRequest is a CLI / REST request message; the gas schedule is either an embedded version or a TOML file,
+// and the fields left empty keep the current configuration of the world
+type WorldConfigRequest struct {
+	RequestBase
+	GasSchedule     string
+	GasSchedulePath string
+	EnabledFlags    *[]string
+}
+
+func (request *WorldConfigRequest) digest() error {
+	err := request.RequestBase.digest()
+	if err != nil {
+		return err
+	}
+
+	if len(request.GasSchedule) > 0 && len(request.GasSchedulePath) > 0 {
+		return NewRequestError("gas schedule version and gas schedule path are mutually exclusive")
+	}
+
+	if request.EnabledFlags != nil {
+		err = checkEnabledFlags(*request.EnabledFlags)
+		if err != nil {
+			return NewRequestErrorMessageInner("invalid enabled flags", err)
+		}
+	}
+
+	return nil
+}
+
+// WorldConfigResponse is a CLI / REST response message
+type WorldConfigResponse struct {
+	GasSchedule  string
+	EnabledFlags []string
+	KnownFlags   []string
+}
@@ -0,0 +1,62 @@
+package vmserver
+
+import (
+	"math/big"
+)
+
+// ESDTTransfer is a token transfer attached to a contract request; a nonce greater than 0 denotes an NFT / SFT
+type ESDTTransfer struct {
+	TokenIdentifier string
+	Nonce           uint64
+	Value           string
+	ValueAsBigInt   *big.Int
+}
+
+func (transfer *ESDTTransfer) digest() error {
+	if len(transfer.TokenIdentifier) == 0 {
+		return NewRequestError("empty ESDT token identifier")
+	}
+
+	var err error
+	transfer.ValueAsBigInt, err = parseValue(transfer.Value)
+	if err != nil {
+		return err
+	}
+
+	if transfer.ValueAsBigInt.Sign() <= 0 {
+		return NewRequestError("invalid ESDT value")
+	}
+
+	return nil
+}
+
+// AccountESDT is a token instance held by an account created through CreateAccountRequest;
+// the roles are set on the token, for all its instances, and entries without value only set roles
+type AccountESDT struct {
+	TokenIdentifier string
+	Nonce           uint64
+	Value           string
+	ValueAsBigInt   *big.Int
+	AttributesHex   string
+	Attributes      []byte
+	Roles           []string
+}
+
+func (accountESDT *AccountESDT) digest() error {
+	if len(accountESDT.TokenIdentifier) == 0 {
+		return NewRequestError("empty ESDT token identifier")
+	}
+
+	var err error
+	accountESDT.ValueAsBigInt, err = parseValue(accountESDT.Value)
+	if err != nil {
+		return err
+	}
+
+	accountESDT.Attributes, err = decodeExpression(accountESDT.AttributesHex)
+	if err != nil {
+		return NewRequestErrorMessageInner("invalid ESDT attributes", err)
+	}
+
+	return nil
+}
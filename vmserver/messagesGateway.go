@@ -0,0 +1,203 @@
+package vmserver
+
+import (
+	"encoding/hex"
+	"math/big"
+
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+)
+
+// GatewayAccountRequest is a gateway request message, for /address/:bech32
+type GatewayAccountRequest struct {
+	RequestBase
+	Bech32Address string
+	Address       []byte
+}
+
+func (request *GatewayAccountRequest) digest() error {
+	err := request.RequestBase.digest()
+	if err != nil {
+		return err
+	}
+
+	request.Address, err = decodeBech32Address(request.Bech32Address)
+	if err != nil {
+		return NewRequestErrorMessageInner("invalid address", err)
+	}
+
+	return nil
+}
+
+// GatewayAccount is an account in the format of the gateway
+type GatewayAccount struct {
+	Address         string `json:"address"`
+	Nonce           uint64 `json:"nonce"`
+	Balance         string `json:"balance"`
+	Username        string `json:"username"`
+	Code            string `json:"code"`
+	CodeHash        []byte `json:"codeHash"`
+	RootHash        []byte `json:"rootHash"`
+	CodeMetadata    []byte `json:"codeMetadata"`
+	DeveloperReward string `json:"developerReward"`
+	OwnerAddress    string `json:"ownerAddress"`
+}
+
+// GatewayAccountResponse is a gateway response message
+type GatewayAccountResponse struct {
+	Account *GatewayAccount `json:"account"`
+}
+
+// GatewayStorageRequest is a gateway request message, for /address/:bech32/key/:key
+type GatewayStorageRequest struct {
+	GatewayAccountRequest
+	KeyHex string
+	Key    []byte
+}
+
+func (request *GatewayStorageRequest) digest() error {
+	err := request.GatewayAccountRequest.digest()
+	if err != nil {
+		return err
+	}
+
+	request.Key, err = hex.DecodeString(request.KeyHex)
+	if err != nil {
+		return NewRequestErrorMessageInner("invalid storage key", err)
+	}
+
+	return nil
+}
+
+// GatewayStorageResponse is a gateway response message, the value is hex encoded
+type GatewayStorageResponse struct {
+	Value string `json:"value"`
+}
+
+// GatewaySendTransactionRequest is a gateway request message, for /transaction/send
+type GatewaySendTransactionRequest struct {
+	RequestBase
+	Transaction     transaction.FrontendTransaction
+	CheckSignatures bool
+	ChainID         string
+	parsed          *transaction.Transaction
+}
+
+func (request *GatewaySendTransactionRequest) digest() error {
+	err := request.RequestBase.digest()
+	if err != nil {
+		return err
+	}
+
+	request.parsed, err = fromFrontendTransaction(&request.Transaction)
+	if err != nil {
+		return err
+	}
+
+	if !request.CheckSignatures {
+		return nil
+	}
+
+	if request.Transaction.ChainID != request.ChainID {
+		return NewRequestError("invalid chain ID")
+	}
+
+	return verifyTransactionSignature(request.parsed)
+}
+
+// GatewaySendTransactionResponse is a gateway response message
+type GatewaySendTransactionResponse struct {
+	TxHash string `json:"txHash"`
+}
+
+// GatewayTransactionRequest is a gateway request message, for /transaction/:hash
+type GatewayTransactionRequest struct {
+	RequestBase
+	TxHash string
+}
+
+func (request *GatewayTransactionRequest) digest() error {
+	err := request.RequestBase.digest()
+	if err != nil {
+		return err
+	}
+
+	_, err = hex.DecodeString(request.TxHash)
+	if err != nil || len(request.TxHash) == 0 {
+		return NewRequestError("invalid transaction hash")
+	}
+
+	return nil
+}
+
+// GatewayTransactionResponse is a gateway response message
+type GatewayTransactionResponse struct {
+	Transaction *transaction.ApiTransactionResult `json:"transaction"`
+}
+
+// GatewayTransactionStatusResponse is a gateway response message, for /transaction/:hash/status
+type GatewayTransactionStatusResponse struct {
+	Status string `json:"status"`
+}
+
+// GatewayQueryRequest is a gateway request message, for /vm-values/query
+type GatewayQueryRequest struct {
+	RequestBase
+	ScAddress string   `json:"scAddress"`
+	FuncName  string   `json:"funcName"`
+	Caller    string   `json:"caller"`
+	Value     string   `json:"value"`
+	Args      []string `json:"args"`
+	query     QueryRequest
+}
+
+func (request *GatewayQueryRequest) digest() error {
+	err := request.RequestBase.digest()
+	if err != nil {
+		return err
+	}
+
+	contractAddress, err := decodeBech32Address(request.ScAddress)
+	if err != nil {
+		return NewRequestErrorMessageInner("invalid contract address", err)
+	}
+
+	caller := contractAddress
+	if len(request.Caller) > 0 {
+		caller, err = decodeBech32Address(request.Caller)
+		if err != nil {
+			return NewRequestErrorMessageInner("invalid caller address", err)
+		}
+	}
+
+	request.query = QueryRequest{RunRequest: RunRequest{
+		ContractRequestBase: ContractRequestBase{
+			RequestBase:     request.RequestBase,
+			ImpersonatedHex: toHex(caller),
+			Value:           request.Value,
+			GasLimit:        gatewayQueryGasLimit,
+		},
+		ContractAddressHex: toHex(contractAddress),
+		Function:           request.FuncName,
+		ArgumentsHex:       request.Args,
+	}}
+
+	return request.query.digest()
+}
+
+// GatewayVMOutput is the result of a query, in the format of the gateway
+type GatewayVMOutput struct {
+	ReturnData      [][]byte               `json:"returnData"`
+	ReturnCode      string                 `json:"returnCode"`
+	ReturnMessage   string                 `json:"returnMessage"`
+	GasRemaining    uint64                 `json:"gasRemaining"`
+	GasRefund       *big.Int               `json:"gasRefund"`
+	OutputAccounts  map[string]interface{} `json:"outputAccounts"`
+	DeletedAccounts [][]byte               `json:"deletedAccounts"`
+	TouchedAccounts [][]byte               `json:"touchedAccounts"`
+	Logs            []*transaction.Events  `json:"logs"`
+}
+
+// GatewayQueryResponse is a gateway response message
+type GatewayQueryResponse struct {
+	Data *GatewayVMOutput `json:"data"`
+}
@@ -0,0 +1,98 @@
+package vmserver
+
+import (
+	"encoding/json"
+)
+
+// AccountsRequest is a CLI / REST request message
+type AccountsRequest struct {
+	RequestBase
+}
+
+// AccountsResponse is a CLI / REST response message, the accounts are in the scenario format
+type AccountsResponse struct {
+	Accounts json.RawMessage
+}
+
+// AccountRequest is a CLI / REST request message
+type AccountRequest struct {
+	RequestBase
+	AddressHex string
+	Address    []byte
+}
+
+func (request *AccountRequest) digest() error {
+	err := request.RequestBase.digest()
+	if err != nil {
+		return err
+	}
+
+	if len(request.AddressHex) == 0 {
+		return NewRequestError("empty account address")
+	}
+
+	request.Address, err = decodeExpression(request.AddressHex)
+	if err != nil {
+		return NewRequestErrorMessageInner("invalid account address", err)
+	}
+
+	return nil
+}
+
+// AccountResponse is a CLI / REST response message, the account is in the scenario format
+type AccountResponse struct {
+	Account json.RawMessage
+}
+
+// StorageRequest is a CLI / REST request message; the key is either hex ("0x" prefix optional) or a "str:" string,
+// bare keys that are not valid hex are taken as strings
+type StorageRequest struct {
+	AccountRequest
+	Key      string
+	KeyBytes []byte
+}
+
+func (request *StorageRequest) digest() error {
+	err := request.AccountRequest.digest()
+	if err != nil {
+		return err
+	}
+
+	request.KeyBytes, err = decodeStorageKey(request.Key)
+	if err != nil {
+		return NewRequestErrorMessageInner("invalid storage key", err)
+	}
+
+	return nil
+}
+
+// StorageResponse is a CLI / REST response message, the key and value are scenario expressions
+type StorageResponse struct {
+	Key      string
+	Value    string
+	ValueHex string
+}
+
+// ESDTRequest is a CLI / REST request message
+type ESDTRequest struct {
+	AccountRequest
+	TokenIdentifier string
+}
+
+func (request *ESDTRequest) digest() error {
+	err := request.AccountRequest.digest()
+	if err != nil {
+		return err
+	}
+
+	if len(request.TokenIdentifier) == 0 {
+		return NewRequestError("empty token identifier")
+	}
+
+	return nil
+}
+
+// ESDTResponse is a CLI / REST response message, the token data is in the scenario format
+type ESDTResponse struct {
+	ESDT json.RawMessage
+}
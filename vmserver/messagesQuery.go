@@ -5,6 +5,19 @@ type QueryRequest struct {
 	RunRequest
 }
 
+func (request *QueryRequest) digest() error {
+	err := request.RunRequest.digest()
+	if err != nil {
+		return err
+	}
+
+	if len(request.ESDTTransfers) > 0 {
+		return NewRequestError("queries cannot transfer ESDT tokens")
+	}
+
+	return nil
+}
+
 // QueryResponse is a CLI / REST response message
 type QueryResponse struct {
 	ContractResponseBase
@@ -21,7 +21,7 @@ func (request *RunRequest) digest() error {
 		return err
 	}
 
-	request.ContractAddress, err = fromHex(request.ContractAddressHex)
+	request.ContractAddress, err = decodeExpression(request.ContractAddressHex)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,36 @@
+package vmserver
+
+import (
+	"encoding/json"
+)
+
+// RecordingRequest is a CLI / REST request message
+type RecordingRequest struct {
+	RequestBase
+}
+
+func (request *RecordingRequest) digest() error {
+	return request.RequestBase.digest()
+}
+
+// RecordingResponse is a CLI / REST response message; the recording starts with the given entry of the transaction log
+type RecordingResponse struct {
+	World      string
+	FirstEntry int
+}
+
+// ScenarioRequest is a CLI / REST request message
+type ScenarioRequest struct {
+	RequestBase
+	Name       string
+	CheckState bool
+}
+
+func (request *ScenarioRequest) digest() error {
+	return request.RequestBase.digest()
+}
+
+// ScenarioResponse is a CLI / REST response message, the scenario is in the .scen.json format
+type ScenarioResponse struct {
+	Scenario json.RawMessage
+}
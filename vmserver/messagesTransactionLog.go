@@ -0,0 +1,163 @@
+package vmserver
+
+import (
+	"time"
+)
+
+const (
+	// TransactionKindDeploy marks the entries of the transaction log created by DeploySmartContract
+	TransactionKindDeploy = "deploy"
+	// TransactionKindUpgrade marks the entries of the transaction log created by UpgradeSmartContract
+	TransactionKindUpgrade = "upgrade"
+	// TransactionKindRun marks the entries of the transaction log created by RunSmartContract
+	TransactionKindRun = "run"
+	// TransactionKindQuery marks the entries of the transaction log created by QuerySmartContract
+	TransactionKindQuery = "query"
+	// TransactionKindGateway marks the entries of the transaction log created by the transactions sent through the gateway
+	TransactionKindGateway = "gateway"
+	// TransactionKindCreateAccount marks the entries of the transaction log created by CreateAccount
+	TransactionKindCreateAccount = "createAccount"
+	// TransactionKindWorldConfig marks the entries of the transaction log created by SetWorldConfig
+	TransactionKindWorldConfig = "worldConfig"
+)
+
+// TransactionLogEntry is an executed request, or a change made directly to the world, as kept in the transaction log
+// of a world; bytes are hex encoded
+type TransactionLogEntry struct {
+	TxHash         string
+	Kind           string
+	Timestamp      time.Time
+	BlockNonce     uint64
+	BlockRound     uint64
+	BlockEpoch     uint32
+	BlockTimestamp uint64
+	Input          *TransactionLogInput
+	Output         *TransactionLogOutput
+	Error          string
+	Account        *TransactionLogAccount     `json:",omitempty"`
+	WorldConfig    *TransactionLogWorldConfig `json:",omitempty"`
+}
+
+// TransactionLogInput is the input of an executed request; the code is only set for deployments and upgrades
+type TransactionLogInput struct {
+	Caller        string
+	Contract      string
+	Function      string
+	Arguments     []string
+	Value         string
+	ESDTTransfers []*TransactionLogESDTTransfer
+	GasLimit      uint64
+	GasPrice      uint64
+	Code          string `json:",omitempty"`
+	CodePath      string `json:",omitempty"`
+	CodeMetadata  string `json:",omitempty"`
+}
+
+// TransactionLogESDTTransfer is a token transfer of an executed request
+type TransactionLogESDTTransfer struct {
+	TokenIdentifier string
+	Nonce           uint64
+	Value           string
+}
+
+// TransactionLogOutput holds what an executed request did
+type TransactionLogOutput struct {
+	ReturnCode     string
+	ReturnMessage  string
+	ReturnData     []string
+	GasUsed        uint64
+	Logs           []*TransactionLogEvent
+	Transfers      []*TransactionLogTransfer
+	StorageUpdates []*TransactionLogStorageUpdate
+}
+
+// TransactionLogEvent is an event logged by a contract
+type TransactionLogEvent struct {
+	Address    string
+	Identifier string
+	Topics     []string
+	Data       string
+}
+
+// TransactionLogTransfer is a transfer made by a contract
+type TransactionLogTransfer struct {
+	Sender   string
+	Receiver string
+	Value    string
+	Data     string
+	GasLimit uint64
+	CallType string
+}
+
+// TransactionLogStorageUpdate is a storage change made by a contract
+type TransactionLogStorageUpdate struct {
+	Account string
+	Key     string
+	Value   string
+}
+
+// TransactionLogAccount is an account set by CreateAccount, its tokens are kept in its storage
+type TransactionLogAccount struct {
+	Address string
+	Nonce   uint64
+	Balance string
+	Storage []*TransactionLogStorageUpdate
+}
+
+// TransactionLogWorldConfig is the configuration set by SetWorldConfig
+type TransactionLogWorldConfig struct {
+	GasSchedule  string
+	EnabledFlags []string
+}
+
+// TransactionLogRequest is a CLI / REST request message; the filters left empty match all entries
+type TransactionLogRequest struct {
+	RequestBase
+	ContractHex     string
+	Function        string
+	EventIdentifier string
+}
+
+func (request *TransactionLogRequest) digest() error {
+	err := request.RequestBase.digest()
+	if err != nil {
+		return err
+	}
+
+	contract, err := decodeExpression(request.ContractHex)
+	if err != nil {
+		return NewRequestErrorMessageInner("invalid contract address", err)
+	}
+	request.ContractHex = toHex(contract)
+
+	return nil
+}
+
+// TransactionLogResponse is a CLI / REST response message, the entries are in execution order
+type TransactionLogResponse struct {
+	Entries []*TransactionLogEntry
+}
+
+// TransactionLogEntryRequest is a CLI / REST request message
+type TransactionLogEntryRequest struct {
+	RequestBase
+	TxHash string
+}
+
+func (request *TransactionLogEntryRequest) digest() error {
+	err := request.RequestBase.digest()
+	if err != nil {
+		return err
+	}
+
+	if len(request.TxHash) == 0 {
+		return NewRequestError("empty transaction hash")
+	}
+
+	return nil
+}
+
+// TransactionLogEntryResponse is a CLI / REST response message
+type TransactionLogEntryResponse struct {
+	Entry *TransactionLogEntry
+}
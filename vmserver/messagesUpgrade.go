@@ -13,7 +13,7 @@ func (request *UpgradeRequest) digest() error {
 		return err
 	}
 
-	request.ContractAddress, err = fromHex(request.ContractAddressHex)
+	request.ContractAddress, err = decodeExpression(request.ContractAddressHex)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,93 @@
+package vmserver
+
+import (
+	"strings"
+)
+
+// SnapshotWorldRequest is a CLI / REST request message
+type SnapshotWorldRequest struct {
+	RequestBase
+	Snapshot string
+}
+
+func (request *SnapshotWorldRequest) digest() error {
+	err := request.RequestBase.digest()
+	if err != nil {
+		return err
+	}
+
+	if !isValidFileName(request.Snapshot) {
+		return NewRequestError("invalid snapshot name")
+	}
+
+	return nil
+}
+
+// SnapshotWorldResponse is a CLI / REST response message
+type SnapshotWorldResponse struct {
+	World    string
+	Snapshot string
+}
+
+// ForkWorldRequest is a CLI / REST request message
+type ForkWorldRequest struct {
+	RequestBase
+	NewWorld string
+}
+
+func (request *ForkWorldRequest) digest() error {
+	err := request.RequestBase.digest()
+	if err != nil {
+		return err
+	}
+
+	if !isValidFileName(request.NewWorld) {
+		return NewRequestError("invalid new world name")
+	}
+
+	if request.NewWorld == request.World {
+		return NewRequestError("cannot fork a world onto itself")
+	}
+
+	return nil
+}
+
+// ForkWorldResponse is a CLI / REST response message
+type ForkWorldResponse struct {
+	World    string
+	NewWorld string
+}
+
+// RevertWorldRequest is a CLI / REST request message
+type RevertWorldRequest struct {
+	RequestBase
+	Snapshot string
+}
+
+func (request *RevertWorldRequest) digest() error {
+	err := request.RequestBase.digest()
+	if err != nil {
+		return err
+	}
+
+	if !isValidFileName(request.Snapshot) {
+		return NewRequestError("invalid snapshot name")
+	}
+
+	return nil
+}
+
+// RevertWorldResponse is a CLI / REST response message
+type RevertWorldResponse struct {
+	World    string
+	Snapshot string
+}
+
+// isValidFileName accepts names that map to a single file inside the database folders
+func isValidFileName(name string) bool {
+	if len(name) == 0 || name == "." || name == ".." {
+		return false
+	}
+
+	return !strings.ContainsAny(name, "/\\")
+}
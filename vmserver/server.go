@@ -2,6 +2,7 @@ package vmserver
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -11,6 +12,7 @@ import (
 type DebugServer struct {
 	facade  *DebugFacade
 	address string
+	gateway *ArgsGateway
 }
 
 // NewDebugServer creates a Server object
@@ -32,6 +34,24 @@ func (server *DebugServer) Start() error {
 	router.POST("/upgrade", server.handleUpgrade)
 	router.POST("/run", server.handleRun)
 	router.POST("/query", server.handleQuery)
+	router.POST("/world/block", server.handleSetBlock)
+	router.POST("/world/config", server.handleSetWorldConfig)
+	router.POST("/world/snapshot", server.handleSnapshotWorld)
+	router.POST("/world/fork", server.handleForkWorld)
+	router.POST("/world/revert", server.handleRevertWorld)
+	router.POST("/world/recording", server.handleStartRecording)
+	router.GET("/world/:id/accounts", server.handleGetAccounts)
+	router.GET("/world/:id/account/:address", server.handleGetAccount)
+	router.GET("/world/:id/account/:address/storage/:key", server.handleGetStorage)
+	router.GET("/world/:id/account/:address/esdt/:token", server.handleGetESDT)
+	router.GET("/world/:id/transactions", server.handleGetTransactionLog)
+	router.GET("/world/:id/transactions/:hash", server.handleGetTransactionLogEntry)
+	router.GET("/world/:id/events", server.handleGetEvents)
+	router.GET("/world/:id/scenario", server.handleGetScenario)
+
+	if server.gateway != nil {
+		server.registerGatewayRoutes(router.Group("/gateway/:world"))
+	}
 
 	return router.Run(server.address)
 }
@@ -126,6 +146,267 @@ func (server *DebugServer) handleQuery(ginContext *gin.Context) {
 	returnOkResponse(ginContext, response)
 }
 
+func (server *DebugServer) handleSetBlock(ginContext *gin.Context) {
+	request := BlockRequest{}
+
+	err := ginContext.ShouldBindJSON(&request)
+	if err != nil {
+		returnBadRequest(ginContext, "handleSetBlock.ShouldBindJSON", err)
+		return
+	}
+
+	response, err := server.facade.SetBlock(request)
+	if err != nil {
+		returnBadRequest(ginContext, "handleSetBlock.SetBlock", err)
+		return
+	}
+
+	returnOkResponse(ginContext, response)
+}
+
+func (server *DebugServer) handleSetWorldConfig(ginContext *gin.Context) {
+	request := WorldConfigRequest{}
+
+	err := ginContext.ShouldBindJSON(&request)
+	if err != nil {
+		returnBadRequest(ginContext, "handleSetWorldConfig.ShouldBindJSON", err)
+		return
+	}
+
+	response, err := server.facade.SetWorldConfig(request)
+	if err != nil {
+		returnBadRequest(ginContext, "handleSetWorldConfig.SetWorldConfig", err)
+		return
+	}
+
+	returnOkResponse(ginContext, response)
+}
+
+func (server *DebugServer) handleSnapshotWorld(ginContext *gin.Context) {
+	request := SnapshotWorldRequest{}
+
+	err := ginContext.ShouldBindJSON(&request)
+	if err != nil {
+		returnBadRequest(ginContext, "handleSnapshotWorld.ShouldBindJSON", err)
+		return
+	}
+
+	response, err := server.facade.SnapshotWorld(request)
+	if err != nil {
+		returnBadRequest(ginContext, "handleSnapshotWorld.SnapshotWorld", err)
+		return
+	}
+
+	returnOkResponse(ginContext, response)
+}
+
+func (server *DebugServer) handleForkWorld(ginContext *gin.Context) {
+	request := ForkWorldRequest{}
+
+	err := ginContext.ShouldBindJSON(&request)
+	if err != nil {
+		returnBadRequest(ginContext, "handleForkWorld.ShouldBindJSON", err)
+		return
+	}
+
+	response, err := server.facade.ForkWorld(request)
+	if err != nil {
+		returnBadRequest(ginContext, "handleForkWorld.ForkWorld", err)
+		return
+	}
+
+	returnOkResponse(ginContext, response)
+}
+
+func (server *DebugServer) handleRevertWorld(ginContext *gin.Context) {
+	request := RevertWorldRequest{}
+
+	err := ginContext.ShouldBindJSON(&request)
+	if err != nil {
+		returnBadRequest(ginContext, "handleRevertWorld.ShouldBindJSON", err)
+		return
+	}
+
+	response, err := server.facade.RevertWorld(request)
+	if err != nil {
+		returnBadRequest(ginContext, "handleRevertWorld.RevertWorld", err)
+		return
+	}
+
+	returnOkResponse(ginContext, response)
+}
+
+func (server *DebugServer) handleGetAccounts(ginContext *gin.Context) {
+	request := AccountsRequest{
+		RequestBase: getRequestBaseFromPath(ginContext),
+	}
+
+	response, err := server.facade.GetAccounts(request)
+	if err != nil {
+		returnBadRequest(ginContext, "handleGetAccounts.GetAccounts", err)
+		return
+	}
+
+	returnOkResponse(ginContext, response)
+}
+
+func (server *DebugServer) handleGetAccount(ginContext *gin.Context) {
+	request := getAccountRequestFromPath(ginContext)
+
+	response, err := server.facade.GetAccount(request)
+	if err != nil {
+		returnBadRequest(ginContext, "handleGetAccount.GetAccount", err)
+		return
+	}
+
+	returnOkResponse(ginContext, response)
+}
+
+func (server *DebugServer) handleGetStorage(ginContext *gin.Context) {
+	request := StorageRequest{
+		AccountRequest: getAccountRequestFromPath(ginContext),
+		Key:            ginContext.Param("key"),
+	}
+
+	response, err := server.facade.GetStorage(request)
+	if err != nil {
+		returnBadRequest(ginContext, "handleGetStorage.GetStorage", err)
+		return
+	}
+
+	returnOkResponse(ginContext, response)
+}
+
+func (server *DebugServer) handleGetESDT(ginContext *gin.Context) {
+	request := ESDTRequest{
+		AccountRequest:  getAccountRequestFromPath(ginContext),
+		TokenIdentifier: ginContext.Param("token"),
+	}
+
+	response, err := server.facade.GetESDT(request)
+	if err != nil {
+		returnBadRequest(ginContext, "handleGetESDT.GetESDT", err)
+		return
+	}
+
+	returnOkResponse(ginContext, response)
+}
+
+func (server *DebugServer) handleGetTransactionLog(ginContext *gin.Context) {
+	request := TransactionLogRequest{
+		RequestBase:     getRequestBaseFromPath(ginContext),
+		ContractHex:     ginContext.Query("contract"),
+		Function:        ginContext.Query("function"),
+		EventIdentifier: ginContext.Query("event"),
+	}
+
+	response, err := server.facade.GetTransactionLog(request)
+	if err != nil {
+		returnBadRequest(ginContext, "handleGetTransactionLog.GetTransactionLog", err)
+		return
+	}
+
+	returnOkResponse(ginContext, response)
+}
+
+func (server *DebugServer) handleGetTransactionLogEntry(ginContext *gin.Context) {
+	request := TransactionLogEntryRequest{
+		RequestBase: getRequestBaseFromPath(ginContext),
+		TxHash:      ginContext.Param("hash"),
+	}
+
+	response, err := server.facade.GetTransactionLogEntry(request)
+	if err != nil {
+		returnBadRequest(ginContext, "handleGetTransactionLogEntry.GetTransactionLogEntry", err)
+		return
+	}
+
+	returnOkResponse(ginContext, response)
+}
+
+// handleGetEvents streams the requests executed on the world as server-sent events, until the client goes away
+func (server *DebugServer) handleGetEvents(ginContext *gin.Context) {
+	request := TransactionLogRequest{
+		RequestBase:     getRequestBaseFromPath(ginContext),
+		ContractHex:     ginContext.Query("contract"),
+		Function:        ginContext.Query("function"),
+		EventIdentifier: ginContext.Query("event"),
+	}
+
+	subscription, err := server.facade.SubscribeEvents(request)
+	if err != nil {
+		returnBadRequest(ginContext, "handleGetEvents.SubscribeEvents", err)
+		return
+	}
+	defer subscription.Close()
+
+	// lets the client know that it will not miss the requests sent from now on
+	ginContext.SSEvent("subscribed", request.World)
+	ginContext.Writer.Flush()
+
+	ginContext.Stream(func(_ io.Writer) bool {
+		select {
+		case entry, ok := <-subscription.Entries():
+			if !ok {
+				return false
+			}
+			ginContext.SSEvent("transaction", entry)
+			return true
+		case <-ginContext.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+func (server *DebugServer) handleStartRecording(ginContext *gin.Context) {
+	request := RecordingRequest{}
+
+	err := ginContext.ShouldBindJSON(&request)
+	if err != nil {
+		returnBadRequest(ginContext, "handleStartRecording.ShouldBindJSON", err)
+		return
+	}
+
+	response, err := server.facade.StartRecording(request)
+	if err != nil {
+		returnBadRequest(ginContext, "handleStartRecording.StartRecording", err)
+		return
+	}
+
+	returnOkResponse(ginContext, response)
+}
+
+func (server *DebugServer) handleGetScenario(ginContext *gin.Context) {
+	request := ScenarioRequest{
+		RequestBase: getRequestBaseFromPath(ginContext),
+		Name:        ginContext.Query("name"),
+		CheckState:  ginContext.Query("checkState") == "true",
+	}
+
+	response, err := server.facade.ExportScenario(request)
+	if err != nil {
+		returnBadRequest(ginContext, "handleGetScenario.ExportScenario", err)
+		return
+	}
+
+	returnOkResponse(ginContext, response)
+}
+
+// getRequestBaseFromPath reads the world from the path and the (optional) database from the query string
+func getRequestBaseFromPath(ginContext *gin.Context) RequestBase {
+	return RequestBase{
+		DatabasePath: ginContext.Query("database"),
+		World:        ginContext.Param("id"),
+	}
+}
+
+func getAccountRequestFromPath(ginContext *gin.Context) AccountRequest {
+	return AccountRequest{
+		RequestBase: getRequestBaseFromPath(ginContext),
+		AddressHex:  ginContext.Param("address"),
+	}
+}
+
 func returnBadRequest(context *gin.Context, errScope string, err error) {
 	context.JSON(http.StatusBadRequest, gin.H{
 		"error":        fmt.Sprintf("%T", err),
@@ -0,0 +1,172 @@
+package vmserver
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ArgsGateway holds the arguments of the gateway-compatible routes, served under /gateway/:world
+type ArgsGateway struct {
+	DatabasePath    string
+	ChainID         string
+	CheckSignatures bool
+}
+
+// EnableGateway serves a subset of the gateway (proxy) API, so that the SDKs can be pointed to a debug world
+func (server *DebugServer) EnableGateway(args ArgsGateway) {
+	if len(args.ChainID) == 0 {
+		args.ChainID = DefaultGatewayChainID
+	}
+
+	server.gateway = &args
+}
+
+func (server *DebugServer) registerGatewayRoutes(routes *gin.RouterGroup) {
+	routes.GET("/address/:address", server.handleGatewayGetAccount)
+	routes.GET("/address/:address/key/:key", server.handleGatewayGetStorage)
+	routes.POST("/transaction/send", server.handleGatewaySendTransaction)
+	routes.GET("/transaction/:hash", server.handleGatewayGetTransaction)
+	routes.GET("/transaction/:hash/status", server.handleGatewayGetTransactionStatus)
+	routes.GET("/transaction/:hash/process-status", server.handleGatewayGetTransactionStatus)
+	routes.POST("/vm-values/query", server.handleGatewayQuery)
+	routes.GET("/network/config", server.handleGatewayNetworkConfig)
+}
+
+func (server *DebugServer) handleGatewayGetAccount(ginContext *gin.Context) {
+	request := server.getGatewayAccountRequest(ginContext)
+
+	response, err := server.facade.GatewayGetAccount(request)
+	if err != nil {
+		returnGatewayError(ginContext, err)
+		return
+	}
+
+	returnGatewayResponse(ginContext, response)
+}
+
+func (server *DebugServer) handleGatewayGetStorage(ginContext *gin.Context) {
+	request := GatewayStorageRequest{
+		GatewayAccountRequest: server.getGatewayAccountRequest(ginContext),
+		KeyHex:                ginContext.Param("key"),
+	}
+
+	response, err := server.facade.GatewayGetStorage(request)
+	if err != nil {
+		returnGatewayError(ginContext, err)
+		return
+	}
+
+	returnGatewayResponse(ginContext, response)
+}
+
+func (server *DebugServer) handleGatewaySendTransaction(ginContext *gin.Context) {
+	request := GatewaySendTransactionRequest{
+		RequestBase:     server.getGatewayRequestBase(ginContext),
+		CheckSignatures: server.gateway.CheckSignatures,
+		ChainID:         server.gateway.ChainID,
+	}
+
+	err := ginContext.ShouldBindJSON(&request.Transaction)
+	if err != nil {
+		returnGatewayError(ginContext, err)
+		return
+	}
+
+	response, err := server.facade.GatewaySendTransaction(request)
+	if err != nil {
+		returnGatewayError(ginContext, err)
+		return
+	}
+
+	returnGatewayResponse(ginContext, response)
+}
+
+func (server *DebugServer) handleGatewayGetTransaction(ginContext *gin.Context) {
+	response, err := server.facade.GatewayGetTransaction(server.getGatewayTransactionRequest(ginContext))
+	if err != nil {
+		returnGatewayError(ginContext, err)
+		return
+	}
+
+	returnGatewayResponse(ginContext, response)
+}
+
+func (server *DebugServer) handleGatewayGetTransactionStatus(ginContext *gin.Context) {
+	response, err := server.facade.GatewayGetTransaction(server.getGatewayTransactionRequest(ginContext))
+	if err != nil {
+		returnGatewayError(ginContext, err)
+		return
+	}
+
+	returnGatewayResponse(ginContext, &GatewayTransactionStatusResponse{Status: string(response.Transaction.Status)})
+}
+
+func (server *DebugServer) handleGatewayQuery(ginContext *gin.Context) {
+	request := GatewayQueryRequest{}
+
+	err := ginContext.ShouldBindJSON(&request)
+	if err != nil {
+		returnGatewayError(ginContext, err)
+		return
+	}
+
+	request.RequestBase = server.getGatewayRequestBase(ginContext)
+	response, err := server.facade.GatewayQuery(request)
+	if err != nil {
+		returnGatewayError(ginContext, err)
+		return
+	}
+
+	returnGatewayResponse(ginContext, response)
+}
+
+func (server *DebugServer) handleGatewayNetworkConfig(ginContext *gin.Context) {
+	returnGatewayResponse(ginContext, gin.H{
+		"config": gatewayNetworkConfig(server.gateway.ChainID),
+	})
+}
+
+func (server *DebugServer) getGatewayRequestBase(ginContext *gin.Context) RequestBase {
+	return RequestBase{
+		DatabasePath: server.gateway.DatabasePath,
+		World:        ginContext.Param("world"),
+	}
+}
+
+func (server *DebugServer) getGatewayAccountRequest(ginContext *gin.Context) GatewayAccountRequest {
+	return GatewayAccountRequest{
+		RequestBase:   server.getGatewayRequestBase(ginContext),
+		Bech32Address: ginContext.Param("address"),
+	}
+}
+
+func (server *DebugServer) getGatewayTransactionRequest(ginContext *gin.Context) GatewayTransactionRequest {
+	return GatewayTransactionRequest{
+		RequestBase: server.getGatewayRequestBase(ginContext),
+		TxHash:      ginContext.Param("hash"),
+	}
+}
+
+// returnGatewayError answers in the format of the gateway, which the SDKs read the error message from
+func returnGatewayError(context *gin.Context, err error) {
+	status := http.StatusBadRequest
+	if errors.Is(err, ErrTransactionNotFound) {
+		status = http.StatusNotFound
+	}
+
+	context.JSON(status, gin.H{
+		"data":  nil,
+		"error": err.Error(),
+		"code":  "bad_request",
+	})
+}
+
+func returnGatewayResponse(context *gin.Context, data interface{}) {
+	context.JSON(http.StatusOK, gin.H{
+		"data":  data,
+		"error": "",
+		"code":  "successful",
+	})
+}
@@ -3,7 +3,7 @@ package vmserver
 import (
 	"fmt"
 	"math/big"
-	"math/rand"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -14,6 +14,9 @@ import (
 
 const gasLimit = 50000000
 
+// worldCounter keeps the worlds of the tests apart, even when created within the same second
+var worldCounter uint64
+
 type testContext struct {
 	t       *testing.T
 	worldID string
@@ -21,7 +24,7 @@ type testContext struct {
 }
 
 func newTestContext(t *testing.T) *testContext {
-	worldID := fmt.Sprintf("%s_%d", time.Now().Format("20060102150405"), rand.Intn(100))
+	worldID := fmt.Sprintf("%s_%d", time.Now().Format("20060102150405"), atomic.AddUint64(&worldCounter, 1))
 
 	return &testContext{
 		t:       t,
@@ -45,6 +48,70 @@ func (context *testContext) createAccount(address string, balance string) {
 	require.NotNil(t, response)
 }
 
+func (context *testContext) createAccountWithESDT(address string, balance string, accountESDT ...*AccountESDT) {
+	request := CreateAccountRequest{
+		RequestBase: context.createRequestBase(),
+		AddressHex:  address,
+		Balance:     balance,
+		ESDT:        accountESDT,
+	}
+
+	response, err := context.facade.CreateAccount(request)
+
+	t := context.t
+	require.Nil(t, err)
+	require.NotNil(t, response)
+	require.Nil(t, response.Error)
+}
+
+func (context *testContext) getTokenBalance(address []byte, tokenIdentifier string, nonce uint64) int64 {
+	world := context.loadWorld()
+	balance, err := world.blockchainHook.BuiltinFuncs.GetTokenBalance(address, []byte(tokenIdentifier), nonce)
+	require.Nil(context.t, err)
+
+	return balance.Int64()
+}
+
+func (context *testContext) snapshotWorld(snapshot string) {
+	request := SnapshotWorldRequest{
+		RequestBase: context.createRequestBase(),
+		Snapshot:    snapshot,
+	}
+
+	response, err := context.facade.SnapshotWorld(request)
+
+	t := context.t
+	require.Nil(t, err)
+	require.NotNil(t, response)
+}
+
+func (context *testContext) forkWorld() *testContext {
+	forked := newTestContext(context.t)
+	forked.worldID = context.worldID + "_fork"
+	request := ForkWorldRequest{
+		RequestBase: context.createRequestBase(),
+		NewWorld:    forked.worldID,
+	}
+
+	response, err := context.facade.ForkWorld(request)
+
+	t := context.t
+	require.Nil(t, err)
+	require.NotNil(t, response)
+
+	return forked
+}
+
+func (context *testContext) revertWorld(snapshot string) error {
+	request := RevertWorldRequest{
+		RequestBase: context.createRequestBase(),
+		Snapshot:    snapshot,
+	}
+
+	_, err := context.facade.RevertWorld(request)
+	return err
+}
+
 func (context *testContext) accountExists(address []byte) bool {
 	world := context.loadWorld()
 	account, err := world.blockchainHook.GetUserAccount(address)
@@ -161,7 +228,7 @@ func (response *ContractResponseBase) getFirstResultAsInt64() int64 {
 }
 
 func (context *testContext) createRequestBase() RequestBase {
-	randomOutcome := fmt.Sprintf("%s_%d", time.Now().Format("20060102150405"), rand.Intn(100))
+	randomOutcome := fmt.Sprintf("%s_%d", time.Now().Format("20060102150405"), atomic.AddUint64(&worldCounter, 1))
 
 	return RequestBase{
 		DatabasePath: databasePath,
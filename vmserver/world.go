@@ -2,66 +2,140 @@ package vmserver
 
 import (
 	"math/big"
+	"sync"
 
+	"github.com/multiversx/mx-chain-core-go/data/vm"
 	vmcommon "github.com/multiversx/mx-chain-vm-common-go"
-	"github.com/multiversx/mx-chain-vm-common-go/builtInFunctions"
 	"github.com/multiversx/mx-chain-vm-common-go/parsers"
 	"github.com/multiversx/mx-chain-vm-v1_4-go/config"
 	worldmock "github.com/multiversx/mx-chain-vm-v1_4-go/mock/world"
+	"github.com/multiversx/mx-chain-vm-v1_4-go/scenarios/esdtconvert"
+	mj "github.com/multiversx/mx-chain-vm-v1_4-go/scenarios/model"
 	"github.com/multiversx/mx-chain-vm-v1_4-go/vmhost"
 	"github.com/multiversx/mx-chain-vm-v1_4-go/vmhost/hostCore"
 	"github.com/multiversx/mx-chain-vm-v1_4-go/vmhost/mock"
 )
 
+var vmType = []byte{5, 0}
+
+// vmHostMutex is held from the creation of a world VM host until the host is closed, since each host sets the wasmer
+// imports and the opcode costs of its gas schedule process-wide, so that two worlds cannot execute at the same time
+var vmHostMutex sync.Mutex
+
 type worldDataModel struct {
-	ID       string
-	Accounts worldmock.AccountMap
+	ID                string
+	Accounts          worldmock.AccountMap
+	CurrentBlockInfo  *worldmock.BlockInfo
+	PreviousBlockInfo *worldmock.BlockInfo
+	AutoAdvanceBlock  bool
+	SecondsPerBlock   uint64
+	GasScheduleName   string
+	GasSchedule       config.GasScheduleMap
+	EnabledFlags      []string
+	// changes are not stored; they are nil for worlds that are written as a whole
+	changes *worldChanges
 }
 
 type world struct {
-	id             string
-	blockchainHook *worldmock.MockWorld
-	vm             vmcommon.VMExecutionHandler
+	id               string
+	blockchainHook   *worldmock.MockWorld
+	vm               vmcommon.VMExecutionHandler
+	autoAdvanceBlock bool
+	secondsPerBlock  uint64
+	gasScheduleName  string
+	gasSchedule      config.GasScheduleMap
+	enabledFlags     []string
+	changes          *worldChanges
 }
 
 func newWorldDataModel(worldID string) *worldDataModel {
 	return &worldDataModel{
-		ID:       worldID,
-		Accounts: worldmock.NewAccountMap(),
+		ID:              worldID,
+		Accounts:        worldmock.NewAccountMap(),
+		SecondsPerBlock: DefaultSecondsPerBlock,
+	}
+}
+
+// clone copies the parts of the data model that a world changes in place
+func (dataModel *worldDataModel) clone() *worldDataModel {
+	clone := *dataModel
+	clone.Accounts = dataModel.Accounts.Clone()
+	clone.CurrentBlockInfo = cloneBlockInfo(dataModel.CurrentBlockInfo)
+	clone.PreviousBlockInfo = cloneBlockInfo(dataModel.PreviousBlockInfo)
+	clone.changes = dataModel.changes.clone()
+	return &clone
+}
+
+func cloneBlockInfo(blockInfo *worldmock.BlockInfo) *worldmock.BlockInfo {
+	if blockInfo == nil {
+		return nil
 	}
+
+	clone := *blockInfo
+	return &clone
 }
 
 // newWorld creates a new debugging world
 func newWorld(dataModel *worldDataModel) (*world, error) {
 	blockchainHook := worldmock.NewMockWorld()
 	blockchainHook.AcctMap = dataModel.Accounts
+	blockchainHook.CurrentBlockInfo = dataModel.CurrentBlockInfo
+	blockchainHook.PreviousBlockInfo = dataModel.PreviousBlockInfo
+	for _, account := range blockchainHook.AcctMap {
+		account.MockWorld = blockchainHook
+	}
+
+	gasSchedule, err := resolveGasSchedule(dataModel.GasScheduleName, dataModel.GasSchedule)
+	if err != nil {
+		return nil, err
+	}
+
+	enableEpochsHandler, err := newEnableEpochsHandler(dataModel.EnabledFlags)
+	if err != nil {
+		return nil, err
+	}
+
+	err = blockchainHook.InitBuiltinFunctions(gasSchedule)
+	if err != nil {
+		return nil, err
+	}
 
 	vm, err := hostCore.NewVMHost(
 		blockchainHook,
-		getHostParameters(),
+		getHostParameters(gasSchedule, blockchainHook.BuiltinFuncs.Container, enableEpochsHandler),
 	)
 	if err != nil {
 		return nil, err
 	}
 
 	return &world{
-		id:             dataModel.ID,
-		blockchainHook: blockchainHook,
-		vm:             vm,
+		id:               dataModel.ID,
+		blockchainHook:   blockchainHook,
+		vm:               vm,
+		autoAdvanceBlock: dataModel.AutoAdvanceBlock,
+		secondsPerBlock:  dataModel.SecondsPerBlock,
+		gasScheduleName:  dataModel.GasScheduleName,
+		gasSchedule:      dataModel.GasSchedule,
+		enabledFlags:     dataModel.EnabledFlags,
+		changes:          dataModel.changes.clone(),
 	}, nil
 }
 
-func getHostParameters() *vmhost.VMHostParameters {
+func getHostParameters(
+	gasSchedule config.GasScheduleMap,
+	builtInFuncContainer vmcommon.BuiltInFunctionContainer,
+	enableEpochsHandler vmcommon.EnableEpochsHandler,
+) *vmhost.VMHostParameters {
 	esdtTransferParser, _ := parsers.NewESDTTransferParser(worldmock.WorldMarshalizer)
 	return &vmhost.VMHostParameters{
-		VMType:                   []byte{5, 0},
+		VMType:                   vmType,
 		BlockGasLimit:            uint64(10000000),
-		GasSchedule:              config.MakeGasMap(1, 1),
+		GasSchedule:              gasSchedule,
 		ProtectedKeyPrefix:       []byte("E" + "L" + "R" + "O" + "N" + "D"),
-		BuiltInFuncContainer:     builtInFunctions.NewBuiltInFunctionContainer(),
+		BuiltInFuncContainer:     builtInFuncContainer,
 		ESDTTransferParser:       esdtTransferParser,
 		EpochNotifier:            &mock.EpochNotifierStub{},
-		EnableEpochsHandler:      &mock.EnableEpochsHandlerStub{},
+		EnableEpochsHandler:      enableEpochsHandler,
 		WasmerSIGSEGVPassthrough: false,
 		Hasher:                   worldmock.DefaultHasher,
 	}
@@ -71,13 +145,21 @@ func (w *world) deploySmartContract(request DeployRequest) *DeployResponse {
 	input := w.prepareDeployInput(request)
 	log.Trace("w.deploySmartContract()", "input", prettyJson(input))
 
-	vmOutput, err := w.vm.RunSmartContractCreate(input)
+	contractAddress, err := w.nextContractAddress(request.Impersonated)
+	if err != nil {
+		return &DeployResponse{ContractResponseBase: ContractResponseBase{ResponseBase: ResponseBase{Error: err}}}
+	}
+
+	vmOutput, err := w.runWithESDTTransfers(contractAddress, &request.ContractRequestBase, &input.VMInput, func() (*vmcommon.VMOutput, error) {
+		return w.vm.RunSmartContractCreate(input)
+	})
 	if err == nil {
 		_ = w.blockchainHook.UpdateAccounts(vmOutput.OutputAccounts, nil)
+		w.changes.markOutputAccounts(vmOutput)
 	}
 
 	response := &DeployResponse{}
-	response.ContractResponseBase = createContractResponseBase(&input.VMInput, vmOutput)
+	response.ContractResponseBase = createContractResponseBase(&input.VMInput, vmOutput, w.blockchainHook.AcctMap)
 	response.Error = err
 	response.ContractAddress = w.blockchainHook.LastCreatedContractAddress
 	response.ContractAddressHex = toHex(response.ContractAddress)
@@ -88,34 +170,125 @@ func (w *world) upgradeSmartContract(request UpgradeRequest) *UpgradeResponse {
 	input := w.prepareUpgradeInput(request)
 	log.Trace("w.upgradeSmartContract()", "input", prettyJson(input))
 
-	vmOutput, err := w.vm.RunSmartContractCall(input)
+	vmOutput, err := w.runWithESDTTransfers(request.ContractAddress, &request.ContractRequestBase, &input.VMInput, func() (*vmcommon.VMOutput, error) {
+		return w.vm.RunSmartContractCall(input)
+	})
 	if err == nil {
 		_ = w.blockchainHook.UpdateAccounts(vmOutput.OutputAccounts, nil)
+		w.changes.markOutputAccounts(vmOutput)
 	}
 
 	response := &UpgradeResponse{}
-	response.ContractResponseBase = createContractResponseBase(&input.VMInput, vmOutput)
+	response.ContractResponseBase = createContractResponseBase(&input.VMInput, vmOutput, w.blockchainHook.AcctMap)
 	response.Error = err
 
 	return response
 }
 
 func (w *world) runSmartContract(request RunRequest) *RunResponse {
+	if w.autoAdvanceBlock {
+		w.advanceBlocks(1)
+	}
+
+	return w.callSmartContract(request)
+}
+
+// callSmartContract runs a contract call in the current block
+func (w *world) callSmartContract(request RunRequest) *RunResponse {
 	input := w.prepareCallInput(request)
-	log.Trace("w.runSmartContract()", "input", prettyJson(input))
+	log.Trace("w.callSmartContract()", "input", prettyJson(input))
 
-	vmOutput, err := w.vm.RunSmartContractCall(input)
+	vmOutput, err := w.runWithESDTTransfers(request.ContractAddress, &request.ContractRequestBase, &input.VMInput, func() (*vmcommon.VMOutput, error) {
+		return w.vm.RunSmartContractCall(input)
+	})
 	if err == nil {
 		_ = w.blockchainHook.UpdateAccounts(vmOutput.OutputAccounts, nil)
+		w.changes.markOutputAccounts(vmOutput)
 	}
 
 	response := &RunResponse{}
-	response.ContractResponseBase = createContractResponseBase(&input.VMInput, vmOutput)
+	response.ContractResponseBase = createContractResponseBase(&input.VMInput, vmOutput, w.blockchainHook.AcctMap)
 	response.Error = err
 
 	return response
 }
 
+// runWithESDTTransfers moves the tokens of the request to the receiver through the builtin functions,
+// the same way scenarios handle esdtValue, then runs the contract with the gas left;
+// the transfers are rolled back if the execution does not succeed
+func (w *world) runWithESDTTransfers(
+	receiver []byte,
+	request *ContractRequestBase,
+	input *vmcommon.VMInput,
+	run func() (*vmcommon.VMOutput, error),
+) (*vmcommon.VMOutput, error) {
+	if len(request.ESDTTransfers) == 0 {
+		return run()
+	}
+
+	w.blockchainHook.CreateStateBackup()
+	w.changes.markTokenTransfer(request.Impersonated, receiver)
+
+	gasRemaining, err := w.performESDTTransfers(receiver, request)
+	if err != nil {
+		_ = w.blockchainHook.RollbackChanges()
+		return nil, err
+	}
+
+	input.GasProvided = gasRemaining
+	vmOutput, err := run()
+	if err != nil || vmOutput.ReturnCode != vmcommon.Ok {
+		_ = w.blockchainHook.RollbackChanges()
+		return vmOutput, err
+	}
+
+	return vmOutput, w.blockchainHook.CommitChanges()
+}
+
+func (w *world) performESDTTransfers(receiver []byte, request *ContractRequestBase) (uint64, error) {
+	if len(request.ESDTTransfers) == 1 {
+		transfer := request.ESDTTransfers[0]
+		return w.blockchainHook.BuiltinFuncs.PerformDirectESDTTransfer(
+			request.Impersonated,
+			receiver,
+			[]byte(transfer.TokenIdentifier),
+			transfer.Nonce,
+			transfer.ValueAsBigInt,
+			vm.DirectCall,
+			request.GasLimit,
+			request.GasPrice)
+	}
+
+	esdtTransfers := make([]*mj.ESDTTxData, len(request.ESDTTransfers))
+	for i, transfer := range request.ESDTTransfers {
+		esdtTransfers[i] = &mj.ESDTTxData{
+			TokenIdentifier: mj.JSONBytesFromString{Value: []byte(transfer.TokenIdentifier)},
+			Nonce:           mj.JSONUint64{Value: transfer.Nonce},
+			Value:           mj.JSONBigInt{Value: transfer.ValueAsBigInt},
+		}
+	}
+
+	return w.blockchainHook.BuiltinFuncs.PerformDirectMultiESDTTransfer(
+		request.Impersonated,
+		receiver,
+		esdtTransfers,
+		vm.DirectCall,
+		request.GasLimit,
+		request.GasPrice)
+}
+
+// nextContractAddress returns the address of the contract that the given account would deploy next,
+// computed the same way the VM does for direct deployments
+func (w *world) nextContractAddress(creator []byte) ([]byte, error) {
+	nonce := uint64(0)
+	account := w.blockchainHook.AcctMap.GetAccount(creator)
+	if account != nil && account.Nonce > 0 {
+		nonce = account.Nonce - 1
+	}
+
+	return w.blockchainHook.NewAddress(creator, nonce, vmType)
+}
+
 func (w *world) querySmartContract(request QueryRequest) *QueryResponse {
 	input := w.prepareCallInput(request.RunRequest)
 	log.Trace("w.querySmartContract()", "input", prettyJson(input))
@@ -123,7 +296,7 @@ func (w *world) querySmartContract(request QueryRequest) *QueryResponse {
 	vmOutput, err := w.vm.RunSmartContractCall(input)
 
 	response := &QueryResponse{}
-	response.ContractResponseBase = createContractResponseBase(&input.VMInput, vmOutput)
+	response.ContractResponseBase = createContractResponseBase(&input.VMInput, vmOutput, w.blockchainHook.AcctMap)
 	response.Error = err
 
 	return response
@@ -138,9 +311,52 @@ func (w *world) createAccount(request CreateAccountRequest) *CreateAccountRespon
 		Balance:         request.BalanceAsBigInt,
 		BalanceDelta:    big.NewInt(0),
 		DeveloperReward: big.NewInt(0),
+		Storage:         make(map[string][]byte),
+		MockWorld:       w.blockchainHook,
+	}
+
+	err := esdtconvert.WriteScenariosESDTToStorage(groupAccountESDT(request.ESDT), account.Storage)
+	if err != nil {
+		return &CreateAccountResponse{ResponseBase: ResponseBase{Error: err}}
 	}
+
 	w.blockchainHook.AcctMap.PutAccount(&account)
-	return &CreateAccountResponse{Account: &account}
+	w.changes.account(request.Address).allStorage = true
+
+	accountCopy := account.Clone()
+	accountCopy.MockWorld = nil
+	return &CreateAccountResponse{Account: accountCopy}
+}
+
+// groupAccountESDT converts the token instances of a CreateAccountRequest to the scenario format, grouped by token
+func groupAccountESDT(accountESDT []*AccountESDT) []*mj.ESDTData {
+	var tokens []*mj.ESDTData
+	tokensByIdentifier := make(map[string]*mj.ESDTData)
+	for _, instance := range accountESDT {
+		token, exists := tokensByIdentifier[instance.TokenIdentifier]
+		if !exists {
+			token = &mj.ESDTData{
+				TokenIdentifier: mj.JSONBytesFromString{Value: []byte(instance.TokenIdentifier)},
+			}
+			tokensByIdentifier[instance.TokenIdentifier] = token
+			tokens = append(tokens, token)
+		}
+
+		// entries without value only set roles
+		if instance.ValueAsBigInt.Sign() > 0 {
+			token.Instances = append(token.Instances, &mj.ESDTInstance{
+				Nonce:      mj.JSONUint64{Value: instance.Nonce},
+				Balance:    mj.JSONBigInt{Value: instance.ValueAsBigInt},
+				Attributes: mj.JSONBytesFromString{Value: instance.Attributes},
+			})
+		}
+		token.Roles = append(token.Roles, instance.Roles...)
+		if instance.Nonce > token.LastNonce.Value {
+			token.LastNonce = mj.JSONUint64{Value: instance.Nonce}
+		}
+	}
+
+	return tokens
 }
 
 func (w *world) toDataModel() *worldDataModel {
@@ -150,7 +366,15 @@ func (w *world) toDataModel() *worldDataModel {
 	}
 
 	return &worldDataModel{
-		ID:       w.id,
-		Accounts: accounts,
+		ID:                w.id,
+		Accounts:          accounts,
+		CurrentBlockInfo:  w.blockchainHook.CurrentBlockInfo,
+		PreviousBlockInfo: w.blockchainHook.PreviousBlockInfo,
+		AutoAdvanceBlock:  w.autoAdvanceBlock,
+		SecondsPerBlock:   w.secondsPerBlock,
+		GasScheduleName:   w.gasScheduleName,
+		GasSchedule:       w.gasSchedule,
+		EnabledFlags:      w.enabledFlags,
+		changes:           w.changes,
 	}
 }
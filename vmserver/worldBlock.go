@@ -0,0 +1,86 @@
+package vmserver
+
+import (
+	worldmock "github.com/multiversx/mx-chain-vm-v1_4-go/mock/world"
+)
+
+func (w *world) setBlock(request BlockRequest) *BlockResponse {
+	log.Trace("w.setBlock()", "request", prettyJson(request))
+
+	if request.SecondsPerBlock != nil {
+		w.secondsPerBlock = *request.SecondsPerBlock
+	}
+	if request.AutoAdvance != nil {
+		w.autoAdvanceBlock = *request.AutoAdvance
+	}
+
+	w.blockchainHook.CurrentBlockInfo = updateBlockInfo(w.blockchainHook.CurrentBlockInfo, request.Current)
+	w.blockchainHook.PreviousBlockInfo = updateBlockInfo(w.blockchainHook.PreviousBlockInfo, request.Previous)
+	w.advanceBlocks(request.Advance)
+
+	return &BlockResponse{
+		Current:         newBlockInfoResponse(w.blockchainHook.CurrentBlockInfo),
+		Previous:        newBlockInfoResponse(w.blockchainHook.PreviousBlockInfo),
+		AutoAdvance:     w.autoAdvanceBlock,
+		SecondsPerBlock: w.secondsPerBlock,
+	}
+}
+
+// updateBlockInfo overwrites the fields present in the request, the same way scenarios handle setState block infos
+func updateBlockInfo(blockInfo *worldmock.BlockInfo, request *BlockInfoRequest) *worldmock.BlockInfo {
+	if request == nil {
+		return blockInfo
+	}
+
+	if blockInfo == nil {
+		blockInfo = &worldmock.BlockInfo{}
+	}
+
+	if request.Timestamp != nil {
+		blockInfo.BlockTimestamp = *request.Timestamp
+	}
+	if request.Nonce != nil {
+		blockInfo.BlockNonce = *request.Nonce
+	}
+	if request.Round != nil {
+		blockInfo.BlockRound = *request.Round
+	}
+	if request.Epoch != nil {
+		blockInfo.BlockEpoch = *request.Epoch
+	}
+	if request.RandomSeed != nil {
+		blockInfo.RandomSeed = request.RandomSeed
+	}
+
+	return blockInfo
+}
+
+// advanceBlocks moves the world the given number of blocks ahead: the current block becomes the previous one,
+// the nonce and round grow by one, the timestamp by secondsPerBlock, and the random seed is derived from the previous one
+func (w *world) advanceBlocks(count uint64) {
+	for i := uint64(0); i < count; i++ {
+		current := w.blockchainHook.CurrentBlockInfo
+		if current == nil {
+			current = &worldmock.BlockInfo{}
+		}
+
+		w.blockchainHook.PreviousBlockInfo = current
+		w.blockchainHook.CurrentBlockInfo = &worldmock.BlockInfo{
+			BlockTimestamp: current.BlockTimestamp + w.secondsPerBlock,
+			BlockNonce:     current.BlockNonce + 1,
+			BlockRound:     current.BlockRound + 1,
+			BlockEpoch:     current.BlockEpoch,
+			RandomSeed:     nextRandomSeed(current.GetRandomSeedSlice()),
+		}
+	}
+}
+
+func nextRandomSeed(previousSeed []byte) *[48]byte {
+	firstHash := worldmock.DefaultHasher.Compute(string(previousSeed))
+	secondHash := worldmock.DefaultHasher.Compute(string(firstHash))
+
+	randomSeed := &[48]byte{}
+	copy(randomSeed[:], firstHash)
+	copy(randomSeed[len(firstHash):], secondHash)
+	return randomSeed
+}
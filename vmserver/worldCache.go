@@ -0,0 +1,119 @@
+package vmserver
+
+import (
+	"sync"
+	"time"
+)
+
+type cachedWorld struct {
+	filePath  string
+	store     WorldStore
+	dataModel *worldDataModel
+	dirty     bool
+}
+
+// worldCache keeps the worlds in memory and writes the changed ones to their stores in the background
+type worldCache struct {
+	mutex         sync.Mutex
+	worlds        map[string]*cachedWorld
+	flushInterval time.Duration
+	closeChan     chan struct{}
+	closeOnce     sync.Once
+	waitGroup     sync.WaitGroup
+}
+
+// newWorldCache creates a world cache; with a zero flush interval, changes are only written on flush or close
+func newWorldCache(flushInterval time.Duration) *worldCache {
+	cache := &worldCache{
+		worlds:        make(map[string]*cachedWorld),
+		flushInterval: flushInterval,
+		closeChan:     make(chan struct{}),
+	}
+
+	if flushInterval > 0 {
+		cache.waitGroup.Add(1)
+		go cache.flushPeriodically()
+	}
+
+	return cache
+}
+
+func (cache *worldCache) flushPeriodically() {
+	defer cache.waitGroup.Done()
+
+	ticker := time.NewTicker(cache.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			err := cache.flush()
+			if err != nil {
+				log.Error("worldCache.flushPeriodically", "err", err)
+			}
+		case <-cache.closeChan:
+			return
+		}
+	}
+}
+
+// get returns a copy of the cached world, so that the cache is only changed through put
+func (cache *worldCache) get(filePath string) (*worldDataModel, bool) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cached, ok := cache.worlds[worldKey(filePath)]
+	if !ok {
+		return nil, false
+	}
+
+	return cached.dataModel.clone(), true
+}
+
+// put takes ownership of the data model and marks it for writing to the given store
+func (cache *worldCache) put(filePath string, store WorldStore, dataModel *worldDataModel) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cache.worlds[worldKey(filePath)] = &cachedWorld{
+		filePath:  filePath,
+		store:     store,
+		dataModel: dataModel,
+		dirty:     true,
+	}
+}
+
+// flush writes all changed worlds to their stores; the worlds failing to be written are retried on the next flush
+func (cache *worldCache) flush() error {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	var lastErr error
+	for _, cached := range cache.worlds {
+		if !cached.dirty {
+			continue
+		}
+
+		log.Trace("worldCache.flush()", "file", cached.filePath)
+		err := cached.store.StoreWorld(cached.dataModel)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		cached.dirty = false
+		cached.dataModel.changes = newWorldChanges()
+	}
+
+	return lastErr
+}
+
+// close stops the background writing and writes the remaining changes
+func (cache *worldCache) close() error {
+	cache.closeOnce.Do(func() {
+		close(cache.closeChan)
+	})
+	cache.waitGroup.Wait()
+
+	return cache.flush()
+}
@@ -0,0 +1,83 @@
+package vmserver
+
+import (
+	vmcommon "github.com/multiversx/mx-chain-vm-common-go"
+)
+
+// worldChanges are the accounts changed by the requests run on a world since it was loaded from its store,
+// so that the store can write only those; a world without changes is written as a whole
+type worldChanges struct {
+	accounts map[string]*accountChanges
+}
+
+// accountChanges tell which part of the storage of a changed account is written besides the account itself:
+// the keys updated by the executions, the protected keys written directly by the builtin functions, or all of it
+type accountChanges struct {
+	storageKeys   map[string]struct{}
+	protectedKeys bool
+	allStorage    bool
+}
+
+func newWorldChanges() *worldChanges {
+	return &worldChanges{accounts: make(map[string]*accountChanges)}
+}
+
+// clone copies the changes, so that the worlds created from the same data model record their changes apart
+func (changes *worldChanges) clone() *worldChanges {
+	if changes == nil {
+		return nil
+	}
+
+	clone := newWorldChanges()
+	for address, account := range changes.accounts {
+		accountClone := *account
+		accountClone.storageKeys = make(map[string]struct{}, len(account.storageKeys))
+		for key := range account.storageKeys {
+			accountClone.storageKeys[key] = struct{}{}
+		}
+		clone.accounts[address] = &accountClone
+	}
+
+	return clone
+}
+
+// account returns the changes of an account, marking it as changed; the changes of an untracked world are discarded
+func (changes *worldChanges) account(address []byte) *accountChanges {
+	if changes == nil {
+		return &accountChanges{storageKeys: make(map[string]struct{})}
+	}
+
+	account, ok := changes.accounts[string(address)]
+	if !ok {
+		account = &accountChanges{storageKeys: make(map[string]struct{})}
+		changes.accounts[string(address)] = account
+	}
+
+	return account
+}
+
+// markOutputAccounts records the accounts changed by an execution, with the storage keys it updated;
+// the builtin functions change the tokens without storage updates, so the protected keys of these accounts,
+// and of the system account holding the token metadata, are written as well
+func (changes *worldChanges) markOutputAccounts(vmOutput *vmcommon.VMOutput) {
+	for _, outputAccount := range vmOutput.OutputAccounts {
+		account := changes.account(outputAccount.Address)
+		account.protectedKeys = true
+		for key := range outputAccount.StorageUpdates {
+			account.storageKeys[key] = struct{}{}
+		}
+	}
+
+	for _, address := range vmOutput.DeletedAccounts {
+		changes.account(address)
+	}
+
+	changes.account(vmcommon.SystemAccountAddress).protectedKeys = true
+}
+
+// markTokenTransfer records the accounts whose tokens are moved by the builtin functions
+func (changes *worldChanges) markTokenTransfer(sender []byte, receiver []byte) {
+	changes.account(sender).protectedKeys = true
+	changes.account(receiver).protectedKeys = true
+	changes.account(vmcommon.SystemAccountAddress).protectedKeys = true
+}
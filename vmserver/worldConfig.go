@@ -0,0 +1,185 @@
+package vmserver
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+
+	"github.com/multiversx/mx-chain-vm-v1_4-go/config"
+	gasSchedules "github.com/multiversx/mx-chain-vm-v1_4-go/scenarioexec/gasSchedules"
+	"github.com/multiversx/mx-chain-vm-v1_4-go/vmhost/mock"
+)
+
+const (
+	// GasScheduleDummy is the gas schedule of worlds that never chose one, every operation costs 1
+	GasScheduleDummy = "dummy"
+	// GasScheduleV3 is the embedded gas schedule V3
+	GasScheduleV3 = "v3"
+	// GasScheduleV4 is the embedded gas schedule V4
+	GasScheduleV4 = "v4"
+	// AllEnabledFlags enables every flag when present in the enabled flags of a world
+	AllEnabledFlags = "*"
+)
+
+// enabledFlagSetters holds the flags a world can enable, named after the EnableEpochsHandler methods
+var enabledFlagSetters = map[string]func(stub *mock.EnableEpochsHandlerStub){
+	"IsGlobalMintBurnFlagEnabled":   func(stub *mock.EnableEpochsHandlerStub) { stub.IsGlobalMintBurnFlagEnabledField = true },
+	"IsESDTTransferRoleFlagEnabled": func(stub *mock.EnableEpochsHandlerStub) { stub.IsESDTTransferRoleFlagEnabledField = true },
+	"IsBuiltInFunctionsFlagEnabled": func(stub *mock.EnableEpochsHandlerStub) { stub.IsBuiltInFunctionsFlagEnabledField = true },
+	"IsCheckCorrectTokenIDForTransferRoleFlagEnabled": func(stub *mock.EnableEpochsHandlerStub) {
+		stub.IsCheckCorrectTokenIDForTransferRoleFlagEnabledField = true
+	},
+	"IsMultiESDTTransferFixOnCallBackFlagEnabled": func(stub *mock.EnableEpochsHandlerStub) { stub.IsMultiESDTTransferFixOnCallBackFlagEnabledField = true },
+	"IsFixOOGReturnCodeFlagEnabled":               func(stub *mock.EnableEpochsHandlerStub) { stub.IsFixOOGReturnCodeFlagEnabledField = true },
+	"IsRemoveNonUpdatedStorageFlagEnabled":        func(stub *mock.EnableEpochsHandlerStub) { stub.IsRemoveNonUpdatedStorageFlagEnabledField = true },
+	"IsCreateNFTThroughExecByCallerFlagEnabled":   func(stub *mock.EnableEpochsHandlerStub) { stub.IsCreateNFTThroughExecByCallerFlagEnabledField = true },
+	"IsStorageAPICostOptimizationFlagEnabled":     func(stub *mock.EnableEpochsHandlerStub) { stub.IsStorageAPICostOptimizationFlagEnabledField = true },
+	"IsFailExecutionOnEveryAPIErrorFlagEnabled":   func(stub *mock.EnableEpochsHandlerStub) { stub.IsFailExecutionOnEveryAPIErrorFlagEnabledField = true },
+	"IsManagedCryptoAPIsFlagEnabled":              func(stub *mock.EnableEpochsHandlerStub) { stub.IsManagedCryptoAPIsFlagEnabledField = true },
+	"IsSCDeployFlagEnabled":                       func(stub *mock.EnableEpochsHandlerStub) { stub.IsSCDeployFlagEnabledField = true },
+	"IsAheadOfTimeGasUsageFlagEnabled":            func(stub *mock.EnableEpochsHandlerStub) { stub.IsAheadOfTimeGasUsageFlagEnabledField = true },
+	"IsRepairCallbackFlagEnabled":                 func(stub *mock.EnableEpochsHandlerStub) { stub.IsRepairCallbackFlagEnabledField = true },
+	"IsDisableExecByCallerFlagEnabled":            func(stub *mock.EnableEpochsHandlerStub) { stub.IsDisableExecByCallerFlagEnabledField = true },
+	"IsRefactorContextFlagEnabled":                func(stub *mock.EnableEpochsHandlerStub) { stub.IsRefactorContextFlagEnabledField = true },
+	"IsCheckFunctionArgumentFlagEnabled":          func(stub *mock.EnableEpochsHandlerStub) { stub.IsCheckFunctionArgumentFlagEnabledField = true },
+	"IsCheckExecuteOnReadOnlyFlagEnabled":         func(stub *mock.EnableEpochsHandlerStub) { stub.IsCheckExecuteOnReadOnlyFlagEnabledField = true },
+	"IsFixAsyncCallbackCheckFlagEnabled":          func(stub *mock.EnableEpochsHandlerStub) { stub.IsFixAsyncCallbackCheckFlagEnabledField = true },
+	"IsSaveToSystemAccountFlagEnabled":            func(stub *mock.EnableEpochsHandlerStub) { stub.IsSaveToSystemAccountFlagEnabledField = true },
+	"IsCheckFrozenCollectionFlagEnabled":          func(stub *mock.EnableEpochsHandlerStub) { stub.IsCheckFrozenCollectionFlagEnabledField = true },
+	"IsSendAlwaysFlagEnabled":                     func(stub *mock.EnableEpochsHandlerStub) { stub.IsSendAlwaysFlagEnabledField = true },
+	"IsValueLengthCheckFlagEnabled":               func(stub *mock.EnableEpochsHandlerStub) { stub.IsValueLengthCheckFlagEnabledField = true },
+	"IsCheckTransferFlagEnabled":                  func(stub *mock.EnableEpochsHandlerStub) { stub.IsCheckTransferFlagEnabledField = true },
+	"IsTransferToMetaFlagEnabled":                 func(stub *mock.EnableEpochsHandlerStub) { stub.IsTransferToMetaFlagEnabledField = true },
+	"IsESDTNFTImprovementV1FlagEnabled":           func(stub *mock.EnableEpochsHandlerStub) { stub.IsESDTNFTImprovementV1FlagEnabledField = true },
+	"IsFixOldTokenLiquidityEnabled":               func(stub *mock.EnableEpochsHandlerStub) { stub.IsFixOldTokenLiquidityEnabledField = true },
+	"IsRuntimeMemStoreLimitEnabled":               func(stub *mock.EnableEpochsHandlerStub) { stub.IsRuntimeMemStoreLimitEnabledField = true },
+	"IsRuntimeCodeSizeFixEnabled":                 func(stub *mock.EnableEpochsHandlerStub) { stub.IsRuntimeCodeSizeFixEnabledField = true },
+	"IsMaxBlockchainHookCountersFlagEnabled":      func(stub *mock.EnableEpochsHandlerStub) { stub.IsMaxBlockchainHookCountersFlagEnabledField = true },
+	"IsWipeSingleNFTLiquidityDecreaseEnabled":     func(stub *mock.EnableEpochsHandlerStub) { stub.IsWipeSingleNFTLiquidityDecreaseEnabledField = true },
+	"IsAlwaysSaveTokenMetaDataEnabled":            func(stub *mock.EnableEpochsHandlerStub) { stub.IsAlwaysSaveTokenMetaDataEnabledField = true },
+	"IsGuardAccountEnabled":                       func(stub *mock.EnableEpochsHandlerStub) { stub.IsGuardAccountEnabledField = true },
+	"IsSetGuardianEnabled":                        func(stub *mock.EnableEpochsHandlerStub) { stub.IsSetGuardianEnabledField = true },
+	"IsVMPluginsFlagEnabled":                      func(stub *mock.EnableEpochsHandlerStub) { stub.IsVMPluginsFlagEnabledField = true },
+}
+
+// loadGasSchedule resolves an embedded gas schedule version, or reads a TOML gas schedule file
+func loadGasSchedule(version string, filePath string) (config.GasScheduleMap, error) {
+	if len(filePath) > 0 {
+		fileContents, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+		return gasSchedules.LoadGasScheduleConfig(string(fileContents))
+	}
+
+	switch version {
+	case GasScheduleDummy:
+		return config.MakeGasMap(1, 1), nil
+	case GasScheduleV3:
+		return gasSchedules.LoadGasScheduleConfig(gasSchedules.GetV3())
+	case GasScheduleV4:
+		return gasSchedules.LoadGasScheduleConfig(gasSchedules.GetV4())
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownGasSchedule, version)
+	}
+}
+
+func (w *world) setConfig(request WorldConfigRequest) (*WorldConfigResponse, error) {
+	log.Trace("w.setConfig()", "request", prettyJson(request))
+
+	if len(request.GasSchedule) > 0 {
+		_, err := loadGasSchedule(request.GasSchedule, "")
+		if err != nil {
+			return nil, err
+		}
+
+		w.gasScheduleName = request.GasSchedule
+		w.gasSchedule = nil
+	}
+	if len(request.GasSchedulePath) > 0 {
+		gasSchedule, err := loadGasSchedule("", request.GasSchedulePath)
+		if err != nil {
+			return nil, err
+		}
+
+		w.gasScheduleName = request.GasSchedulePath
+		w.gasSchedule = gasSchedule
+	}
+	if request.EnabledFlags != nil {
+		w.enabledFlags = *request.EnabledFlags
+	}
+
+	return w.getConfig(), nil
+}
+
+func (w *world) getConfig() *WorldConfigResponse {
+	gasScheduleName := w.gasScheduleName
+	if len(gasScheduleName) == 0 {
+		gasScheduleName = GasScheduleDummy
+	}
+
+	enabledFlags := w.enabledFlags
+	if enabledFlags == nil {
+		enabledFlags = make([]string, 0)
+	}
+
+	return &WorldConfigResponse{
+		GasSchedule:  gasScheduleName,
+		EnabledFlags: enabledFlags,
+		KnownFlags:   getKnownEnabledFlags(),
+	}
+}
+
+// resolveGasSchedule returns the gas schedule a world was configured with; schedules read from files are kept
+// in the world itself, while embedded ones are referenced by name
+func resolveGasSchedule(name string, gasSchedule config.GasScheduleMap) (config.GasScheduleMap, error) {
+	if gasSchedule != nil {
+		return gasSchedule, nil
+	}
+	if len(name) == 0 {
+		return loadGasSchedule(GasScheduleDummy, "")
+	}
+
+	return loadGasSchedule(name, "")
+}
+
+func checkEnabledFlags(enabledFlags []string) error {
+	for _, flag := range enabledFlags {
+		_, known := enabledFlagSetters[flag]
+		if !known && flag != AllEnabledFlags {
+			return fmt.Errorf("%w: %s", ErrUnknownEnabledFlag, flag)
+		}
+	}
+
+	return nil
+}
+
+func newEnableEpochsHandler(enabledFlags []string) (*mock.EnableEpochsHandlerStub, error) {
+	err := checkEnabledFlags(enabledFlags)
+	if err != nil {
+		return nil, err
+	}
+
+	stub := &mock.EnableEpochsHandlerStub{}
+	for _, flag := range enabledFlags {
+		if flag == AllEnabledFlags {
+			for _, setFlag := range enabledFlagSetters {
+				setFlag(stub)
+			}
+			continue
+		}
+		enabledFlagSetters[flag](stub)
+	}
+
+	return stub, nil
+}
+
+// getKnownEnabledFlags returns the sorted names of the flags a world can enable
+func getKnownEnabledFlags() []string {
+	flags := make([]string, 0, len(enabledFlagSetters))
+	for flag := range enabledFlagSetters {
+		flags = append(flags, flag)
+	}
+	sort.Strings(flags)
+	return flags
+}
@@ -0,0 +1,101 @@
+package vmserver
+
+import (
+	"sync"
+)
+
+// eventsBufferSize is how many entries a subscription holds for a slow client, before dropping the new ones
+const eventsBufferSize = 256
+
+// worldEvents passes the entries added to the transaction logs to the clients following the worlds
+type worldEvents struct {
+	mutex         sync.Mutex
+	subscriptions map[*EventSubscription]struct{}
+}
+
+// EventSubscription receives the executed requests of a world, as they are added to its transaction log
+type EventSubscription struct {
+	worldKey string
+	filters  TransactionLogRequest
+	entries  chan *TransactionLogEntry
+	events   *worldEvents
+}
+
+func newWorldEvents() *worldEvents {
+	return &worldEvents{
+		subscriptions: make(map[*EventSubscription]struct{}),
+	}
+}
+
+// subscribe follows a world file, keeping the entries matching the filters of the request
+func (we *worldEvents) subscribe(worldFile string, filters TransactionLogRequest) *EventSubscription {
+	subscription := &EventSubscription{
+		worldKey: worldKey(worldFile),
+		filters:  filters,
+		entries:  make(chan *TransactionLogEntry, eventsBufferSize),
+		events:   we,
+	}
+
+	we.mutex.Lock()
+	we.subscriptions[subscription] = struct{}{}
+	we.mutex.Unlock()
+
+	return subscription
+}
+
+// publish hands an entry to the subscriptions of its world; it never waits for the clients
+func (we *worldEvents) publish(worldFile string, entry *TransactionLogEntry) {
+	key := worldKey(worldFile)
+
+	we.mutex.Lock()
+	defer we.mutex.Unlock()
+
+	for subscription := range we.subscriptions {
+		if subscription.worldKey != key {
+			continue
+		}
+		if len(filterTransactionLog([]*TransactionLogEntry{entry}, subscription.filters)) == 0 {
+			continue
+		}
+
+		select {
+		case subscription.entries <- entry:
+		default:
+			log.Warn("worldEvents.publish: subscription is full, entry dropped", "world", subscription.filters.World, "txHash", entry.TxHash)
+		}
+	}
+}
+
+func (we *worldEvents) unsubscribe(subscription *EventSubscription) {
+	we.mutex.Lock()
+	defer we.mutex.Unlock()
+
+	_, ok := we.subscriptions[subscription]
+	if !ok {
+		return
+	}
+
+	delete(we.subscriptions, subscription)
+	close(subscription.entries)
+}
+
+// close ends all the subscriptions
+func (we *worldEvents) close() {
+	we.mutex.Lock()
+	defer we.mutex.Unlock()
+
+	for subscription := range we.subscriptions {
+		delete(we.subscriptions, subscription)
+		close(subscription.entries)
+	}
+}
+
+// Entries yields the matching entries, in execution order; it is closed when the subscription ends
+func (subscription *EventSubscription) Entries() <-chan *TransactionLogEntry {
+	return subscription.entries
+}
+
+// Close ends the subscription
+func (subscription *EventSubscription) Close() {
+	subscription.events.unsubscribe(subscription)
+}
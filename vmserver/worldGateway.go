@@ -0,0 +1,443 @@
+package vmserver
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	"github.com/multiversx/mx-chain-core-go/data/transaction"
+	"github.com/multiversx/mx-chain-core-go/data/vm"
+	vmcommon "github.com/multiversx/mx-chain-vm-common-go"
+	"github.com/multiversx/mx-chain-vm-common-go/parsers"
+	worldmock "github.com/multiversx/mx-chain-vm-v1_4-go/mock/world"
+	"github.com/multiversx/mx-chain-vm-v1_4-go/vmhost"
+)
+
+const (
+	deployEventIdentifier  = "SCDeploy"
+	upgradeEventIdentifier = "SCUpgrade"
+	errorEventIdentifier   = "signalError"
+)
+
+// gatewayExecution is what a gateway transaction did, before being converted to the format of the gateway
+type gatewayExecution struct {
+	vmInput         *vmcommon.VMInput
+	vmOutput        *vmcommon.VMOutput
+	err             error
+	function        string
+	contractAddress []byte
+	code            []byte
+	codeMetadata    []byte
+	isDeploy        bool
+	isUpgrade       bool
+}
+
+func (w *world) getGatewayAccount(address []byte) *GatewayAccount {
+	gatewayAccount := &GatewayAccount{
+		Address:         encodeBech32Address(address),
+		Balance:         "0",
+		DeveloperReward: "0",
+	}
+
+	account := w.blockchainHook.AcctMap.GetAccount(address)
+	if account == nil {
+		return gatewayAccount
+	}
+
+	gatewayAccount.Nonce = account.Nonce
+	gatewayAccount.Balance = account.Balance.String()
+	gatewayAccount.Username = string(account.Username)
+	gatewayAccount.Code = toHex(account.Code)
+	gatewayAccount.CodeHash = account.CodeHash
+	gatewayAccount.RootHash = account.RootHash
+	gatewayAccount.CodeMetadata = account.CodeMetadata
+	gatewayAccount.DeveloperReward = account.DeveloperReward.String()
+	gatewayAccount.OwnerAddress = encodeBech32Address(account.OwnerAddress)
+	return gatewayAccount
+}
+
+func (w *world) getGatewayStorage(request GatewayStorageRequest) *GatewayStorageResponse {
+	value := make([]byte, 0)
+	account := w.blockchainHook.AcctMap.GetAccount(request.Address)
+	if account != nil {
+		value = account.Storage[string(request.Key)]
+	}
+
+	return &GatewayStorageResponse{Value: toHex(value)}
+}
+
+// executeTransaction runs a gateway transaction the way the protocol would: deployments are sent to the zero address,
+// ESDT transfers and contract calls are parsed from the data field, anything else is a plain transfer;
+// the returned error means the transaction was rejected, execution errors are part of the result and of the log entry
+func (w *world) executeTransaction(tx *transaction.Transaction, txHash string) (*transaction.ApiTransactionResult, *TransactionLogEntry, error) {
+	log.Trace("w.executeTransaction()", "tx", prettyJson(tx))
+
+	sender := w.blockchainHook.AcctMap.GetAccount(tx.SndAddr)
+	if sender == nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrAccountNotFound, encodeBech32Address(tx.SndAddr))
+	}
+	if sender.Nonce != tx.Nonce {
+		return nil, nil, fmt.Errorf("%w: expected %d, got %d", ErrInvalidTransactionNonce, sender.Nonce, tx.Nonce)
+	}
+
+	if w.autoAdvanceBlock {
+		w.advanceBlocks(1)
+	}
+
+	execution := w.runTransaction(tx)
+
+	// deployments already increment the nonce of the sender
+	sender = w.blockchainHook.AcctMap.GetAccount(tx.SndAddr)
+	sender.Nonce = tx.Nonce + 1
+	w.changes.account(tx.SndAddr)
+
+	contract := execution.contractAddress
+	if len(contract) == 0 {
+		contract = tx.RcvAddr
+	}
+	entry := w.newTransactionLogEntry(&executedRequest{
+		txHash:       txHash,
+		kind:         TransactionKindGateway,
+		contract:     contract,
+		function:     execution.function,
+		gasLimit:     tx.GasLimit,
+		code:         execution.code,
+		codeMetadata: execution.codeMetadata,
+		input:        execution.vmInput,
+		output:       execution.vmOutput,
+		err:          execution.err,
+	})
+
+	return w.toApiTransactionResult(tx, txHash, execution), entry, nil
+}
+
+func (w *world) runTransaction(tx *transaction.Transaction) *gatewayExecution {
+	request := ContractRequestBase{
+		Impersonated:  tx.SndAddr,
+		ValueAsBigInt: tx.Value,
+		GasPrice:      tx.GasPrice,
+		GasLimit:      tx.GasLimit,
+	}
+
+	if bytes.Equal(tx.RcvAddr, make([]byte, addressLength)) {
+		return w.runDeployTransaction(request, tx.Data)
+	}
+
+	function, arguments, err := parsers.NewCallArgsParser().ParseData(string(tx.Data))
+	if err != nil {
+		// data that is not a call is only a note attached to a transfer
+		function, arguments = "", nil
+	}
+
+	receiver := tx.RcvAddr
+	transferFunction := ""
+	if isESDTTransferFunction(function) {
+		transferFunction = function
+		esdtTransferParser, _ := parsers.NewESDTTransferParser(worldmock.WorldMarshalizer)
+		parsedTransfers, err := esdtTransferParser.ParseESDTTransfers(tx.SndAddr, tx.RcvAddr, function, arguments)
+		if err != nil {
+			return &gatewayExecution{err: err, function: function}
+		}
+
+		request.ESDTTransfers = fromVMCommonESDTTransfers(parsedTransfers.ESDTTransfers)
+		receiver = parsedTransfers.RcvAddr
+		function = parsedTransfers.CallFunction
+		arguments = parsedTransfers.CallArgs
+	}
+
+	receiverAccount := w.blockchainHook.AcctMap.GetAccount(receiver)
+	isContract := receiverAccount != nil && len(receiverAccount.Code) > 0
+	if !isContract || len(function) == 0 {
+		return w.runTransferTransaction(request, receiver, transferFunction)
+	}
+
+	if function == vmhost.UpgradeFunctionName {
+		return w.runUpgradeTransaction(request, receiver, arguments)
+	}
+
+	response := w.callSmartContract(RunRequest{
+		ContractRequestBase: request,
+		ContractAddress:     receiver,
+		Function:            function,
+		Arguments:           arguments,
+	})
+	return &gatewayExecution{
+		vmInput:         response.Input,
+		vmOutput:        response.Output,
+		err:             response.Error,
+		function:        function,
+		contractAddress: receiver,
+	}
+}
+
+func (w *world) runDeployTransaction(request ContractRequestBase, data []byte) *gatewayExecution {
+	deployArgs, err := parsers.NewDeployArgsParser().ParseData(string(data))
+	if err != nil {
+		return &gatewayExecution{err: err, function: vmhost.InitFunctionName, isDeploy: true}
+	}
+
+	codeMetadata := deployArgs.CodeMetadata.ToBytes()
+	response := w.deploySmartContract(DeployRequest{
+		ContractRequestBase: request,
+		Code:                deployArgs.Code,
+		CodeMetadataBytes:   codeMetadata,
+		Arguments:           deployArgs.Arguments,
+	})
+	return &gatewayExecution{
+		vmInput:         response.Input,
+		vmOutput:        response.Output,
+		err:             response.Error,
+		function:        vmhost.InitFunctionName,
+		contractAddress: response.ContractAddress,
+		code:            deployArgs.Code,
+		codeMetadata:    codeMetadata,
+		isDeploy:        true,
+	}
+}
+
+func (w *world) runUpgradeTransaction(request ContractRequestBase, contractAddress []byte, arguments [][]byte) *gatewayExecution {
+	execution := &gatewayExecution{function: vmhost.UpgradeFunctionName, contractAddress: contractAddress, isUpgrade: true}
+	if len(arguments) < 2 {
+		execution.err = vmhost.ErrInvalidUpgradeArguments
+		return execution
+	}
+
+	response := w.upgradeSmartContract(UpgradeRequest{
+		DeployRequest: DeployRequest{
+			ContractRequestBase: request,
+			Code:                arguments[0],
+			CodeMetadataBytes:   arguments[1],
+			Arguments:           arguments[2:],
+		},
+		ContractAddress: contractAddress,
+	})
+	execution.vmInput = response.Input
+	execution.vmOutput = response.Output
+	execution.err = response.Error
+	return execution
+}
+
+// runTransferTransaction moves the EGLD and the ESDT tokens of a transaction that does not call a contract;
+// as on chain, the receiver account is created if it does not exist yet
+func (w *world) runTransferTransaction(request ContractRequestBase, receiver []byte, transferFunction string) *gatewayExecution {
+	w.getOrCreateAccount(receiver)
+
+	vmInput := &vmcommon.VMInput{
+		CallerAddr:    request.Impersonated,
+		CallValue:     request.ValueAsBigInt,
+		GasPrice:      request.GasPrice,
+		GasProvided:   request.GasLimit,
+		ESDTTransfers: prepareESDTTransfers(request.ESDTTransfers),
+	}
+	vmOutput, err := w.runWithESDTTransfers(receiver, &request, vmInput, func() (*vmcommon.VMOutput, error) {
+		err := w.transferValue(request.Impersonated, receiver, request.ValueAsBigInt)
+		if err != nil {
+			return nil, err
+		}
+
+		return &vmcommon.VMOutput{ReturnCode: vmcommon.Ok}, nil
+	})
+
+	return &gatewayExecution{vmInput: vmInput, vmOutput: vmOutput, err: err, function: transferFunction}
+}
+
+func (w *world) transferValue(sender []byte, receiver []byte, value *big.Int) error {
+	if value.Sign() == 0 {
+		return nil
+	}
+
+	senderAccount := w.blockchainHook.AcctMap.GetAccount(sender)
+	if senderAccount.Balance.Cmp(value) < 0 {
+		return vmhost.ErrTransferInsufficientFunds
+	}
+
+	receiverAccount := w.getOrCreateAccount(receiver)
+	senderAccount.Balance = big.NewInt(0).Sub(senderAccount.Balance, value)
+	receiverAccount.Balance = big.NewInt(0).Add(receiverAccount.Balance, value)
+	w.changes.account(sender)
+	w.changes.account(receiver)
+	return nil
+}
+
+func (w *world) getOrCreateAccount(address []byte) *worldmock.Account {
+	account := w.blockchainHook.AcctMap.GetAccount(address)
+	if account != nil {
+		return account
+	}
+
+	account = &worldmock.Account{
+		Exists:          true,
+		Address:         address,
+		Balance:         big.NewInt(0),
+		BalanceDelta:    big.NewInt(0),
+		DeveloperReward: big.NewInt(0),
+		Storage:         make(map[string][]byte),
+		MockWorld:       w.blockchainHook,
+	}
+	w.blockchainHook.AcctMap.PutAccount(account)
+	w.changes.account(address)
+	return account
+}
+
+func isESDTTransferFunction(function string) bool {
+	switch function {
+	case core.BuiltInFunctionESDTTransfer, core.BuiltInFunctionESDTNFTTransfer, core.BuiltInFunctionMultiESDTNFTTransfer:
+		return true
+	default:
+		return false
+	}
+}
+
+func fromVMCommonESDTTransfers(transfers []*vmcommon.ESDTTransfer) []*ESDTTransfer {
+	esdtTransfers := make([]*ESDTTransfer, len(transfers))
+	for i, transfer := range transfers {
+		esdtTransfers[i] = &ESDTTransfer{
+			TokenIdentifier: string(transfer.ESDTTokenName),
+			Nonce:           transfer.ESDTTokenNonce,
+			Value:           transfer.ESDTValue.String(),
+			ValueAsBigInt:   transfer.ESDTValue,
+		}
+	}
+
+	return esdtTransfers
+}
+
+// toApiTransactionResult describes an executed transaction the way the gateway does: the results of contracts
+// are returned to the sender in a smart contract result, and failures are signaled through a signalError event
+func (w *world) toApiTransactionResult(tx *transaction.Transaction, txHash string, execution *gatewayExecution) *transaction.ApiTransactionResult {
+	result := &transaction.ApiTransactionResult{
+		Type:      "normal",
+		Hash:      txHash,
+		Nonce:     tx.Nonce,
+		Value:     tx.Value.String(),
+		Receiver:  encodeBech32Address(tx.RcvAddr),
+		Sender:    encodeBech32Address(tx.SndAddr),
+		GasPrice:  tx.GasPrice,
+		GasLimit:  tx.GasLimit,
+		GasUsed:   tx.GasLimit,
+		Data:      tx.Data,
+		Signature: toHex(tx.Signature),
+		ChainID:   string(tx.ChainID),
+		Version:   tx.Version,
+		Options:   tx.Options,
+		Function:  execution.function,
+		Status:    transaction.TxStatusSuccess,
+		Logs:      &transaction.ApiLogs{Address: encodeBech32Address(tx.RcvAddr), Events: make([]*transaction.Events, 0)},
+	}
+
+	currentBlock := w.blockchainHook.CurrentBlockInfo
+	if currentBlock != nil {
+		result.Round = currentBlock.BlockRound
+		result.Epoch = currentBlock.BlockEpoch
+		result.BlockNonce = currentBlock.BlockNonce
+		result.Timestamp = int64(currentBlock.BlockTimestamp)
+	}
+
+	returnCode := vmcommon.Ok
+	returnMessage := ""
+	if execution.vmOutput != nil {
+		returnCode = execution.vmOutput.ReturnCode
+		returnMessage = execution.vmOutput.ReturnMessage
+		if execution.vmOutput.GasRemaining <= tx.GasLimit {
+			result.GasUsed = tx.GasLimit - execution.vmOutput.GasRemaining
+		}
+	}
+	if execution.err != nil {
+		returnCode = vmcommon.ExecutionFailed
+		returnMessage = execution.err.Error()
+	}
+
+	if returnCode != vmcommon.Ok {
+		result.Status = transaction.TxStatusFail
+		result.ReturnMessage = returnMessage
+		result.Logs.Events = append(result.Logs.Events, &transaction.Events{
+			Address:    encodeBech32Address(tx.SndAddr),
+			Identifier: errorEventIdentifier,
+			Topics:     [][]byte{tx.SndAddr, []byte(returnMessage)},
+			Data:       []byte("@" + toHex([]byte(returnCode.String()))),
+		})
+		return result
+	}
+
+	if execution.isDeploy || execution.isUpgrade {
+		identifier := deployEventIdentifier
+		if execution.isUpgrade {
+			identifier = upgradeEventIdentifier
+		}
+		result.Logs.Events = append(result.Logs.Events, &transaction.Events{
+			Address:    encodeBech32Address(execution.contractAddress),
+			Identifier: identifier,
+			Topics:     [][]byte{execution.contractAddress, tx.SndAddr},
+		})
+	}
+
+	for _, logEntry := range execution.vmOutput.Logs {
+		result.Logs.Events = append(result.Logs.Events, &transaction.Events{
+			Address:    encodeBech32Address(logEntry.Address),
+			Identifier: string(logEntry.Identifier),
+			Topics:     logEntry.Topics,
+			Data:       logEntry.Data,
+		})
+	}
+
+	if len(execution.contractAddress) > 0 {
+		result.SmartContractResults = []*transaction.ApiSmartContractResult{
+			{
+				Nonce:          tx.Nonce + 1,
+				Value:          big.NewInt(0),
+				RcvAddr:        encodeBech32Address(tx.SndAddr),
+				SndAddr:        encodeBech32Address(execution.contractAddress),
+				Data:           encodeReturnData(execution.vmOutput.ReturnData),
+				PrevTxHash:     txHash,
+				OriginalTxHash: txHash,
+				GasPrice:       tx.GasPrice,
+				CallType:       vm.DirectCall,
+			},
+		}
+	}
+
+	return result
+}
+
+// encodeReturnData formats the results of a contract the way the protocol does: "@6f6b" (hex of "ok"), then the results
+func encodeReturnData(returnData [][]byte) string {
+	encoded := make([]string, 0, len(returnData)+2)
+	encoded = append(encoded, "", toHex([]byte(vmcommon.Ok.String())))
+	for _, data := range returnData {
+		encoded = append(encoded, toHex(data))
+	}
+
+	return strings.Join(encoded, "@")
+}
+
+func (w *world) gatewayQuery(request GatewayQueryRequest) *GatewayQueryResponse {
+	response := w.querySmartContract(request.query)
+
+	output := &GatewayVMOutput{
+		ReturnData:      make([][]byte, 0),
+		ReturnCode:      vmcommon.ExecutionFailed.String(),
+		GasRefund:       big.NewInt(0),
+		OutputAccounts:  make(map[string]interface{}),
+		DeletedAccounts: make([][]byte, 0),
+		TouchedAccounts: make([][]byte, 0),
+		Logs:            make([]*transaction.Events, 0),
+	}
+
+	if response.Error != nil {
+		output.ReturnMessage = response.Error.Error()
+		return &GatewayQueryResponse{Data: output}
+	}
+
+	vmOutput := response.Output
+	output.ReturnData = vmOutput.ReturnData
+	output.ReturnCode = vmOutput.ReturnCode.String()
+	output.ReturnMessage = vmOutput.ReturnMessage
+	output.GasRemaining = vmOutput.GasRemaining
+	if vmOutput.GasRefund != nil {
+		output.GasRefund = vmOutput.GasRefund
+	}
+
+	return &GatewayQueryResponse{Data: output}
+}
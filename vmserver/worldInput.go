@@ -1,6 +1,7 @@
 package vmserver
 
 import (
+	"github.com/multiversx/mx-chain-core-go/core"
 	"github.com/multiversx/mx-chain-vm-common-go"
 	"github.com/multiversx/mx-chain-vm-v1_4-go/vmhost"
 )
@@ -14,6 +15,7 @@ func (w *world) prepareDeployInput(request DeployRequest) *vmcommon.ContractCrea
 	createInput.Arguments = request.Arguments
 	createInput.GasProvided = request.GasLimit
 	createInput.GasPrice = request.GasPrice
+	createInput.ESDTTransfers = prepareESDTTransfers(request.ESDTTransfers)
 
 	return createInput
 }
@@ -31,6 +33,7 @@ func (w *world) prepareUpgradeInput(request UpgradeRequest) *vmcommon.ContractCa
 	callInput.Arguments = allArguments
 	callInput.GasProvided = request.GasLimit
 	callInput.GasPrice = request.GasPrice
+	callInput.ESDTTransfers = prepareESDTTransfers(request.ESDTTransfers)
 
 	return callInput
 }
@@ -44,6 +47,24 @@ func (w *world) prepareCallInput(request RunRequest) *vmcommon.ContractCallInput
 	callInput.Arguments = request.Arguments
 	callInput.GasProvided = request.GasLimit
 	callInput.GasPrice = request.GasPrice
+	callInput.ESDTTransfers = prepareESDTTransfers(request.ESDTTransfers)
 
 	return callInput
 }
+
+func prepareESDTTransfers(transfers []*ESDTTransfer) []*vmcommon.ESDTTransfer {
+	esdtTransfers := make([]*vmcommon.ESDTTransfer, len(transfers))
+	for i, transfer := range transfers {
+		esdtTransfers[i] = &vmcommon.ESDTTransfer{
+			ESDTValue:      transfer.ValueAsBigInt,
+			ESDTTokenName:  []byte(transfer.TokenIdentifier),
+			ESDTTokenType:  uint32(core.Fungible),
+			ESDTTokenNonce: transfer.Nonce,
+		}
+		if transfer.Nonce > 0 {
+			esdtTransfers[i].ESDTTokenType = uint32(core.NonFungible)
+		}
+	}
+
+	return esdtTransfers
+}
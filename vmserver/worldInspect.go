@@ -0,0 +1,105 @@
+package vmserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	vmcommon "github.com/multiversx/mx-chain-vm-common-go"
+	worldmock "github.com/multiversx/mx-chain-vm-v1_4-go/mock/world"
+	"github.com/multiversx/mx-chain-vm-v1_4-go/scenarioexec"
+	er "github.com/multiversx/mx-chain-vm-v1_4-go/scenarios/expression/reconstructor"
+	mjwrite "github.com/multiversx/mx-chain-vm-v1_4-go/scenarios/json/write"
+	mj "github.com/multiversx/mx-chain-vm-v1_4-go/scenarios/model"
+	oj "github.com/multiversx/mx-chain-vm-v1_4-go/scenarios/orderedjson"
+)
+
+// inspectionHook wraps the accounts of a stored world for reading them, without the builtin functions
+// and the VM host that a world needs for executing requests
+func (dataModel *worldDataModel) inspectionHook() *worldmock.MockWorld {
+	blockchainHook := worldmock.NewMockWorld()
+	blockchainHook.AcctMap = dataModel.Accounts
+	return blockchainHook
+}
+
+func (dataModel *worldDataModel) getAccounts() (*AccountsResponse, error) {
+	blockchainHook := dataModel.inspectionHook()
+
+	var scenAccounts []*mj.Account
+	for _, address := range sortedAccountAddresses(blockchainHook.AcctMap) {
+		scenAccount, err := scenarioexec.ConvertMockAccountToScenarioFormat(blockchainHook, blockchainHook.AcctMap[address])
+		if err != nil {
+			return nil, err
+		}
+		scenAccounts = append(scenAccounts, scenAccount)
+	}
+
+	return &AccountsResponse{Accounts: ojToRawJSON(mjwrite.AccountsToOJ(scenAccounts))}, nil
+}
+
+// sortedAccountAddresses returns the addresses of the accounts of a world, sorted;
+// the system account, which only holds the token metadata, is left out
+func sortedAccountAddresses(accounts worldmock.AccountMap) []string {
+	var addresses []string
+	for address := range accounts {
+		if !bytes.Equal([]byte(address), vmcommon.SystemAccountAddress) {
+			addresses = append(addresses, address)
+		}
+	}
+	sort.Strings(addresses)
+
+	return addresses
+}
+
+func (dataModel *worldDataModel) getScenarioAccount(address []byte) (*mj.Account, error) {
+	account := dataModel.Accounts.GetAccount(address)
+	if account == nil {
+		return nil, fmt.Errorf("%w: %s", ErrAccountNotFound, toHex(address))
+	}
+
+	return scenarioexec.ConvertMockAccountToScenarioFormat(dataModel.inspectionHook(), account)
+}
+
+func (dataModel *worldDataModel) getAccount(request AccountRequest) (*AccountResponse, error) {
+	scenAccount, err := dataModel.getScenarioAccount(request.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AccountResponse{Account: ojToRawJSON(mjwrite.AccountsToOJ([]*mj.Account{scenAccount}))}, nil
+}
+
+func (dataModel *worldDataModel) getStorage(request StorageRequest) (*StorageResponse, error) {
+	account := dataModel.Accounts.GetAccount(request.Address)
+	if account == nil {
+		return nil, fmt.Errorf("%w: %s", ErrAccountNotFound, toHex(request.Address))
+	}
+
+	exprReconstructor := er.ExprReconstructor{}
+	value := account.Storage[string(request.KeyBytes)]
+	return &StorageResponse{
+		Key:      exprReconstructor.Reconstruct(request.KeyBytes, er.NoHint),
+		Value:    exprReconstructor.Reconstruct(value, er.NoHint),
+		ValueHex: toHex(value),
+	}, nil
+}
+
+func (dataModel *worldDataModel) getESDT(request ESDTRequest) (*ESDTResponse, error) {
+	scenAccount, err := dataModel.getScenarioAccount(request.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, esdtData := range scenAccount.ESDTData {
+		if string(esdtData.TokenIdentifier.Value) == request.TokenIdentifier {
+			return &ESDTResponse{ESDT: ojToRawJSON(mjwrite.ESDTDataToOJ([]*mj.ESDTData{esdtData}))}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrTokenNotFound, request.TokenIdentifier)
+}
+
+func ojToRawJSON(obj oj.OJsonObject) json.RawMessage {
+	return json.RawMessage(oj.JSONString(obj))
+}
@@ -0,0 +1,68 @@
+package vmserver
+
+import (
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// worldLocks serializes the requests touching the same world file, so that concurrent requests do not lose updates
+type worldLocks struct {
+	mutex sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+func newWorldLocks() *worldLocks {
+	return &worldLocks{
+		locks: make(map[string]*sync.Mutex),
+	}
+}
+
+// lock acquires the locks of the given world files, always in the same order, and returns the function releasing them
+func (wl *worldLocks) lock(filePaths ...string) func() {
+	keys := make([]string, 0, len(filePaths))
+	for _, filePath := range filePaths {
+		keys = append(keys, worldKey(filePath))
+	}
+	sort.Strings(keys)
+
+	acquired := make([]*sync.Mutex, 0, len(keys))
+	for i, key := range keys {
+		if i > 0 && keys[i-1] == key {
+			continue
+		}
+
+		worldLock := wl.getLock(key)
+		worldLock.Lock()
+		acquired = append(acquired, worldLock)
+	}
+
+	return func() {
+		for i := len(acquired) - 1; i >= 0; i-- {
+			acquired[i].Unlock()
+		}
+	}
+}
+
+func (wl *worldLocks) getLock(key string) *sync.Mutex {
+	wl.mutex.Lock()
+	defer wl.mutex.Unlock()
+
+	worldLock, ok := wl.locks[key]
+	if !ok {
+		worldLock = &sync.Mutex{}
+		wl.locks[key] = worldLock
+	}
+
+	return worldLock
+}
+
+// worldKey identifies a world file regardless of how its database path was written
+func worldKey(filePath string) string {
+	absolutePath, err := filepath.Abs(filePath)
+	if err != nil {
+		return filepath.Clean(filePath)
+	}
+
+	return absolutePath
+}
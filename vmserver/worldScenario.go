@@ -0,0 +1,560 @@
+package vmserver
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	vmcommon "github.com/multiversx/mx-chain-vm-common-go"
+	worldmock "github.com/multiversx/mx-chain-vm-v1_4-go/mock/world"
+	"github.com/multiversx/mx-chain-vm-v1_4-go/scenarioexec"
+	ei "github.com/multiversx/mx-chain-vm-v1_4-go/scenarios/expression/interpreter"
+	er "github.com/multiversx/mx-chain-vm-v1_4-go/scenarios/expression/reconstructor"
+	mj "github.com/multiversx/mx-chain-vm-v1_4-go/scenarios/model"
+	oj "github.com/multiversx/mx-chain-vm-v1_4-go/scenarios/orderedjson"
+	"github.com/multiversx/mx-chain-vm-v1_4-go/vmhost"
+)
+
+// worldRecording is the start of a recorded session: the state of the world and the position in its transaction log
+type worldRecording struct {
+	InitialState *worldDataModel
+	FirstEntry   int
+}
+
+// newRecordedScenario replays a recorded session as a scenario: the initial state is a setState step,
+// each executed request is a step expecting its actual result, each created account is a setState step,
+// and the final state is an optional checkState step.
+// Gas is neither paid nor checked, since vmserver does not charge it, and nonces are not checked,
+// since vmserver only keeps them for the gateway. A scenario has a single configuration,
+// so the sessions that change the configuration of the world cannot be exported.
+func newRecordedScenario(request ScenarioRequest, recording *worldRecording, entries []*TransactionLogEntry, finalState *worldDataModel) (*mj.Scenario, error) {
+	initialState := recording.InitialState
+	initialAccounts, err := newScenarioAccounts(initialState.Accounts)
+	if err != nil {
+		return nil, err
+	}
+
+	setState := &mj.SetStateStep{
+		Comment:           "initial state",
+		Accounts:          initialAccounts,
+		PreviousBlockInfo: newScenarioBlockInfo(initialState.PreviousBlockInfo),
+		CurrentBlockInfo:  newScenarioBlockInfo(initialState.CurrentBlockInfo),
+	}
+	scenario := &mj.Scenario{
+		Name:        request.Name,
+		Comment:     fmt.Sprintf("recorded in the world %s", request.World),
+		GasSchedule: scenarioGasSchedule(initialState.GasScheduleName),
+		Steps:       []mj.Step{setState},
+	}
+
+	// the scenario executor increments the nonce of the sender for each successful transaction,
+	// and the new addresses are mocked on these nonces
+	nonces := make(map[string]uint64)
+	for address, account := range initialState.Accounts {
+		nonces[address] = account.Nonce
+	}
+
+	// scenarios only call contracts, the calls to other accounts failed anyway
+	contracts := make(map[string]bool)
+	for address, account := range initialState.Accounts {
+		contracts[address] = len(account.Code) > 0
+	}
+
+	blockInfo := cloneBlockInfo(initialState.CurrentBlockInfo)
+	if blockInfo == nil {
+		blockInfo = &worldmock.BlockInfo{}
+	}
+
+	for _, entry := range entries {
+		switch entry.Kind {
+		case TransactionKindWorldConfig:
+			return nil, fmt.Errorf("%w: the configuration of world %s changed", ErrRecordingNotExportable, request.World)
+		case TransactionKindCreateAccount:
+			step, err := newScenarioAccountStep(entry)
+			if err != nil {
+				return nil, err
+			}
+
+			account := entry.Account
+			address := string(step.Accounts[0].Address.Value)
+			nonces[address] = account.Nonce
+			contracts[address] = false
+			scenario.Steps = append(scenario.Steps, step)
+			continue
+		}
+
+		if entry.Output == nil {
+			// the request was rejected before reaching the VM, the world did not change
+			continue
+		}
+
+		step, err := newScenarioTxStep(entry)
+		if err != nil {
+			return nil, err
+		}
+
+		isCall := step.Tx.Type == mj.ScCall || step.Tx.Type == mj.ScQuery
+		if isCall && !contracts[string(step.Tx.To.Value)] {
+			continue
+		}
+
+		if !entry.isInBlock(blockInfo) {
+			blockInfo = entry.blockInfo()
+			scenario.Steps = append(scenario.Steps, &mj.SetStateStep{
+				Comment:          fmt.Sprintf("block %d", blockInfo.BlockNonce),
+				CurrentBlockInfo: newScenarioBlockInfo(blockInfo),
+			})
+		}
+
+		succeeded := entry.Output.ReturnCode == vmcommon.Ok.String()
+		sender := string(step.Tx.From.Value)
+		if step.Tx.Type == mj.ScDeploy && succeeded {
+			setState.NewAddressMocks = append(setState.NewAddressMocks, &mj.NewAddressMock{
+				CreatorAddress: step.Tx.From,
+				CreatorNonce:   newScenarioUint64(nonces[sender]),
+				NewAddress:     step.Tx.To,
+			})
+			contracts[string(step.Tx.To.Value)] = true
+		}
+		if step.Tx.Type.HasSender() && succeeded {
+			nonces[sender]++
+		}
+
+		scenario.Steps = append(scenario.Steps, step)
+	}
+
+	if request.CheckState {
+		checkAccounts, err := newScenarioCheckAccounts(finalState.Accounts)
+		if err != nil {
+			return nil, err
+		}
+
+		scenario.Steps = append(scenario.Steps, &mj.CheckStateStep{
+			Comment:       "final state",
+			CheckAccounts: checkAccounts,
+		})
+	}
+
+	return scenario, nil
+}
+
+// newScenarioAccountStep sets an account created during the session, replacing any account at its address
+func newScenarioAccountStep(entry *TransactionLogEntry) (*mj.SetStateStep, error) {
+	address, err := fromHex(entry.Account.Address)
+	if err != nil {
+		return nil, err
+	}
+	balance, err := parseValue(entry.Account.Balance)
+	if err != nil {
+		return nil, err
+	}
+
+	account := &worldmock.Account{
+		Address:         address,
+		Nonce:           entry.Account.Nonce,
+		Balance:         balance,
+		BalanceDelta:    big.NewInt(0),
+		DeveloperReward: big.NewInt(0),
+		Storage:         make(map[string][]byte),
+	}
+	for _, update := range entry.Account.Storage {
+		key, err := fromHex(update.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := fromHex(update.Value)
+		if err != nil {
+			return nil, err
+		}
+		account.Storage[string(key)] = value
+	}
+
+	accounts := worldmock.NewAccountMap()
+	accounts.PutAccount(account)
+	scenAccounts, err := newScenarioAccounts(accounts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mj.SetStateStep{
+		Comment:  fmt.Sprintf("created account %s", entry.TxHash),
+		Accounts: scenAccounts,
+	}, nil
+}
+
+func (entry *TransactionLogEntry) blockInfo() *worldmock.BlockInfo {
+	return &worldmock.BlockInfo{
+		BlockTimestamp: entry.BlockTimestamp,
+		BlockNonce:     entry.BlockNonce,
+		BlockRound:     entry.BlockRound,
+		BlockEpoch:     entry.BlockEpoch,
+	}
+}
+
+func (entry *TransactionLogEntry) isInBlock(blockInfo *worldmock.BlockInfo) bool {
+	return entry.BlockTimestamp == blockInfo.BlockTimestamp &&
+		entry.BlockNonce == blockInfo.BlockNonce &&
+		entry.BlockRound == blockInfo.BlockRound &&
+		entry.BlockEpoch == blockInfo.BlockEpoch
+}
+
+// newScenarioTxStep converts an entry of the transaction log; the deployed address is kept as the receiver,
+// for the caller to turn it into a new address mock
+func newScenarioTxStep(entry *TransactionLogEntry) (*mj.TxStep, error) {
+	input := entry.Input
+	caller, err := fromHex(input.Caller)
+	if err != nil {
+		return nil, err
+	}
+	contract, err := fromHex(input.Contract)
+	if err != nil {
+		return nil, err
+	}
+	value, err := parseValue(input.Value)
+	if err != nil {
+		return nil, err
+	}
+	arguments, err := newScenarioArguments(input.Arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := &mj.Transaction{
+		From:      newScenarioBytes(caller, er.AddressHint),
+		To:        newScenarioBytes(contract, er.AddressHint),
+		EGLDValue: mj.JSONBigInt{Value: value, Original: value.String()},
+		Function:  input.Function,
+		Arguments: arguments,
+		GasLimit:  newScenarioUint64(input.GasLimit),
+		GasPrice:  newScenarioUint64(0),
+	}
+
+	for _, transfer := range input.ESDTTransfers {
+		transferValue, err := parseValue(transfer.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		tx.ESDTValue = append(tx.ESDTValue, &mj.ESDTTxData{
+			TokenIdentifier: newScenarioBytes([]byte(transfer.TokenIdentifier), er.StrHint),
+			Nonce:           newScenarioUint64(transfer.Nonce),
+			Value:           mj.JSONBigInt{Value: transferValue, Original: transferValue.String()},
+		})
+	}
+
+	switch {
+	case entry.Kind == TransactionKindQuery:
+		tx.Type = mj.ScQuery
+	case input.Function == vmhost.InitFunctionName:
+		tx.Type = mj.ScDeploy
+		code, expression, err := newScenarioCode(input)
+		if err != nil {
+			return nil, err
+		}
+		tx.Code = mj.JSONBytesFromString{Value: code, Original: expression}
+	case input.Function == vmhost.UpgradeFunctionName:
+		tx.Type = mj.ScCall
+		code, expression, err := newScenarioCode(input)
+		if err != nil {
+			return nil, err
+		}
+		codeMetadata, err := newScenarioArguments([]string{input.CodeMetadata})
+		if err != nil {
+			return nil, err
+		}
+		codeArgument := mj.JSONBytesFromTree{Value: code, Original: &oj.OJsonString{Value: expression}}
+		tx.Arguments = append([]mj.JSONBytesFromTree{codeArgument, codeMetadata[0]}, tx.Arguments...)
+	case entry.Kind == TransactionKindGateway && (len(input.Function) == 0 || isESDTTransferFunction(input.Function)):
+		tx.Type = mj.Transfer
+	default:
+		tx.Type = mj.ScCall
+	}
+
+	step := &mj.TxStep{
+		TxIdent: entry.TxHash,
+		Tx:      tx,
+	}
+	if tx.Type.IsSmartContractTx() {
+		step.ExpectedResult, err = newScenarioResult(entry.Output)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return step, nil
+}
+
+// newScenarioCode refers to the code file when the request had one
+func newScenarioCode(input *TransactionLogInput) ([]byte, string, error) {
+	code, err := fromHex(input.Code)
+	if err != nil {
+		return nil, "", err
+	}
+
+	expression := "0x" + input.Code
+	if len(input.CodePath) > 0 {
+		expression = "file:" + input.CodePath
+	}
+
+	return code, expression, nil
+}
+
+func newScenarioArguments(argumentsHex []string) ([]mj.JSONBytesFromTree, error) {
+	arguments := make([]mj.JSONBytesFromTree, 0, len(argumentsHex))
+	for _, argumentHex := range argumentsHex {
+		argument, err := fromHex(argumentHex)
+		if err != nil {
+			return nil, err
+		}
+
+		arguments = append(arguments, mj.JSONBytesFromTree{
+			Value:    argument,
+			Original: &oj.OJsonString{Value: scenarioExpression(argument, er.NoHint)},
+		})
+	}
+
+	return arguments, nil
+}
+
+// newScenarioResult expects the recorded result; the logs of failed executions are not checked,
+// since they hold the internal errors of the VM
+func newScenarioResult(output *TransactionLogOutput) (*mj.TransactionResult, error) {
+	returnCode, err := parseReturnCode(output.ReturnCode)
+	if err != nil {
+		return nil, err
+	}
+
+	out := mj.JSONCheckValueList{}
+	for _, valueHex := range output.ReturnData {
+		value, err := fromHex(valueHex)
+		if err != nil {
+			return nil, err
+		}
+		out.Values = append(out.Values, newScenarioCheckBytes(value, er.NoHint))
+	}
+
+	result := &mj.TransactionResult{
+		Out:     out,
+		Status:  mj.JSONCheckBigInt{Value: big.NewInt(int64(returnCode)), Original: strconv.Itoa(int(returnCode))},
+		Message: newScenarioCheckBytes([]byte(output.ReturnMessage), er.StrHint),
+		Gas:     mj.JSONCheckUint64Unspecified(),
+		Refund:  mj.JSONCheckBigIntUnspecified(),
+		Logs:    mj.LogList{IsStar: returnCode != vmcommon.Ok},
+	}
+	if result.Logs.IsStar {
+		return result, nil
+	}
+
+	for _, event := range output.Logs {
+		logEntry, err := newScenarioLogEntry(event)
+		if err != nil {
+			return nil, err
+		}
+		result.Logs.List = append(result.Logs.List, logEntry)
+	}
+
+	return result, nil
+}
+
+func newScenarioLogEntry(event *TransactionLogEvent) (*mj.LogEntry, error) {
+	address, err := fromHex(event.Address)
+	if err != nil {
+		return nil, err
+	}
+	data, err := fromHex(event.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	topics := mj.JSONCheckValueList{}
+	for _, topicHex := range event.Topics {
+		topic, err := fromHex(topicHex)
+		if err != nil {
+			return nil, err
+		}
+		topics.Values = append(topics.Values, newScenarioCheckBytes(topic, er.NoHint))
+	}
+
+	return &mj.LogEntry{
+		Address:  newScenarioCheckBytes(address, er.AddressHint),
+		Endpoint: newScenarioCheckBytes([]byte(event.Identifier), er.StrHint),
+		Topics:   topics,
+		Data:     newScenarioCheckBytes(data, er.NoHint),
+	}, nil
+}
+
+func parseReturnCode(name string) (vmcommon.ReturnCode, error) {
+	for returnCode := vmcommon.Ok; returnCode <= vmcommon.SimulateFailed; returnCode++ {
+		if returnCode.String() == name {
+			return returnCode, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown return code: %s", name)
+}
+
+// newScenarioAccounts converts the accounts of a world, sorted by address; the system account is left out,
+// the scenarios keep the token metadata with the tokens of the accounts
+func newScenarioAccounts(accounts worldmock.AccountMap) ([]*mj.Account, error) {
+	mockWorld := worldmock.NewMockWorld()
+	mockWorld.AcctMap = accounts
+
+	addresses := sortedAccountAddresses(accounts)
+	scenAccounts := make([]*mj.Account, 0, len(addresses))
+	for _, address := range addresses {
+		account := accounts[address]
+		scenAccount, err := scenarioexec.ConvertMockAccountToScenarioFormat(mockWorld, account)
+		if err != nil {
+			return nil, err
+		}
+
+		rewriteScenarioExpressions(scenAccount)
+		if len(account.Code) > 0 {
+			scenAccount.Code = mj.JSONBytesFromString{Value: account.Code, Original: "0x" + toHex(account.Code)}
+		}
+		scenAccounts = append(scenAccounts, scenAccount)
+	}
+
+	return scenAccounts, nil
+}
+
+// rewriteScenarioExpressions replaces the expressions meant to be read by people with ones that parse back
+func rewriteScenarioExpressions(scenAccount *mj.Account) {
+	scenAccount.Address = newScenarioBytes(scenAccount.Address.Value, er.AddressHint)
+	if len(scenAccount.Owner.Value) > 0 {
+		scenAccount.Owner = newScenarioBytes(scenAccount.Owner.Value, er.AddressHint)
+	}
+
+	for _, storage := range scenAccount.Storage {
+		storage.Key = newScenarioBytes(storage.Key.Value, er.StrHint)
+		storage.Value.Original = &oj.OJsonString{Value: scenarioExpression(storage.Value.Value, er.NoHint)}
+	}
+
+	for _, esdtData := range scenAccount.ESDTData {
+		for _, instance := range esdtData.Instances {
+			if len(instance.Creator.Value) > 0 {
+				instance.Creator = newScenarioBytes(instance.Creator.Value, er.AddressHint)
+			}
+			if len(instance.Hash.Value) > 0 {
+				instance.Hash = newScenarioBytes(instance.Hash.Value, er.NoHint)
+			}
+			if len(instance.Attributes.Value) > 0 {
+				instance.Attributes = newScenarioBytes(instance.Attributes.Value, er.NoHint)
+			}
+		}
+	}
+}
+
+// newScenarioCheckAccounts expects the balances, the storage and the tokens of the accounts of a world
+func newScenarioCheckAccounts(accounts worldmock.AccountMap) (*mj.CheckAccounts, error) {
+	scenAccounts, err := newScenarioAccounts(accounts)
+	if err != nil {
+		return nil, err
+	}
+
+	checkAccounts := &mj.CheckAccounts{}
+	for _, scenAccount := range scenAccounts {
+		checkAccount := &mj.CheckAccount{
+			Address:         scenAccount.Address,
+			Nonce:           scenarioCheckUint64Star(),
+			Balance:         mj.JSONCheckBigInt{Value: scenAccount.Balance.Value, Original: scenAccount.Balance.Original},
+			Username:        mj.JSONCheckBytesUnspecified(),
+			ExplicitStorage: true,
+			Code:            mj.JSONCheckBytesStar(),
+			Owner:           mj.JSONCheckBytesUnspecified(),
+			AsyncCallData:   mj.JSONCheckBytesUnspecified(),
+			DeveloperReward: mj.JSONCheckBigIntUnspecified(),
+		}
+
+		for _, storage := range scenAccount.Storage {
+			checkAccount.CheckStorage = append(checkAccount.CheckStorage, &mj.CheckStorageKeyValuePair{
+				Key:        storage.Key,
+				CheckValue: newScenarioCheckBytes(storage.Value.Value, er.NoHint),
+			})
+		}
+
+		for _, esdtData := range scenAccount.ESDTData {
+			checkESDTData := &mj.CheckESDTData{
+				TokenIdentifier: esdtData.TokenIdentifier,
+				Roles:           esdtData.Roles,
+				LastNonce:       scenarioCheckUint64Star(),
+				Frozen:          mj.JSONCheckUint64Unspecified(),
+			}
+			for _, instance := range esdtData.Instances {
+				checkInstance := mj.NewCheckESDTInstance()
+				checkInstance.Nonce = instance.Nonce
+				checkInstance.Balance = mj.JSONCheckBigInt{Value: instance.Balance.Value, Original: instance.Balance.Original}
+				if len(instance.Attributes.Value) > 0 {
+					checkInstance.Attributes = newScenarioCheckBytes(instance.Attributes.Value, er.NoHint)
+				}
+				checkESDTData.Instances = append(checkESDTData.Instances, checkInstance)
+			}
+			checkAccount.CheckESDTData = append(checkAccount.CheckESDTData, checkESDTData)
+		}
+
+		checkAccounts.Accounts = append(checkAccounts.Accounts, checkAccount)
+	}
+
+	return checkAccounts, nil
+}
+
+// scenarioCheckUint64Star accepts any value, the checks treat missing fields as zero.
+func scenarioCheckUint64Star() mj.JSONCheckUint64 {
+	return mj.JSONCheckUint64{IsStar: true, Original: "*"}
+}
+
+func newScenarioBlockInfo(blockInfo *worldmock.BlockInfo) *mj.BlockInfo {
+	if blockInfo == nil {
+		return nil
+	}
+
+	return &mj.BlockInfo{
+		BlockTimestamp: newScenarioUint64(blockInfo.BlockTimestamp),
+		BlockNonce:     newScenarioUint64(blockInfo.BlockNonce),
+		BlockRound:     newScenarioUint64(blockInfo.BlockRound),
+		BlockEpoch:     newScenarioUint64(uint64(blockInfo.BlockEpoch)),
+	}
+}
+
+func scenarioGasSchedule(gasScheduleName string) mj.GasSchedule {
+	switch gasScheduleName {
+	case "", GasScheduleDummy:
+		return mj.GasScheduleDummy
+	case GasScheduleV3:
+		return mj.GasScheduleV3
+	case GasScheduleV4:
+		return mj.GasScheduleV4
+	default:
+		return mj.GasScheduleDefault
+	}
+}
+
+func newScenarioUint64(value uint64) mj.JSONUint64 {
+	return mj.JSONUint64{Value: value, Original: strconv.FormatUint(value, 10)}
+}
+
+func newScenarioBytes(value []byte, hint er.ExprReconstructorHint) mj.JSONBytesFromString {
+	return mj.JSONBytesFromString{Value: value, Original: scenarioExpression(value, hint)}
+}
+
+func newScenarioCheckBytes(value []byte, hint er.ExprReconstructorHint) mj.JSONCheckBytes {
+	return mj.JSONCheckBytesReconstructed(value, scenarioExpression(value, hint))
+}
+
+// scenarioExpression uses the readable expression of a value when it parses back to the same bytes, and hex otherwise
+func scenarioExpression(value []byte, hint er.ExprReconstructorHint) string {
+	if len(value) == 0 {
+		return ""
+	}
+
+	exprReconstructor := er.ExprReconstructor{}
+	expression := exprReconstructor.Reconstruct(value, hint)
+	exprInterpreter := ei.ExprInterpreter{}
+	interpreted, err := exprInterpreter.InterpretString(expression)
+	if err == nil && bytes.Equal(interpreted, value) {
+		return expression
+	}
+
+	return "0x" + toHex(value)
+}
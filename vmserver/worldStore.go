@@ -0,0 +1,96 @@
+package vmserver
+
+import (
+	"fmt"
+	"os"
+	"path"
+)
+
+const (
+	// WorldStoreJSON keeps each world in a JSON file, rewritten on each change
+	WorldStoreJSON = "json"
+	// WorldStoreBolt keeps the worlds in an embedded key-value database, writing only the accounts changed by each request
+	WorldStoreBolt = "bolt"
+)
+
+// WorldStore keeps the state of the worlds of a database, and their snapshots
+type WorldStore interface {
+	// LoadWorld returns the stored world, or nil if the world was never stored
+	LoadWorld(worldID string) (*worldDataModel, error)
+	// StoreWorld replaces the stored state of the world; the data model is not changed afterwards, so the store may keep it
+	StoreWorld(dataModel *worldDataModel) error
+	// LoadSnapshot returns the world saved under the snapshot name, or nil if there is no such snapshot
+	LoadSnapshot(worldID string, snapshot string) (*worldDataModel, error)
+	// StoreSnapshot saves the world under the snapshot name, replacing any older snapshot with the same name
+	StoreSnapshot(snapshot string, dataModel *worldDataModel) error
+	// Close releases the resources of the store
+	Close() error
+}
+
+// jsonWorldStore is the world store of the JSON files, each world being rewritten as a whole
+type jsonWorldStore struct {
+	rootPath string
+}
+
+func newJSONWorldStore(rootPath string) *jsonWorldStore {
+	return &jsonWorldStore{rootPath: rootPath}
+}
+
+// LoadWorld returns the world read from its file, or nil if there is no file
+func (store *jsonWorldStore) LoadWorld(worldID string) (*worldDataModel, error) {
+	return store.readWorldDataModel(worldFilePath(store.rootPath, worldID))
+}
+
+// StoreWorld writes the world to its file
+func (store *jsonWorldStore) StoreWorld(dataModel *worldDataModel) error {
+	return marshalToFile(worldFilePath(store.rootPath, dataModel.ID), dataModel)
+}
+
+// LoadSnapshot returns the world read from the snapshot file, or nil if there is no file
+func (store *jsonWorldStore) LoadSnapshot(worldID string, snapshot string) (*worldDataModel, error) {
+	return store.readWorldDataModel(store.getSnapshotFile(worldID, snapshot))
+}
+
+// StoreSnapshot writes the world to the snapshot file, in the snapshots folder of the world
+func (store *jsonWorldStore) StoreSnapshot(snapshot string, dataModel *worldDataModel) error {
+	err := os.MkdirAll(store.getSnapshotsFolder(dataModel.ID), os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	return marshalToFile(store.getSnapshotFile(dataModel.ID, snapshot), dataModel)
+}
+
+// Close does nothing, the files are not kept open
+func (store *jsonWorldStore) Close() error {
+	return nil
+}
+
+// getSnapshotsFolder returns the folder holding the snapshots of a world, next to the world file
+func (store *jsonWorldStore) getSnapshotsFolder(worldID string) string {
+	return path.Join(store.rootPath, "worlds", fmt.Sprintf("%s.snapshots", worldID))
+}
+
+func (store *jsonWorldStore) getSnapshotFile(worldID string, snapshot string) string {
+	return path.Join(store.getSnapshotsFolder(worldID), fmt.Sprintf("%s.json", snapshot))
+}
+
+func (store *jsonWorldStore) readWorldDataModel(filePath string) (*worldDataModel, error) {
+	if !fileExists(filePath) {
+		return nil, nil
+	}
+
+	// worlds stored before block settings existed keep the default block time
+	dataModel := &worldDataModel{SecondsPerBlock: DefaultSecondsPerBlock}
+	err := unmarshalFromFile(filePath, dataModel)
+	if err != nil {
+		return nil, err
+	}
+
+	return dataModel, nil
+}
+
+// worldFilePath is the file of the world in the JSON store; it also identifies the world for the locks, the cache and the events
+func worldFilePath(rootPath string, worldID string) string {
+	return path.Join(rootPath, "worlds", fmt.Sprintf("%s.json", worldID))
+}
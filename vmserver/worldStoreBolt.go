@@ -0,0 +1,470 @@
+package vmserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/multiversx/mx-chain-core-go/core"
+	worldmock "github.com/multiversx/mx-chain-vm-v1_4-go/mock/world"
+	bolt "go.etcd.io/bbolt"
+)
+
+// boltWorldStoreFile is the file of the key-value store, in the folder of the database
+const boltWorldStoreFile = "worlds.db"
+
+// boltOpenTimeout bounds the wait for another process holding the store
+const boltOpenTimeout = time.Second
+
+var (
+	boltWorldsBucket    = []byte("worlds")
+	boltSnapshotsBucket = []byte("snapshots")
+	boltAccountsBucket  = []byte("accounts")
+	boltStorageBucket   = []byte("storage")
+	boltWorldFieldsKey  = []byte("world")
+)
+
+// boltWorldStore keeps the worlds in an embedded key-value database; each world is a bucket holding the world
+// settings, a record per account and a bucket of storage keys per account, so that only the changes are written.
+// The database file is locked by the store, so the worlds are only read once, then kept in memory as last written.
+type boltWorldStore struct {
+	db     *bolt.DB
+	mutex  sync.Mutex
+	worlds map[string]*worldDataModel
+}
+
+// boltAccountRecord is an account without its storage, which is kept key by key
+type boltAccountRecord struct {
+	*worldmock.Account
+	Storage map[string][]byte `json:",omitempty"`
+}
+
+func newBoltWorldStore(rootPath string) (*boltWorldStore, error) {
+	err := os.MkdirAll(rootPath, os.ModePerm)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := bolt.Open(path.Join(rootPath, boltWorldStoreFile), 0644, &bolt.Options{Timeout: boltOpenTimeout})
+	if err != nil {
+		return nil, err
+	}
+
+	return &boltWorldStore{
+		db:     db,
+		worlds: make(map[string]*worldDataModel),
+	}, nil
+}
+
+// LoadWorld returns a copy of the world kept in memory, reading it from its bucket the first time,
+// or nil if the world was never stored; the copy records its changes, for the next StoreWorld.
+// The copy clones every account with its storage, so each request pays in proportion to the size of the world
+// (see BenchmarkBoltWorldStore_LoadWorld); the VM changes the accounts in place, and a request that fails
+// before storing the world must not change the one kept in memory
+func (store *boltWorldStore) LoadWorld(worldID string) (*worldDataModel, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	dataModel, ok := store.worlds[worldID]
+	if !ok {
+		err := store.db.View(func(tx *bolt.Tx) error {
+			worldBucket := nestedBucket(tx.Bucket(boltWorldsBucket), []byte(worldID))
+			if worldBucket == nil {
+				return nil
+			}
+
+			var err error
+			dataModel, err = readWorldBucket(worldBucket)
+			return err
+		})
+		if err != nil || dataModel == nil {
+			return nil, err
+		}
+
+		store.worlds[worldID] = dataModel
+	}
+
+	loaded := dataModel.clone()
+	loaded.changes = newWorldChanges()
+	return loaded, nil
+}
+
+// StoreWorld writes the settings of the world and the accounts in its changes, or all the accounts that differ
+// from the stored ones if the world has no changes; the accounts of the data model are kept as the last written
+func (store *boltWorldStore) StoreWorld(dataModel *worldDataModel) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	err := store.db.Update(func(tx *bolt.Tx) error {
+		worldBucket, err := createNestedBucket(tx, boltWorldsBucket, []byte(dataModel.ID))
+		if err != nil {
+			return err
+		}
+
+		var numChanged int
+		if dataModel.changes != nil {
+			numChanged, err = writeWorldChanges(worldBucket, dataModel)
+		} else {
+			numChanged, err = writeWorldBucket(worldBucket, dataModel)
+		}
+		log.Trace("boltWorldStore.StoreWorld()", "world", dataModel.ID, "changed accounts", numChanged)
+		return err
+	})
+	if err != nil {
+		// the world is read again from its bucket, which the failed transaction left unchanged
+		delete(store.worlds, dataModel.ID)
+		return err
+	}
+
+	written := *dataModel
+	written.changes = nil
+	store.worlds[dataModel.ID] = &written
+	return nil
+}
+
+// LoadSnapshot returns the world read from the snapshot bucket, or nil if there is no such snapshot
+func (store *boltWorldStore) LoadSnapshot(worldID string, snapshot string) (*worldDataModel, error) {
+	var dataModel *worldDataModel
+	err := store.db.View(func(tx *bolt.Tx) error {
+		snapshotBucket := nestedBucket(tx.Bucket(boltSnapshotsBucket), []byte(worldID), []byte(snapshot))
+		if snapshotBucket == nil {
+			return nil
+		}
+
+		var err error
+		dataModel, err = readWorldBucket(snapshotBucket)
+		return err
+	})
+
+	return dataModel, err
+}
+
+// StoreSnapshot writes the world to the snapshot bucket, which only changes where the snapshots differ
+func (store *boltWorldStore) StoreSnapshot(snapshot string, dataModel *worldDataModel) error {
+	return store.db.Update(func(tx *bolt.Tx) error {
+		snapshotBucket, err := createNestedBucket(tx, boltSnapshotsBucket, []byte(dataModel.ID), []byte(snapshot))
+		if err != nil {
+			return err
+		}
+
+		_, err = writeWorldBucket(snapshotBucket, dataModel)
+		return err
+	})
+}
+
+// Close releases the database file
+func (store *boltWorldStore) Close() error {
+	return store.db.Close()
+}
+
+func nestedBucket(bucket *bolt.Bucket, names ...[]byte) *bolt.Bucket {
+	for _, name := range names {
+		if bucket == nil {
+			return nil
+		}
+		bucket = bucket.Bucket(name)
+	}
+
+	return bucket
+}
+
+func createNestedBucket(tx *bolt.Tx, rootName []byte, names ...[]byte) (*bolt.Bucket, error) {
+	bucket, err := tx.CreateBucketIfNotExists(rootName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range names {
+		bucket, err = bucket.CreateBucketIfNotExists(name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bucket, nil
+}
+
+// readWorldBucket reads a whole world, once per store; the values of the store are only valid during the transaction, so they are copied
+func readWorldBucket(worldBucket *bolt.Bucket) (*worldDataModel, error) {
+	dataModel := &worldDataModel{SecondsPerBlock: DefaultSecondsPerBlock}
+	err := json.Unmarshal(worldBucket.Get(boltWorldFieldsKey), (*worldDataModelFields)(dataModel))
+	if err != nil {
+		return nil, err
+	}
+
+	dataModel.Accounts = worldmock.NewAccountMap()
+	accountsBucket := worldBucket.Bucket(boltAccountsBucket)
+	storageBucket := worldBucket.Bucket(boltStorageBucket)
+	if accountsBucket == nil || storageBucket == nil {
+		return dataModel, nil
+	}
+
+	err = accountsBucket.ForEach(func(address []byte, record []byte) error {
+		account := &worldmock.Account{}
+		err := json.Unmarshal(record, &boltAccountRecord{Account: account})
+		if err != nil {
+			return err
+		}
+
+		account.Storage = make(map[string][]byte)
+		accountStorage := storageBucket.Bucket(address)
+		if accountStorage != nil {
+			err = accountStorage.ForEach(func(key []byte, value []byte) error {
+				account.Storage[string(key)] = append(make([]byte, 0, len(value)), value...)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		dataModel.Accounts.PutAccount(account)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dataModel, nil
+}
+
+// writeWorldBucket writes the world settings, then only the accounts and the storage keys differing from the stored ones;
+// it returns the number of accounts written or removed
+func writeWorldBucket(worldBucket *bolt.Bucket, dataModel *worldDataModel) (int, error) {
+	accountsBucket, storageBucket, err := writeWorldFields(worldBucket, dataModel)
+	if err != nil {
+		return 0, err
+	}
+
+	numChanged := 0
+	for address, account := range dataModel.Accounts {
+		record, err := json.Marshal(&boltAccountRecord{Account: account})
+		if err != nil {
+			return 0, err
+		}
+
+		recordChanged, err := putIfChanged(accountsBucket, []byte(address), record)
+		if err != nil {
+			return 0, err
+		}
+
+		accountStorage, err := storageBucket.CreateBucketIfNotExists([]byte(address))
+		if err != nil {
+			return 0, err
+		}
+
+		storageChanged, err := writeAccountStorage(accountStorage, account.Storage)
+		if err != nil {
+			return 0, err
+		}
+
+		if recordChanged || storageChanged {
+			numChanged++
+		}
+	}
+
+	removed := missingKeys(accountsBucket, func(address string) bool {
+		_, ok := dataModel.Accounts[address]
+		return ok
+	})
+	for _, address := range removed {
+		err = deleteAccount(accountsBucket, storageBucket, address)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return numChanged + len(removed), nil
+}
+
+// writeWorldChanges writes the world settings, then only the accounts in the changes of the world,
+// with the part of their storage that was changed; it returns the number of accounts written or removed
+func writeWorldChanges(worldBucket *bolt.Bucket, dataModel *worldDataModel) (int, error) {
+	accountsBucket, storageBucket, err := writeWorldFields(worldBucket, dataModel)
+	if err != nil {
+		return 0, err
+	}
+
+	for address, changes := range dataModel.changes.accounts {
+		account, ok := dataModel.Accounts[address]
+		if !ok {
+			err = deleteAccount(accountsBucket, storageBucket, []byte(address))
+			if err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		record, err := json.Marshal(&boltAccountRecord{Account: account})
+		if err != nil {
+			return 0, err
+		}
+
+		err = accountsBucket.Put([]byte(address), record)
+		if err != nil {
+			return 0, err
+		}
+
+		accountStorage, err := storageBucket.CreateBucketIfNotExists([]byte(address))
+		if err != nil {
+			return 0, err
+		}
+
+		err = writeStorageChanges(accountStorage, account.Storage, changes)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(dataModel.changes.accounts), nil
+}
+
+// writeWorldFields writes the world settings and returns the buckets of the accounts and of their storage
+func writeWorldFields(worldBucket *bolt.Bucket, dataModel *worldDataModel) (*bolt.Bucket, *bolt.Bucket, error) {
+	fields := worldDataModelFields(*dataModel)
+	fields.Accounts = nil
+	fieldsJSON, err := json.Marshal(&fields)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, err = putIfChanged(worldBucket, boltWorldFieldsKey, fieldsJSON)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	accountsBucket, err := worldBucket.CreateBucketIfNotExists(boltAccountsBucket)
+	if err != nil {
+		return nil, nil, err
+	}
+	storageBucket, err := worldBucket.CreateBucketIfNotExists(boltStorageBucket)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return accountsBucket, storageBucket, nil
+}
+
+func deleteAccount(accountsBucket *bolt.Bucket, storageBucket *bolt.Bucket, address []byte) error {
+	err := accountsBucket.Delete(address)
+	if err != nil {
+		return err
+	}
+
+	err = storageBucket.DeleteBucket(address)
+	if err != nil && err != bolt.ErrBucketNotFound {
+		return err
+	}
+
+	return nil
+}
+
+// writeStorageChanges writes the changed part of the storage of an account; the keys missing from the storage are deleted
+func writeStorageChanges(accountStorage *bolt.Bucket, storage map[string][]byte, changes *accountChanges) error {
+	if changes.allStorage {
+		_, err := writeAccountStorage(accountStorage, storage)
+		return err
+	}
+
+	for key := range changes.storageKeys {
+		value, ok := storage[key]
+		var err error
+		if ok {
+			err = accountStorage.Put([]byte(key), value)
+		} else {
+			err = accountStorage.Delete([]byte(key))
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if changes.protectedKeys {
+		return writeProtectedKeys(accountStorage, storage)
+	}
+
+	return nil
+}
+
+// writeProtectedKeys writes the keys with the protected prefix, where the builtin functions keep the tokens
+func writeProtectedKeys(accountStorage *bolt.Bucket, storage map[string][]byte) error {
+	for key, value := range storage {
+		if !strings.HasPrefix(key, core.ProtectedKeyPrefix) {
+			continue
+		}
+
+		_, err := putIfChanged(accountStorage, []byte(key), value)
+		if err != nil {
+			return err
+		}
+	}
+
+	prefix := []byte(core.ProtectedKeyPrefix)
+	removed := make([][]byte, 0)
+	cursor := accountStorage.Cursor()
+	for key, _ := cursor.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, _ = cursor.Next() {
+		_, ok := storage[string(key)]
+		if !ok {
+			removed = append(removed, append(make([]byte, 0, len(key)), key...))
+		}
+	}
+	for _, key := range removed {
+		err := accountStorage.Delete(key)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeAccountStorage(accountStorage *bolt.Bucket, storage map[string][]byte) (bool, error) {
+	changed := false
+	for key, value := range storage {
+		keyChanged, err := putIfChanged(accountStorage, []byte(key), value)
+		if err != nil {
+			return false, err
+		}
+
+		changed = changed || keyChanged
+	}
+
+	removed := missingKeys(accountStorage, func(key string) bool {
+		_, ok := storage[key]
+		return ok
+	})
+	for _, key := range removed {
+		err := accountStorage.Delete(key)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return changed || len(removed) > 0, nil
+}
+
+func putIfChanged(bucket *bolt.Bucket, key []byte, value []byte) (bool, error) {
+	stored := bucket.Get(key)
+	if stored != nil && bytes.Equal(stored, value) {
+		return false, nil
+	}
+
+	return true, bucket.Put(key, value)
+}
+
+// missingKeys returns the keys of the bucket that are no longer wanted; they cannot be deleted while iterating
+func missingKeys(bucket *bolt.Bucket, isWanted func(key string) bool) [][]byte {
+	missing := make([][]byte, 0)
+	_ = bucket.ForEach(func(key []byte, _ []byte) error {
+		if !isWanted(string(key)) {
+			missing = append(missing, append(make([]byte, 0, len(key)), key...))
+		}
+		return nil
+	})
+
+	return missing
+}
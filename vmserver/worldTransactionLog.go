@@ -0,0 +1,300 @@
+package vmserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	vmcommon "github.com/multiversx/mx-chain-vm-common-go"
+	worldmock "github.com/multiversx/mx-chain-vm-v1_4-go/mock/world"
+	"github.com/multiversx/mx-chain-vm-v1_4-go/vmhost"
+)
+
+// executedRequest is what the transaction log needs to know about an executed request
+type executedRequest struct {
+	txHash       string
+	kind         string
+	contract     []byte
+	function     string
+	gasLimit     uint64
+	code         []byte
+	codeMetadata []byte
+	codePath     string
+	input        *vmcommon.VMInput
+	output       *vmcommon.VMOutput
+	err          error
+}
+
+// newTransactionLogEntry describes an executed request; requests that do not come with a hash get a generated one
+func (w *world) newTransactionLogEntry(request *executedRequest) *TransactionLogEntry {
+	entry := w.newLogEntry(request)
+	entry.TxHash = request.txHash
+	if request.err != nil {
+		entry.Error = request.err.Error()
+	}
+	if request.output != nil {
+		entry.Output = newTransactionLogOutput(request.gasLimit, request.output)
+	}
+	if len(entry.TxHash) == 0 {
+		entry.TxHash = generateTxHash(w.id, entry)
+	}
+
+	return entry
+}
+
+// newAccountLogEntry describes an account created by CreateAccount
+func (w *world) newAccountLogEntry(account *worldmock.Account) *TransactionLogEntry {
+	entry := w.newLogEntry(&executedRequest{kind: TransactionKindCreateAccount, contract: account.Address})
+	entry.Account = &TransactionLogAccount{
+		Address: toHex(account.Address),
+		Nonce:   account.Nonce,
+		Balance: account.Balance.String(),
+		Storage: make([]*TransactionLogStorageUpdate, 0, len(account.Storage)),
+	}
+
+	keys := make([]string, 0, len(account.Storage))
+	for key := range account.Storage {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		entry.Account.Storage = append(entry.Account.Storage, &TransactionLogStorageUpdate{
+			Account: entry.Account.Address,
+			Key:     toHex([]byte(key)),
+			Value:   toHex(account.Storage[key]),
+		})
+	}
+
+	entry.TxHash = generateTxHash(w.id, entry)
+	return entry
+}
+
+// newWorldConfigLogEntry describes a configuration set by SetWorldConfig
+func (w *world) newWorldConfigLogEntry(config *WorldConfigResponse) *TransactionLogEntry {
+	entry := w.newLogEntry(&executedRequest{kind: TransactionKindWorldConfig})
+	entry.WorldConfig = &TransactionLogWorldConfig{
+		GasSchedule:  config.GasSchedule,
+		EnabledFlags: config.EnabledFlags,
+	}
+
+	entry.TxHash = generateTxHash(w.id, entry)
+	return entry
+}
+
+func (w *world) newLogEntry(request *executedRequest) *TransactionLogEntry {
+	entry := &TransactionLogEntry{
+		Kind:      request.kind,
+		Timestamp: time.Now().UTC(),
+		Input:     newTransactionLogInput(request),
+	}
+
+	blockInfo := w.blockchainHook.CurrentBlockInfo
+	if blockInfo != nil {
+		entry.BlockNonce = blockInfo.BlockNonce
+		entry.BlockRound = blockInfo.BlockRound
+		entry.BlockEpoch = blockInfo.BlockEpoch
+		entry.BlockTimestamp = blockInfo.BlockTimestamp
+	}
+
+	return entry
+}
+
+func newTransactionLogInput(request *executedRequest) *TransactionLogInput {
+	input := &TransactionLogInput{
+		Contract:      toHex(request.contract),
+		Function:      request.function,
+		Arguments:     make([]string, 0),
+		ESDTTransfers: make([]*TransactionLogESDTTransfer, 0),
+		GasLimit:      request.gasLimit,
+		Code:          toHex(request.code),
+		CodePath:      absolutePath(request.codePath),
+		CodeMetadata:  toHex(request.codeMetadata),
+	}
+
+	vmInput := request.input
+	if vmInput == nil {
+		return input
+	}
+
+	arguments := vmInput.Arguments
+	if request.function == vmhost.UpgradeFunctionName && len(arguments) >= 2 {
+		// upgrades pass the code and the code metadata as the first arguments
+		input.Code = toHex(arguments[0])
+		input.CodeMetadata = toHex(arguments[1])
+		arguments = arguments[2:]
+	}
+
+	input.Caller = toHex(vmInput.CallerAddr)
+	input.Arguments = toHexList(arguments)
+	input.GasPrice = vmInput.GasPrice
+	if vmInput.CallValue != nil {
+		input.Value = vmInput.CallValue.String()
+	}
+	for _, transfer := range vmInput.ESDTTransfers {
+		input.ESDTTransfers = append(input.ESDTTransfers, &TransactionLogESDTTransfer{
+			TokenIdentifier: string(transfer.ESDTTokenName),
+			Nonce:           transfer.ESDTTokenNonce,
+			Value:           transfer.ESDTValue.String(),
+		})
+	}
+
+	return input
+}
+
+func newTransactionLogOutput(gasLimit uint64, vmOutput *vmcommon.VMOutput) *TransactionLogOutput {
+	output := &TransactionLogOutput{
+		ReturnCode:     vmOutput.ReturnCode.String(),
+		ReturnMessage:  vmOutput.ReturnMessage,
+		ReturnData:     toHexList(vmOutput.ReturnData),
+		Logs:           make([]*TransactionLogEvent, 0, len(vmOutput.Logs)),
+		Transfers:      make([]*TransactionLogTransfer, 0),
+		StorageUpdates: make([]*TransactionLogStorageUpdate, 0),
+	}
+
+	if vmOutput.GasRemaining <= gasLimit {
+		output.GasUsed = gasLimit - vmOutput.GasRemaining
+	}
+
+	for _, logEntry := range vmOutput.Logs {
+		output.Logs = append(output.Logs, &TransactionLogEvent{
+			Address:    toHex(logEntry.Address),
+			Identifier: string(logEntry.Identifier),
+			Topics:     toHexList(logEntry.Topics),
+			Data:       toHex(logEntry.Data),
+		})
+	}
+
+	for _, outputAccount := range sortedOutputAccounts(vmOutput.OutputAccounts) {
+		for _, transfer := range outputAccount.OutputTransfers {
+			output.Transfers = append(output.Transfers, &TransactionLogTransfer{
+				Sender:   toHex(transfer.SenderAddress),
+				Receiver: toHex(outputAccount.Address),
+				Value:    transfer.Value.String(),
+				Data:     toHex(transfer.Data),
+				GasLimit: transfer.GasLimit,
+				CallType: transfer.CallType.ToString(),
+			})
+		}
+
+		for _, update := range sortedStorageUpdates(outputAccount.StorageUpdates) {
+			output.StorageUpdates = append(output.StorageUpdates, &TransactionLogStorageUpdate{
+				Account: toHex(outputAccount.Address),
+				Key:     toHex(update.Offset),
+				Value:   toHex(update.Data),
+			})
+		}
+	}
+
+	return output
+}
+
+func sortedOutputAccounts(outputAccounts map[string]*vmcommon.OutputAccount) []*vmcommon.OutputAccount {
+	accounts := make([]*vmcommon.OutputAccount, 0, len(outputAccounts))
+	for _, account := range outputAccounts {
+		accounts = append(accounts, account)
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return bytes.Compare(accounts[i].Address, accounts[j].Address) < 0
+	})
+
+	return accounts
+}
+
+func sortedStorageUpdates(storageUpdates map[string]*vmcommon.StorageUpdate) []*vmcommon.StorageUpdate {
+	updates := make([]*vmcommon.StorageUpdate, 0, len(storageUpdates))
+	for _, update := range storageUpdates {
+		updates = append(updates, update)
+	}
+	sort.Slice(updates, func(i, j int) bool {
+		return bytes.Compare(updates[i].Offset, updates[j].Offset) < 0
+	})
+
+	return updates
+}
+
+// absolutePath keeps the code files usable when the log is read from another folder
+func absolutePath(filePath string) string {
+	if len(filePath) == 0 {
+		return ""
+	}
+
+	absolute, err := filepath.Abs(filePath)
+	if err != nil {
+		return filePath
+	}
+
+	return absolute
+}
+
+func toHexList(values [][]byte) []string {
+	encoded := make([]string, len(values))
+	for i, value := range values {
+		encoded[i] = toHex(value)
+	}
+
+	return encoded
+}
+
+// generateTxHash hashes the entry together with the world and the time, so that repeated requests get different hashes
+func generateTxHash(worldID string, entry *TransactionLogEntry) string {
+	entryBytes, _ := json.Marshal(entry)
+	data := fmt.Sprintf("%s/%d/%s", worldID, time.Now().UnixNano(), entryBytes)
+	return toHex(worldmock.DefaultHasher.Compute(data))
+}
+
+// filterTransactionLog keeps the entries matching all the filters of the request;
+// the contract matches the receiver of the request and the contracts that logged events
+func filterTransactionLog(entries []*TransactionLogEntry, request TransactionLogRequest) []*TransactionLogEntry {
+	contract := strings.ToLower(request.ContractHex)
+	filtered := make([]*TransactionLogEntry, 0, len(entries))
+	for _, entry := range entries {
+		if len(contract) > 0 && !entry.involvesContract(contract) {
+			continue
+		}
+		if len(request.Function) > 0 && entry.Input.Function != request.Function {
+			continue
+		}
+		if len(request.EventIdentifier) > 0 && !entry.hasEvent(request.EventIdentifier) {
+			continue
+		}
+
+		filtered = append(filtered, entry)
+	}
+
+	return filtered
+}
+
+func (entry *TransactionLogEntry) involvesContract(contract string) bool {
+	if entry.Input.Contract == contract {
+		return true
+	}
+	if entry.Output == nil {
+		return false
+	}
+
+	for _, event := range entry.Output.Logs {
+		if event.Address == contract {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (entry *TransactionLogEntry) hasEvent(identifier string) bool {
+	if entry.Output == nil {
+		return false
+	}
+
+	for _, event := range entry.Output.Logs {
+		if event.Identifier == identifier {
+			return true
+		}
+	}
+
+	return false
+}